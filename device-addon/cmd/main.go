@@ -42,6 +42,7 @@ func main() {
 	cmd.AddCommand(addon.NewManagerCommand())
 	cmd.AddCommand(addon.NewAgentCommand())
 	cmd.AddCommand(device.NewDriverCommand())
+	cmd.AddCommand(device.NewFakeDriverCommand())
 
 	if err := cmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
@@ -0,0 +1,21 @@
+#!/usr/bin/env bash
+
+REPO_DIR="$(cd "$(dirname ${BASH_SOURCE[0]})/.." ; pwd -P)"
+MODULE="open-cluster-management-io/addon-contrib/device-addon"
+PROTO_DIR="${REPO_DIR}/pkg/device/drivers/remote/proto"
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+set -x
+
+GOBIN=${REPO_DIR}/bin
+
+# the protoc is required in the PATH
+protoc --plugin=protoc-gen-go=$GOBIN/protoc-gen-go \
+    --plugin=protoc-gen-go-grpc=$GOBIN/protoc-gen-go-grpc \
+    --go_out="${REPO_DIR}" --go_opt=module="${MODULE}" \
+    --go-grpc_out="${REPO_DIR}" --go-grpc_opt=module="${MODULE}" \
+    -I "${PROTO_DIR}" \
+    "${PROTO_DIR}/driver.proto"
@@ -0,0 +1,596 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/addon-framework/pkg/basecontroller/factory"
+	clusterinformerv1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterinformerv1beta1 "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1beta1"
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterlisterv1beta1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/addon/patcher"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	deviceclient "open-cluster-management-io/addon-contrib/device-addon/pkg/client/clientset/versioned"
+	deviceinformerv1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/client/informers/externalversions/apis/v1alpha1"
+	devicelisterv1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/client/listers/apis/v1alpha1"
+)
+
+const deviceSetFinalizer = "edge.open-cluster-management.io/deviceset-cleanup"
+
+const DeviceSetConditionApplied = "Applied"
+
+// parameterRegexp matches the parameter references in the templates, e.g. ${endpoint}
+var parameterRegexp = regexp.MustCompile(`\$\{([A-Za-z0-9_.-]+)\}`)
+
+// deviceSetsController creates the drivers and devices of a device set in the namespaces of the clusters that are
+// selected by its placement, and deletes them once the clusters are not selected
+type deviceSetsController struct {
+	client         deviceclient.Interface
+	lister         devicelisterv1alpha1.DeviceSetLister
+	deviceLister   devicelisterv1alpha1.DeviceLister
+	driverLister   devicelisterv1alpha1.DriverLister
+	clusterLister  clusterlisterv1.ManagedClusterLister
+	decisionLister clusterlisterv1beta1.PlacementDecisionLister
+}
+
+func NewDeviceSetsController(
+	client deviceclient.Interface,
+	deviceSetInformer deviceinformerv1alpha1.DeviceSetInformer,
+	deviceInformer deviceinformerv1alpha1.DeviceInformer,
+	driverInformer deviceinformerv1alpha1.DriverInformer,
+	clusterInformer clusterinformerv1.ManagedClusterInformer,
+	decisionInformer clusterinformerv1beta1.PlacementDecisionInformer,
+) factory.Controller {
+	c := &deviceSetsController{
+		client:         client,
+		lister:         deviceSetInformer.Lister(),
+		deviceLister:   deviceInformer.Lister(),
+		driverLister:   driverInformer.Lister(),
+		clusterLister:  clusterInformer.Lister(),
+		decisionLister: decisionInformer.Lister(),
+	}
+
+	return factory.New().
+		WithInformersQueueKeysFunc(func(obj runtime.Object) []string {
+			key, _ := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+			return []string{key}
+		}, deviceSetInformer.Informer()).
+		WithInformersQueueKeysFunc(c.ownerQueueKeys, deviceInformer.Informer(), driverInformer.Informer()).
+		WithInformersQueueKeysFunc(c.decisionQueueKeys, decisionInformer.Informer()).
+		WithInformersQueueKeysFunc(c.clusterQueueKeys, clusterInformer.Informer()).
+		WithSync(c.sync).
+		ToController("deviceset-controller")
+}
+
+func (c *deviceSetsController) sync(ctx context.Context, syncCtx factory.SyncContext, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		// ignore device set whose key is invalid
+		klog.Warningf("device set key %s is invalid, %v", key, err)
+		return nil
+	}
+
+	klog.Infof("sync device set %s/%s", namespace, name)
+
+	deviceSet, err := c.lister.DeviceSets(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		// clean up the drivers and devices if the finalizer is removed by others
+		_, err := c.cleanup(ctx, namespace, name, map[string]sets.Set[string]{}, map[string]sets.Set[string]{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	deviceSetPatcher := patcher.NewPatcher[*v1alpha1.DeviceSet, v1alpha1.DeviceSetSpec, v1alpha1.DeviceSetStatus](
+		c.client.EdgeV1alpha1().DeviceSets(namespace))
+
+	if !deviceSet.DeletionTimestamp.IsZero() {
+		remaining, err := c.cleanup(ctx, namespace, name, map[string]sets.Set[string]{}, map[string]sets.Set[string]{})
+		if err != nil {
+			return err
+		}
+
+		if remaining {
+			// wait for the devices are deleted by the agents
+			return nil
+		}
+
+		return deviceSetPatcher.RemoveFinalizer(ctx, deviceSet, deviceSetFinalizer)
+	}
+
+	updated, err := deviceSetPatcher.AddFinalizer(ctx, deviceSet, deviceSetFinalizer)
+	if err != nil || updated {
+		return err
+	}
+
+	clusters, err := c.decidedClusters(deviceSet)
+	if err != nil {
+		return err
+	}
+
+	// the names of the drivers and devices that are kept in each cluster
+	keptDrivers := map[string]sets.Set[string]{}
+	keptDevices := map[string]sets.Set[string]{}
+	failed := []string{}
+	for _, clusterName := range clusters {
+		drivers, devices, err := c.applyCluster(ctx, deviceSet, clusterName)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", clusterName, err))
+		}
+
+		keptDrivers[clusterName] = drivers
+		keptDevices[clusterName] = devices
+	}
+
+	if _, err := c.cleanup(ctx, namespace, name, keptDrivers, keptDevices); err != nil {
+		return err
+	}
+
+	appliedCondition := metav1.Condition{
+		Type:    DeviceSetConditionApplied,
+		Status:  metav1.ConditionTrue,
+		Reason:  "DeviceSetApplied",
+		Message: fmt.Sprintf("The drivers and devices are applied to %d clusters", len(clusters)),
+	}
+	if len(failed) != 0 {
+		sort.Strings(failed)
+		appliedCondition.Status = metav1.ConditionFalse
+		appliedCondition.Reason = "DeviceSetNotApplied"
+		appliedCondition.Message = fmt.Sprintf("Failed to apply the drivers and devices to %d clusters, %s",
+			len(failed), strings.Join(failed, "; "))
+	}
+
+	newDeviceSet := deviceSet.DeepCopy()
+	newDeviceSet.Status.Clusters = int32(len(clusters))
+	newDeviceSet.Status.AppliedClusters = int32(len(clusters) - len(failed))
+	meta.SetStatusCondition(&newDeviceSet.Status.Conditions, appliedCondition)
+
+	_, err = deviceSetPatcher.PatchStatus(ctx, newDeviceSet, newDeviceSet.Status, deviceSet.Status)
+	return err
+}
+
+// decidedClusters returns the clusters that are selected by the placement of the device set
+func (c *deviceSetsController) decidedClusters(deviceSet *v1alpha1.DeviceSet) ([]string, error) {
+	selector := labels.SelectorFromSet(labels.Set{
+		clusterv1beta1.PlacementLabel: deviceSet.Spec.PlacementRef.Name,
+	})
+	decisions, err := c.decisionLister.PlacementDecisions(deviceSet.Namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := sets.New[string]()
+	for _, decision := range decisions {
+		for _, d := range decision.Status.Decisions {
+			clusters.Insert(d.ClusterName)
+		}
+	}
+
+	return sets.List(clusters), nil
+}
+
+// applyCluster creates or updates the drivers and devices of the device set in the cluster namespace, it returns
+// the names of the drivers and devices that should be kept in the cluster
+func (c *deviceSetsController) applyCluster(ctx context.Context, deviceSet *v1alpha1.DeviceSet,
+	clusterName string) (sets.Set[string], sets.Set[string], error) {
+	// keep all of the drivers and devices in the cluster if its templates cannot be rendered
+	keepAll := func(err error) (sets.Set[string], sets.Set[string], error) {
+		return nil, nil, err
+	}
+
+	cluster, err := c.clusterLister.Get(clusterName)
+	if err != nil {
+		return keepAll(fmt.Errorf("failed to get the managed cluster, %v", err))
+	}
+
+	params, err := toParameters(deviceSet.Spec.Parameters, cluster)
+	if err != nil {
+		return keepAll(err)
+	}
+
+	drivers := []*v1alpha1.Driver{}
+	for i := range deviceSet.Spec.Drivers {
+		driver := &v1alpha1.Driver{}
+		if err := render(&deviceSet.Spec.Drivers[i], params, &driver.ObjectMeta, &driver.Spec); err != nil {
+			return keepAll(fmt.Errorf("failed to render the driver %s, %v", deviceSet.Spec.Drivers[i].Name, err))
+		}
+		drivers = append(drivers, driver)
+	}
+
+	devices := []*v1alpha1.Device{}
+	for i := range deviceSet.Spec.Devices {
+		device := &v1alpha1.Device{}
+		if err := render(&deviceSet.Spec.Devices[i], params, &device.ObjectMeta, &device.Spec); err != nil {
+			return keepAll(fmt.Errorf("failed to render the device %s, %v", deviceSet.Spec.Devices[i].Name, err))
+		}
+		devices = append(devices, device)
+	}
+
+	ownerLabels := map[string]string{
+		v1alpha1.DeviceSetNamespaceLabel: deviceSet.Namespace,
+		v1alpha1.DeviceSetNameLabel:      deviceSet.Name,
+	}
+
+	keptDrivers := sets.New[string]()
+	keptDevices := sets.New[string]()
+	errs := []string{}
+	for _, driver := range drivers {
+		keptDrivers.Insert(driver.Name)
+		driver.Namespace = clusterName
+		driver.Labels = ownerLabels
+		if err := c.applyDriver(ctx, driver); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	for _, device := range devices {
+		keptDevices.Insert(device.Name)
+		device.Namespace = clusterName
+		device.Labels = ownerLabels
+		if err := c.applyDevice(ctx, device); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) != 0 {
+		return keptDrivers, keptDevices, fmt.Errorf("%s", strings.Join(errs, ", "))
+	}
+
+	return keptDrivers, keptDevices, nil
+}
+
+func (c *deviceSetsController) applyDriver(ctx context.Context, required *v1alpha1.Driver) error {
+	existing, err := c.driverLister.Drivers(required.Namespace).Get(required.Name)
+	if errors.IsNotFound(err) {
+		_, err := c.client.EdgeV1alpha1().Drivers(required.Namespace).Create(ctx, required, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if !isOwnedBy(existing.Labels, required.Labels) {
+		return fmt.Errorf("the driver %s is not created by the device set", required.Name)
+	}
+
+	if equality.Semantic.DeepEqual(existing.Spec, required.Spec) {
+		return nil
+	}
+
+	updated := existing.DeepCopy()
+	updated.Spec = required.Spec
+	_, err = c.client.EdgeV1alpha1().Drivers(required.Namespace).Update(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *deviceSetsController) applyDevice(ctx context.Context, required *v1alpha1.Device) error {
+	existing, err := c.deviceLister.Devices(required.Namespace).Get(required.Name)
+	if errors.IsNotFound(err) {
+		_, err := c.client.EdgeV1alpha1().Devices(required.Namespace).Create(ctx, required, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if !isOwnedBy(existing.Labels, required.Labels) {
+		return fmt.Errorf("the device %s is not created by the device set", required.Name)
+	}
+
+	if equality.Semantic.DeepEqual(existing.Spec, required.Spec) {
+		return nil
+	}
+
+	updated := existing.DeepCopy()
+	updated.Spec = required.Spec
+	_, err = c.client.EdgeV1alpha1().Devices(required.Namespace).Update(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+// cleanup deletes the drivers and devices of the device set that are not kept, the drivers and devices of a
+// cluster are all kept if the kept names of the cluster are nil. It returns true if there are the drivers or
+// devices that are waited to be deleted by the agents of the available clusters.
+func (c *deviceSetsController) cleanup(ctx context.Context, namespace, name string,
+	keptDrivers, keptDevices map[string]sets.Set[string]) (bool, error) {
+	selector := labels.SelectorFromSet(labels.Set{
+		v1alpha1.DeviceSetNamespaceLabel: namespace,
+		v1alpha1.DeviceSetNameLabel:      name,
+	})
+
+	isKept := func(kept map[string]sets.Set[string], clusterName, name string) bool {
+		names, ok := kept[clusterName]
+		return ok && (names == nil || names.Has(name))
+	}
+
+	remaining := false
+	// the drivers are deleted after the devices are removed by the agents, so the agents can remove the devices
+	// from their drivers
+	devicesRemaining := sets.New[string]()
+	devices, err := c.deviceLister.List(selector)
+	if err != nil {
+		return false, err
+	}
+	for _, device := range devices {
+		if isKept(keptDevices, device.Namespace, device.Name) {
+			continue
+		}
+
+		if device.DeletionTimestamp.IsZero() {
+			klog.Infof("Delete the device %s/%s of device set %s/%s", device.Namespace, device.Name, namespace, name)
+			err := c.client.EdgeV1alpha1().Devices(device.Namespace).Delete(ctx, device.Name, metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return false, err
+			}
+		}
+
+		wait, err := c.waitForAgent(ctx, device.Namespace, func() error {
+			return patcher.NewPatcher[*v1alpha1.Device, v1alpha1.DeviceSpec, v1alpha1.DeviceStatus](
+				c.client.EdgeV1alpha1().Devices(device.Namespace)).RemoveFinalizer(ctx, device, v1alpha1.DeviceFinalizer)
+		})
+		if err != nil {
+			return false, err
+		}
+		if wait {
+			remaining = true
+			devicesRemaining.Insert(device.Namespace)
+		}
+	}
+
+	drivers, err := c.driverLister.List(selector)
+	if err != nil {
+		return false, err
+	}
+	for _, driver := range drivers {
+		if isKept(keptDrivers, driver.Namespace, driver.Name) || devicesRemaining.Has(driver.Namespace) {
+			continue
+		}
+
+		if driver.DeletionTimestamp.IsZero() {
+			klog.Infof("Delete the driver %s/%s of device set %s/%s", driver.Namespace, driver.Name, namespace, name)
+			err := c.client.EdgeV1alpha1().Drivers(driver.Namespace).Delete(ctx, driver.Name, metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return false, err
+			}
+		}
+
+		wait, err := c.waitForAgent(ctx, driver.Namespace, func() error {
+			return patcher.NewPatcher[*v1alpha1.Driver, v1alpha1.DriverSpec, v1alpha1.DriverStatus](
+				c.client.EdgeV1alpha1().Drivers(driver.Namespace)).RemoveFinalizer(ctx, driver, v1alpha1.DriverFinalizer)
+		})
+		if err != nil {
+			return false, err
+		}
+		remaining = remaining || wait
+	}
+
+	return remaining, nil
+}
+
+// waitForAgent returns true if the deleted driver or device should be waited until it is cleaned up by the agent
+// of the cluster. The agent of a deleted cluster never cleans it up, so its finalizer is removed by the
+// removeFinalizer, and the one of an unavailable cluster is not waited, it is cleaned up once the agent is back.
+func (c *deviceSetsController) waitForAgent(ctx context.Context, clusterName string, removeFinalizer func() error) (bool, error) {
+	cluster, err := c.clusterLister.Get(clusterName)
+	switch {
+	case errors.IsNotFound(err):
+		if err := removeFinalizer(); err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	if !cluster.DeletionTimestamp.IsZero() {
+		if err := removeFinalizer(); err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+		return false, nil
+	}
+
+	return meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable), nil
+}
+
+// ownerQueueKeys returns the key of the device set that creates the driver or device
+func (c *deviceSetsController) ownerQueueKeys(obj runtime.Object) []string {
+	accessor, err := accessorOf(obj)
+	if err != nil {
+		return []string{}
+	}
+
+	namespace, ok := accessor.GetLabels()[v1alpha1.DeviceSetNamespaceLabel]
+	if !ok {
+		return []string{}
+	}
+
+	name, ok := accessor.GetLabels()[v1alpha1.DeviceSetNameLabel]
+	if !ok {
+		return []string{}
+	}
+
+	return []string{fmt.Sprintf("%s/%s", namespace, name)}
+}
+
+// decisionQueueKeys returns the keys of the device sets that reference the placement of the decision
+func (c *deviceSetsController) decisionQueueKeys(obj runtime.Object) []string {
+	accessor, err := accessorOf(obj)
+	if err != nil {
+		return []string{}
+	}
+
+	placementName, ok := accessor.GetLabels()[clusterv1beta1.PlacementLabel]
+	if !ok {
+		return []string{}
+	}
+
+	deviceSets, err := c.lister.DeviceSets(accessor.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list device sets, %v", err)
+		return []string{}
+	}
+
+	keys := []string{}
+	for _, deviceSet := range deviceSets {
+		if deviceSet.Spec.PlacementRef.Name != placementName {
+			continue
+		}
+
+		key, _ := cache.MetaNamespaceKeyFunc(deviceSet)
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// clusterQueueKeys returns the keys of the device sets whose placements select the cluster, the parameters may be
+// changed with the cluster, and the keys of the device sets that create the drivers and devices in the cluster,
+// they are cleaned up once the cluster is unavailable or deleted
+func (c *deviceSetsController) clusterQueueKeys(obj runtime.Object) []string {
+	accessor, err := accessorOf(obj)
+	if err != nil {
+		return []string{}
+	}
+	clusterName := accessor.GetName()
+
+	keys := sets.New[string]()
+	decisions, err := c.decisionLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list placement decisions, %v", err)
+		return []string{}
+	}
+	for _, decision := range decisions {
+		for _, d := range decision.Status.Decisions {
+			if d.ClusterName == clusterName {
+				keys.Insert(c.decisionQueueKeys(decision)...)
+				break
+			}
+		}
+	}
+
+	devices, err := c.deviceLister.Devices(clusterName).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list devices, %v", err)
+		return []string{}
+	}
+	for _, device := range devices {
+		keys.Insert(c.ownerQueueKeys(device)...)
+	}
+
+	drivers, err := c.driverLister.Drivers(clusterName).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list drivers, %v", err)
+		return []string{}
+	}
+	for _, driver := range drivers {
+		keys.Insert(c.ownerQueueKeys(driver)...)
+	}
+
+	return sets.List(keys)
+}
+
+// accessorOf returns the accessor of an object, the object is unwrapped if it is the tombstone of a deleted object
+func accessorOf(obj interface{}) (metav1.Object, error) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	return meta.Accessor(obj)
+}
+
+func isOwnedBy(objLabels, ownerLabels map[string]string) bool {
+	for key, value := range ownerLabels {
+		if objLabels[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// toParameters returns the parameter values of the cluster, the value of a parameter is taken from the cluster
+// claim, the cluster label and the default value in order
+func toParameters(parameters []v1alpha1.TemplateParameter, cluster *clusterv1.ManagedCluster) (map[string]string, error) {
+	claims := map[string]string{}
+	for _, claim := range cluster.Status.ClusterClaims {
+		claims[claim.Name] = claim.Value
+	}
+
+	params := map[string]string{
+		v1alpha1.ClusterNameParameter: cluster.Name,
+	}
+	for _, param := range parameters {
+		if value, ok := claims[param.ClusterClaim]; ok && len(param.ClusterClaim) != 0 {
+			params[param.Name] = value
+			continue
+		}
+
+		if value, ok := cluster.Labels[param.ClusterLabel]; ok && len(param.ClusterLabel) != 0 {
+			params[param.Name] = value
+			continue
+		}
+
+		if len(param.Default) != 0 {
+			params[param.Name] = param.Default
+			continue
+		}
+
+		return nil, fmt.Errorf("there is no value of the parameter %s", param.Name)
+	}
+
+	return params, nil
+}
+
+// render substitutes the parameters in the template and decodes the name and spec of the template into the
+// object meta and spec
+func render(template interface{}, params map[string]string, objectMeta *metav1.ObjectMeta, spec interface{}) error {
+	data, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+
+	missing := sets.New[string]()
+	rendered := parameterRegexp.ReplaceAllStringFunc(string(data), func(ref string) string {
+		name := parameterRegexp.FindStringSubmatch(ref)[1]
+		value, ok := params[name]
+		if !ok {
+			missing.Insert(name)
+			return ref
+		}
+
+		// escape the value in the json string
+		escaped, _ := json.Marshal(value)
+		return string(escaped[1 : len(escaped)-1])
+	})
+	if missing.Len() != 0 {
+		return fmt.Errorf("the parameters %s are not defined", strings.Join(sets.List(missing), ", "))
+	}
+
+	obj := struct {
+		Name string          `json:"name"`
+		Spec json.RawMessage `json:"spec"`
+	}{}
+	if err := json.Unmarshal([]byte(rendered), &obj); err != nil {
+		return err
+	}
+
+	objectMeta.Name = obj.Name
+	return json.Unmarshal(obj.Spec, spec)
+}
@@ -0,0 +1,236 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	clusterlisterv1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
+	clusterlisterv1beta1 "open-cluster-management.io/api/client/cluster/listers/cluster/v1beta1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	fakedeviceclient "open-cluster-management-io/addon-contrib/device-addon/pkg/client/clientset/versioned/fake"
+	devicelisterv1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/client/listers/apis/v1alpha1"
+)
+
+func newCluster(name string, available bool) *clusterv1.ManagedCluster {
+	status := metav1.ConditionFalse
+	if available {
+		status = metav1.ConditionTrue
+	}
+
+	return &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: clusterv1.ManagedClusterStatus{
+			Conditions: []metav1.Condition{{Type: clusterv1.ManagedClusterConditionAvailable, Status: status}},
+		},
+	}
+}
+
+func newDeletingDevice(clusterName string) *v1alpha1.Device {
+	now := metav1.NewTime(time.Now())
+	return &v1alpha1.Device{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "sensor",
+			Namespace:         clusterName,
+			DeletionTimestamp: &now,
+			Finalizers:        []string{v1alpha1.DeviceFinalizer},
+			Labels: map[string]string{
+				v1alpha1.DeviceSetNamespaceLabel: "default",
+				v1alpha1.DeviceSetNameLabel:      "sensors",
+			},
+		},
+	}
+}
+
+func newTestController(t *testing.T, objs ...interface{}) (*deviceSetsController, *fakedeviceclient.Clientset) {
+	deviceSetStore := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	deviceStore := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	driverStore := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	clusterStore := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	decisionStore := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+
+	client := fakedeviceclient.NewSimpleClientset()
+	for _, obj := range objs {
+		var err error
+		switch o := obj.(type) {
+		case *v1alpha1.DeviceSet:
+			err = deviceSetStore.Add(o)
+		case *v1alpha1.Device:
+			err = deviceStore.Add(o)
+			if err == nil {
+				err = client.Tracker().Add(o)
+			}
+		case *v1alpha1.Driver:
+			err = driverStore.Add(o)
+			if err == nil {
+				err = client.Tracker().Add(o)
+			}
+		case *clusterv1.ManagedCluster:
+			err = clusterStore.Add(o)
+		case *clusterv1beta1.PlacementDecision:
+			err = decisionStore.Add(o)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return &deviceSetsController{
+		client:         client,
+		lister:         devicelisterv1alpha1.NewDeviceSetLister(deviceSetStore),
+		deviceLister:   devicelisterv1alpha1.NewDeviceLister(deviceStore),
+		driverLister:   devicelisterv1alpha1.NewDriverLister(driverStore),
+		clusterLister:  clusterlisterv1.NewManagedClusterLister(clusterStore),
+		decisionLister: clusterlisterv1beta1.NewPlacementDecisionLister(decisionStore),
+	}, client
+}
+
+func TestCleanup(t *testing.T) {
+	cases := []struct {
+		name                  string
+		cluster               *clusterv1.ManagedCluster
+		expectedRemaining     bool
+		expectedPatched       bool
+		expectedDriverDeleted bool
+	}{
+		{
+			// the driver is deleted after the agent removes the device
+			name:              "available cluster",
+			cluster:           newCluster("cluster1", true),
+			expectedRemaining: true,
+		},
+		{
+			name:                  "unavailable cluster",
+			cluster:               newCluster("cluster1", false),
+			expectedDriverDeleted: true,
+		},
+		{
+			name:                  "deleted cluster",
+			expectedPatched:       true,
+			expectedDriverDeleted: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			objs := []interface{}{newDeletingDevice("cluster1"), &v1alpha1.Driver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "modbus",
+					Namespace: "cluster1",
+					Labels: map[string]string{
+						v1alpha1.DeviceSetNamespaceLabel: "default",
+						v1alpha1.DeviceSetNameLabel:      "sensors",
+					},
+				},
+			}}
+			if c.cluster != nil {
+				objs = append(objs, c.cluster)
+			}
+			controller, client := newTestController(t, objs...)
+
+			remaining, err := controller.cleanup(context.TODO(), "default", "sensors",
+				map[string]sets.Set[string]{}, map[string]sets.Set[string]{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if remaining != c.expectedRemaining {
+				t.Errorf("expected remaining %v, but got %v", c.expectedRemaining, remaining)
+			}
+
+			patched, driverDeleted := false, false
+			for _, action := range client.Actions() {
+				if _, ok := action.(clienttesting.PatchAction); ok {
+					patched = true
+				}
+				if action.Matches("delete", "drivers") {
+					driverDeleted = true
+				}
+			}
+			if patched != c.expectedPatched {
+				t.Errorf("expected the finalizer removed %v, but got the actions %v", c.expectedPatched, client.Actions())
+			}
+			if driverDeleted != c.expectedDriverDeleted {
+				t.Errorf("expected the driver deleted %v, but got the actions %v", c.expectedDriverDeleted, client.Actions())
+			}
+		})
+	}
+}
+
+func TestClusterQueueKeys(t *testing.T) {
+	decision := &clusterv1beta1.PlacementDecision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "placement1-decision-1",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1beta1.PlacementLabel: "placement1"},
+		},
+		Status: clusterv1beta1.PlacementDecisionStatus{
+			Decisions: []clusterv1beta1.ClusterDecision{{ClusterName: "cluster1"}},
+		},
+	}
+	newDeviceSet := func(name, placementName string) *v1alpha1.DeviceSet {
+		return &v1alpha1.DeviceSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       v1alpha1.DeviceSetSpec{PlacementRef: v1alpha1.PlacementRef{Name: placementName}},
+		}
+	}
+
+	controller, _ := newTestController(t,
+		decision,
+		newDeviceSet("selected", "placement1"),
+		newDeviceSet("unselected", "placement2"),
+		newDeletingDevice("cluster2"),
+	)
+
+	cases := []struct {
+		clusterName string
+		expected    []string
+	}{
+		{clusterName: "cluster1", expected: []string{"default/selected"}},
+		{clusterName: "cluster2", expected: []string{"default/sensors"}},
+		{clusterName: "cluster3", expected: []string{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.clusterName, func(t *testing.T) {
+			keys := controller.clusterQueueKeys(newCluster(c.clusterName, true))
+			if !sets.New(keys...).Equal(sets.New(c.expected...)) {
+				t.Errorf("expected keys %v, but got %v", c.expected, keys)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	template := &v1alpha1.DriverTemplate{
+		Name: "opcua-${clusterName}",
+		Spec: v1alpha1.DriverSpec{DriverConfig: v1alpha1.DriverConfig{
+			DriverType: "opcua",
+			Properties: v1alpha1.Values{Data: map[string]interface{}{"endpoint": "${endpoint}"}},
+		}},
+	}
+
+	driver := &v1alpha1.Driver{}
+	if err := render(template, map[string]string{"clusterName": "cluster1", "endpoint": "opc.tcp://plc:4840"},
+		&driver.ObjectMeta, &driver.Spec); err != nil {
+		t.Fatal(err)
+	}
+
+	if driver.Name != "opcua-cluster1" {
+		t.Errorf("expected the name opcua-cluster1, but got %s", driver.Name)
+	}
+	if driver.Spec.Properties.Data["endpoint"] != "opc.tcp://plc:4840" {
+		t.Errorf("expected the rendered endpoint, but got %v", driver.Spec.Properties.Data)
+	}
+
+	if err := render(template, map[string]string{"clusterName": "cluster1"}, &driver.ObjectMeta, &driver.Spec); err == nil {
+		t.Errorf("expected the undefined parameter is rejected")
+	}
+}
@@ -5,10 +5,16 @@ import (
 	"embed"
 	"encoding/json"
 	"fmt"
+	"time"
 
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/addon/hub/controllers"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/addon/hub/webhook"
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
 
+	"github.com/spf13/pflag"
+
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -18,12 +24,16 @@ import (
 	"k8s.io/klog/v2"
 
 	deviceaddonclientset "open-cluster-management-io/addon-contrib/device-addon/pkg/client/clientset/versioned"
+	deviceaddoninformers "open-cluster-management-io/addon-contrib/device-addon/pkg/client/informers/externalversions"
 
 	"open-cluster-management.io/addon-framework/pkg/addonfactory"
 	"open-cluster-management.io/addon-framework/pkg/addonmanager"
 	"open-cluster-management.io/addon-framework/pkg/agent"
 	"open-cluster-management.io/addon-framework/pkg/utils"
 	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonclientset "open-cluster-management.io/api/client/addon/clientset/versioned"
+	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	workv1 "open-cluster-management.io/api/work/v1"
 )
@@ -36,12 +46,63 @@ const (
 //go:embed manifests/templates
 var fs embed.FS
 
-func Run(ctx context.Context, kubeConfig *rest.Config) error {
+// ManagerOptions defines the flags for the addon manager
+type ManagerOptions struct {
+	WebhookPort    int
+	WebhookCertDir string
+}
+
+// NewManagerOptions returns the flags with default value set
+func NewManagerOptions() *ManagerOptions {
+	return &ManagerOptions{
+		WebhookPort: 9443,
+	}
+}
+
+func (o *ManagerOptions) AddFlags(flags *pflag.FlagSet) {
+	flags.IntVar(&o.WebhookPort, "webhook-port", o.WebhookPort,
+		"Port to serve the validating webhook, the webhook is not served if it is 0.")
+	flags.StringVar(&o.WebhookCertDir, "webhook-cert-dir", o.WebhookCertDir,
+		"Directory of the tls.crt and tls.key of the webhook, a self-signed certificate is used if it is empty.")
+}
+
+// RunWebhook starts the validating webhook on the hub, it is served by every replica of the addon manager, so it is
+// not started with the leader election of the manager.
+func (o *ManagerOptions) RunWebhook(ctx context.Context, kubeConfig *rest.Config) error {
+	if o.WebhookPort == 0 {
+		return nil
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return err
+	}
+
 	addonClient, err := deviceaddonclientset.NewForConfig(kubeConfig)
 	if err != nil {
 		return err
 	}
 
+	return webhook.NewServer(kubeClient, addonClient, o.WebhookPort, o.WebhookCertDir).Start(ctx)
+}
+
+// RunManager starts the addon manager and the device set controller on the hub.
+func (o *ManagerOptions) RunManager(ctx context.Context, kubeConfig *rest.Config) error {
+	addonClient, err := deviceaddonclientset.NewForConfig(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	clusterClient, err := clusterclientset.NewForConfig(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	ocmAddOnClient, err := addonclientset.NewForConfig(kubeConfig)
+	if err != nil {
+		return err
+	}
+
 	mgr, err := addonmanager.New(kubeConfig)
 	if err != nil {
 		return err
@@ -63,8 +124,15 @@ func Run(ctx context.Context, kubeConfig *rest.Config) error {
 				Version:  v1alpha1.GroupVersion.Version,
 				Resource: "deviceaddonconfigs",
 			},
+			utils.AddOnDeploymentConfigGVR,
+		).
+		WithGetValuesFuncs(
+			getAddOnConfigFunc(ctx, addonClient),
+			addonfactory.GetAddOnDeploymentConfigValues(
+				utils.NewAddOnDeploymentConfigGetter(ocmAddOnClient),
+				addonfactory.ToAddOnDeploymentConfigValues,
+			),
 		).
-		WithGetValuesFuncs(getAddOnConfigFunc(ctx, addonClient)).
 		BuildTemplateAgentAddon()
 	if err != nil {
 		klog.Errorf("failed to build agent %v", err)
@@ -80,6 +148,24 @@ func Run(ctx context.Context, kubeConfig *rest.Config) error {
 	if err != nil {
 		klog.Fatal(err)
 	}
+
+	deviceInformerFactory := deviceaddoninformers.NewSharedInformerFactory(addonClient, 10*time.Minute)
+	clusterInformerFactory := clusterinformers.NewSharedInformerFactory(clusterClient, 10*time.Minute)
+
+	deviceSetController := controllers.NewDeviceSetsController(
+		addonClient,
+		deviceInformerFactory.Edge().V1alpha1().DeviceSets(),
+		deviceInformerFactory.Edge().V1alpha1().Devices(),
+		deviceInformerFactory.Edge().V1alpha1().Drivers(),
+		clusterInformerFactory.Cluster().V1().ManagedClusters(),
+		clusterInformerFactory.Cluster().V1beta1().PlacementDecisions(),
+	)
+
+	go deviceInformerFactory.Start(ctx.Done())
+	go clusterInformerFactory.Start(ctx.Done())
+
+	go deviceSetController.Run(ctx, 1)
+
 	<-ctx.Done()
 
 	return nil
@@ -149,9 +235,20 @@ func addonRBAC(kubeConfig *rest.Config) agent.PermissionConfigFunc {
 					Resources: []string{"devices"},
 					APIGroups: []string{"edge.open-cluster-management.io"},
 				},
+				{
+					Verbs:     []string{"get", "list", "watch"},
+					Resources: []string{"deviceprofiles"},
+					APIGroups: []string{"edge.open-cluster-management.io"},
+				},
+				{
+					// the add-on config may be referenced from the other namespaces by the default configs
+					Verbs:     []string{"get", "list", "watch"},
+					Resources: []string{"deviceaddonconfigs"},
+					APIGroups: []string{"edge.open-cluster-management.io"},
+				},
 			},
 		}
-		_, err = kubeClient.RbacV1().ClusterRoles().Get(context.TODO(), clusterRole.Name, metav1.GetOptions{})
+		existingClusterRole, err := kubeClient.RbacV1().ClusterRoles().Get(context.TODO(), clusterRole.Name, metav1.GetOptions{})
 		switch {
 		case errors.IsNotFound(err):
 			_, createErr := kubeClient.RbacV1().ClusterRoles().Create(context.TODO(), clusterRole, metav1.CreateOptions{})
@@ -160,6 +257,14 @@ func addonRBAC(kubeConfig *rest.Config) agent.PermissionConfigFunc {
 			}
 		case err != nil:
 			return err
+		case !equality.Semantic.DeepEqual(existingClusterRole.Rules, clusterRole.Rules):
+			// the rules are updated once the add-on is upgraded
+			updated := existingClusterRole.DeepCopy()
+			updated.Rules = clusterRole.Rules
+			_, updateErr := kubeClient.RbacV1().ClusterRoles().Update(context.TODO(), updated, metav1.UpdateOptions{})
+			if updateErr != nil {
+				return updateErr
+			}
 		}
 
 		role := &rbacv1.Role{
@@ -173,6 +278,15 @@ func addonRBAC(kubeConfig *rest.Config) agent.PermissionConfigFunc {
 					Resources: []string{"configmaps"},
 					APIGroups: []string{""},
 				},
+				{
+					// the credential Secrets are named by the users and watched with the credential label, the
+					// rbac rules cannot be restricted by the labels or the unknown names, so all Secrets in the
+					// cluster namespace are readable. The namespace is only accessible by the agent of this
+					// cluster, and the agent only caches and resolves the Secrets with the credential label.
+					Verbs:     []string{"get", "list", "watch"},
+					Resources: []string{"secrets"},
+					APIGroups: []string{""},
+				},
 				{
 					Verbs:     []string{"get", "list", "watch"},
 					Resources: []string{"managedclusteraddons"},
@@ -208,9 +322,14 @@ func addonRBAC(kubeConfig *rest.Config) agent.PermissionConfigFunc {
 					Resources: []string{"devices/finalizers"},
 					APIGroups: []string{"edge.open-cluster-management.io"},
 				},
+				{
+					Verbs:     []string{"get", "list", "watch"},
+					Resources: []string{"deviceprofiles"},
+					APIGroups: []string{"edge.open-cluster-management.io"},
+				},
 			},
 		}
-		_, err = kubeClient.RbacV1().Roles(cluster.Name).Get(context.TODO(), role.Name, metav1.GetOptions{})
+		existingRole, err := kubeClient.RbacV1().Roles(cluster.Name).Get(context.TODO(), role.Name, metav1.GetOptions{})
 		switch {
 		case errors.IsNotFound(err):
 			_, createErr := kubeClient.RbacV1().Roles(cluster.Name).Create(context.TODO(), role, metav1.CreateOptions{})
@@ -219,6 +338,13 @@ func addonRBAC(kubeConfig *rest.Config) agent.PermissionConfigFunc {
 			}
 		case err != nil:
 			return err
+		case !equality.Semantic.DeepEqual(existingRole.Rules, role.Rules):
+			updated := existingRole.DeepCopy()
+			updated.Rules = role.Rules
+			_, updateErr := kubeClient.RbacV1().Roles(cluster.Name).Update(context.TODO(), updated, metav1.UpdateOptions{})
+			if updateErr != nil {
+				return updateErr
+			}
 		}
 
 		clusterRoleBinding := &rbacv1.ClusterRoleBinding{
@@ -275,9 +401,22 @@ func addonRBAC(kubeConfig *rest.Config) agent.PermissionConfigFunc {
 	}
 }
 
+// getAddOnConfigFunc returns the spec of the DeviceAddOnConfig of the add-on as the add-on config data. The
+// DeviceAddOnConfig is referenced by the configs of the ManagedClusterAddOn or the default configs of the
+// ClusterManagementAddOn (including the configs of its placements), the DeviceAddOnConfig that is named
+// device-addon in the cluster namespace is used if there is no config reference.
 func getAddOnConfigFunc(ctx context.Context, addonClient deviceaddonclientset.Interface) addonfactory.GetValuesFunc {
 	return func(cluster *clusterv1.ManagedCluster, addon *addonv1alpha1.ManagedClusterAddOn) (addonfactory.Values, error) {
-		config, err := addonClient.EdgeV1alpha1().DeviceAddOnConfigs(cluster.Name).Get(ctx, addonName, metav1.GetOptions{})
+		namespace, name := cluster.Name, addonName
+		if ok, configRef := utils.GetAddOnConfigRef(addon.Status.ConfigReferences,
+			v1alpha1.GroupVersion.Group, "deviceaddonconfigs"); ok {
+			namespace, name = configRef.Namespace, configRef.Name
+			if configRef.DesiredConfig != nil {
+				namespace, name = configRef.DesiredConfig.Namespace, configRef.DesiredConfig.Name
+			}
+		}
+
+		config, err := addonClient.EdgeV1alpha1().DeviceAddOnConfigs(namespace).Get(ctx, name, metav1.GetOptions{})
 		if errors.IsNotFound(err) {
 			return map[string]interface{}{}, nil
 		}
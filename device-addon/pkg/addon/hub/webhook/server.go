@@ -0,0 +1,273 @@
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	deviceaddonclientset "open-cluster-management-io/addon-contrib/device-addon/pkg/client/clientset/versioned"
+)
+
+const (
+	// ValidatingWebhookConfigurationName is the name of the validating webhook configuration of the device-addon,
+	// its ca bundle is updated by the server if the server uses a self-signed certificate
+	ValidatingWebhookConfigurationName = "device-addon-validator"
+
+	// ServiceName is the name of the service of the webhook server
+	ServiceName = "device-addon-webhook"
+
+	// ServingCertSecretName is the name of the Secret of the self-signed serving certificate, it is shared by the
+	// replicas of the webhook server
+	ServingCertSecretName = "device-addon-webhook-serving-cert"
+
+	defaultNamespace = "open-cluster-management"
+	namespaceFile    = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// validateFunc validates the object of an admission request, the field errors are returned if the object is
+// invalid
+type validateFunc func(ctx context.Context, req *admissionv1.AdmissionRequest) (field.ErrorList, error)
+
+// Server is the validating webhook server of the device-addon APIs, it serves with the certificate in the cert
+// dir, or a self-signed certificate that is shared by the replicas if the cert dir is not set.
+type Server struct {
+	kubeClient kubernetes.Interface
+	port       int
+	certDir    string
+	validators map[string]validateFunc
+}
+
+func NewServer(kubeClient kubernetes.Interface, addonClient deviceaddonclientset.Interface, port int, certDir string) *Server {
+	return &Server{
+		kubeClient: kubeClient,
+		port:       port,
+		certDir:    certDir,
+		validators: map[string]validateFunc{
+			"/validate-devices":            newDeviceValidator(addonClient).validate,
+			"/validate-drivers":            validateDriver,
+			"/validate-deviceaddonconfigs": validateDeviceAddOnConfig,
+			"/validate-deviceprofiles":     validateDeviceProfile,
+		},
+	}
+}
+
+// Start starts the webhook server, the server is stopped when the context is done
+func (s *Server) Start(ctx context.Context) error {
+	cert, err := s.loadCertificate(ctx)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	for urlPath, validate := range s.validators {
+		mux.HandleFunc(urlPath, serve(validate))
+	}
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", s.port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig: &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{*cert},
+		},
+	}
+
+	go func() {
+		<-ctx.Done()
+		if err := server.Shutdown(context.Background()); err != nil {
+			klog.Errorf("failed to stop the webhook server, %v", err)
+		}
+	}()
+
+	go func() {
+		klog.Infof("Serve the webhook on %s", server.Addr)
+		if err := server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			klog.Errorf("failed to serve the webhook, %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// loadCertificate loads the serving certificate from the cert dir, if the cert dir is not set, a self-signed
+// certificate in the serving cert Secret is used, so it is shared by all of the replicas, and its ca is set to the
+// validating webhook configuration
+func (s *Server) loadCertificate(ctx context.Context) (*tls.Certificate, error) {
+	if len(s.certDir) != 0 {
+		cert, err := tls.LoadX509KeyPair(path.Join(s.certDir, "tls.crt"), path.Join(s.certDir, "tls.key"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the webhook serving certificate, %v", err)
+		}
+		return &cert, nil
+	}
+
+	namespace := defaultNamespace
+	if data, err := os.ReadFile(namespaceFile); err == nil && len(strings.TrimSpace(string(data))) != 0 {
+		namespace = strings.TrimSpace(string(data))
+	}
+
+	certData, keyData, err := s.ensureServingCertSecret(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.X509KeyPair(certData, keyData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the webhook serving certificate, %v", err)
+	}
+
+	// the cert data contains the serving certificate and its ca
+	if err := s.updateCABundle(ctx, certData); err != nil {
+		return nil, err
+	}
+
+	return &cert, nil
+}
+
+// ensureServingCertSecret returns the self-signed certificate and key in the serving cert Secret, they are generated
+// if the Secret does not exist or the certificate expires in a day. If the Secret is created or updated by another
+// replica at the same time, the certificate of the other replica is used.
+func (s *Server) ensureServingCertSecret(ctx context.Context, namespace string) ([]byte, []byte, error) {
+	secret, err := s.kubeClient.CoreV1().Secrets(namespace).Get(ctx, ServingCertSecretName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		secret = nil
+	case err != nil:
+		return nil, nil, fmt.Errorf("failed to get the webhook serving certificate, %v", err)
+	case isValidCertificate(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]):
+		return secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey], nil
+	}
+
+	host := fmt.Sprintf("%s.%s.svc", ServiceName, namespace)
+	certData, keyData, err := certutil.GenerateSelfSignedCertKey(host, nil,
+		[]string{ServiceName, fmt.Sprintf("%s.%s", ServiceName, namespace)})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate the webhook serving certificate, %v", err)
+	}
+
+	data := map[string][]byte{corev1.TLSCertKey: certData, corev1.TLSPrivateKeyKey: keyData}
+	if secret == nil {
+		_, err = s.kubeClient.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: ServingCertSecretName, Namespace: namespace},
+			Type:       corev1.SecretTypeTLS,
+			Data:       data,
+		}, metav1.CreateOptions{})
+	} else {
+		updated := secret.DeepCopy()
+		updated.Data = data
+		_, err = s.kubeClient.CoreV1().Secrets(namespace).Update(ctx, updated, metav1.UpdateOptions{})
+	}
+	if errors.IsAlreadyExists(err) || errors.IsConflict(err) {
+		return s.ensureServingCertSecret(ctx, namespace)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to save the webhook serving certificate, %v", err)
+	}
+
+	return certData, keyData, nil
+}
+
+// isValidCertificate returns true if the certificate and key are paired and the certificate does not expire in a day
+func isValidCertificate(certData, keyData []byte) bool {
+	if _, err := tls.X509KeyPair(certData, keyData); err != nil {
+		return false
+	}
+
+	certs, err := certutil.ParseCertsPEM(certData)
+	if err != nil || len(certs) == 0 {
+		return false
+	}
+
+	return time.Now().Add(24 * time.Hour).Before(certs[0].NotAfter)
+}
+
+// updateCABundle sets the ca bundle to the webhooks of the validating webhook configuration if they are different
+func (s *Server) updateCABundle(ctx context.Context, caBundle []byte) error {
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		config, err := s.kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(
+			ctx, ValidatingWebhookConfigurationName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		updated := config.DeepCopy()
+		for i := range updated.Webhooks {
+			updated.Webhooks[i].ClientConfig.CABundle = caBundle
+		}
+		if equality.Semantic.DeepEqual(config.Webhooks, updated.Webhooks) {
+			return nil
+		}
+
+		_, err = s.kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Update(
+			ctx, updated, metav1.UpdateOptions{})
+		return err
+	})
+	if errors.IsNotFound(err) {
+		klog.Warningf("The validating webhook configuration %s does not exist", ValidatingWebhookConfigurationName)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update the ca bundle of the validating webhook configuration, %v", err)
+	}
+
+	return nil
+}
+
+func serve(validate validateFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		review := &admissionv1.AdmissionReview{}
+		if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+			http.Error(w, fmt.Sprintf("failed to decode the admission review, %v", err), http.StatusBadRequest)
+			return
+		}
+
+		if review.Request == nil {
+			http.Error(w, "the admission review has no request", http.StatusBadRequest)
+			return
+		}
+
+		req := review.Request
+		resp := &admissionv1.AdmissionResponse{UID: req.UID, Allowed: true}
+
+		errs, err := validate(r.Context(), req)
+		switch {
+		case err != nil:
+			status := errors.NewInternalError(err).Status()
+			resp.Allowed = false
+			resp.Result = &status
+		case len(errs) != 0:
+			gk := schema.GroupKind{Group: v1alpha1.GroupVersion.Group, Kind: req.Kind.Kind}
+			status := errors.NewInvalid(gk, req.Name, errs).Status()
+			resp.Allowed = false
+			resp.Result = &status
+		}
+
+		review.Request = nil
+		review.Response = resp
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(review); err != nil {
+			klog.Errorf("failed to write the admission review, %v", err)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+
+	fakedeviceclient "open-cluster-management-io/addon-contrib/device-addon/pkg/client/clientset/versioned/fake"
+)
+
+func TestLoadCertificate(t *testing.T) {
+	kubeClient := fakekube.NewSimpleClientset(&admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: ValidatingWebhookConfigurationName},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			{Name: "devices.edge.open-cluster-management.io"},
+			{Name: "drivers.edge.open-cluster-management.io"},
+		},
+	})
+
+	// the replicas share the certificate in the serving cert Secret
+	replica1 := NewServer(kubeClient, fakedeviceclient.NewSimpleClientset(), 9443, "")
+	replica2 := NewServer(kubeClient, fakedeviceclient.NewSimpleClientset(), 9443, "")
+
+	cert1, err := replica1.loadCertificate(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert2, err := replica2.loadCertificate(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(cert1.Certificate[0], cert2.Certificate[0]) {
+		t.Errorf("expected the replicas serve the same certificate")
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(defaultNamespace).Get(
+		context.TODO(), ServingCertSecretName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(
+		context.TODO(), ValidatingWebhookConfigurationName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, webhook := range config.Webhooks {
+		if !bytes.Equal(webhook.ClientConfig.CABundle, secret.Data["tls.crt"]) {
+			t.Errorf("expected the ca bundle of the webhook %s is set", webhook.Name)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	deviceaddonclientset "open-cluster-management-io/addon-contrib/device-addon/pkg/client/clientset/versioned"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers/remote"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/messagebuses"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/rules"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+type deviceValidator struct {
+	addonClient deviceaddonclientset.Interface
+}
+
+func newDeviceValidator(addonClient deviceaddonclientset.Interface) *deviceValidator {
+	return &deviceValidator{addonClient: addonClient}
+}
+
+// validate validates the device with the referenced profile, the driver type of the device should be a build-in
+// driver type or the type of a remote driver in the device namespace
+func (v *deviceValidator) validate(ctx context.Context, req *admissionv1.AdmissionRequest) (field.ErrorList, error) {
+	device := &v1alpha1.Device{}
+	oldDevice := &v1alpha1.Device{}
+	skip, err := decode(req, device, oldDevice)
+	if err != nil || skip {
+		return nil, err
+	}
+
+	// the objects that are being deleted and the updates that do not change the spec are not validated
+	if !device.DeletionTimestamp.IsZero() ||
+		(req.Operation == admissionv1.Update && equality.Semantic.DeepEqual(device.Spec, oldDevice.Spec)) {
+		return nil, nil
+	}
+
+	specPath := field.NewPath("spec")
+
+	// the referenced profile may be created after the device, the device is validated with its own profile
+	// if the referenced profile does not exist
+	base := v1alpha1.DeviceProfileSpec{}
+	if len(device.Spec.ProfileRef) != 0 {
+		profile, err := v.addonClient.EdgeV1alpha1().DeviceProfiles(device.Namespace).Get(
+			ctx, device.Spec.ProfileRef, metav1.GetOptions{})
+		switch {
+		case err == nil:
+			base = profile.Spec
+		case !errors.IsNotFound(err):
+			return nil, err
+		}
+	}
+
+	errs := drivers.ValidateDevice(device.Spec.DeviceConfig, base, specPath)
+
+	driverType := device.Spec.DriverType
+	if len(driverType) != 0 && !drivers.IsBuildIn(driverType) {
+		isRemote, err := v.isRemoteDriverType(ctx, device.Namespace, driverType)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isRemote {
+			errs = append(errs, field.Invalid(specPath.Child("driverType"), driverType,
+				fmt.Sprintf("the driver type should be one of %s or the type of a remote driver in the namespace %s",
+					strings.Join(drivers.BuildInTypes(), ", "), device.Namespace)))
+		}
+	}
+
+	merged := util.MergeProfile(base, device.Spec.Profile)
+	errs = append(errs, util.ValidateDesired(device.Spec.Desired.Data, merged, specPath.Child("desired"))...)
+
+	return errs, nil
+}
+
+func (v *deviceValidator) isRemoteDriverType(ctx context.Context, namespace, driverType string) (bool, error) {
+	driverList, err := v.addonClient.EdgeV1alpha1().Drivers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	for _, driver := range driverList.Items {
+		if driver.Spec.DriverType == driverType && remote.IsRemote(driver.Spec.Properties.Data) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// validateDriver validates the driver with the schema of its driver type
+func validateDriver(ctx context.Context, req *admissionv1.AdmissionRequest) (field.ErrorList, error) {
+	driver := &v1alpha1.Driver{}
+	oldDriver := &v1alpha1.Driver{}
+	skip, err := decode(req, driver, oldDriver)
+	if err != nil || skip {
+		return nil, err
+	}
+
+	if !driver.DeletionTimestamp.IsZero() ||
+		(req.Operation == admissionv1.Update && equality.Semantic.DeepEqual(driver.Spec, oldDriver.Spec)) {
+		return nil, nil
+	}
+
+	return drivers.ValidateDriver(driver.Spec.DriverConfig, field.NewPath("spec")), nil
+}
+
+// validateDeviceProfile validates the device resources and commands of the profile
+func validateDeviceProfile(ctx context.Context, req *admissionv1.AdmissionRequest) (field.ErrorList, error) {
+	profile := &v1alpha1.DeviceProfile{}
+	oldProfile := &v1alpha1.DeviceProfile{}
+	skip, err := decode(req, profile, oldProfile)
+	if err != nil || skip {
+		return nil, err
+	}
+
+	if !profile.DeletionTimestamp.IsZero() ||
+		(req.Operation == admissionv1.Update && equality.Semantic.DeepEqual(profile.Spec, oldProfile.Spec)) {
+		return nil, nil
+	}
+
+	return util.ValidateProfile(profile.Spec, v1alpha1.DeviceProfileSpec{}, field.NewPath("spec")), nil
+}
+
+// validateDeviceAddOnConfig validates the message buses and the rules of the config
+func validateDeviceAddOnConfig(ctx context.Context, req *admissionv1.AdmissionRequest) (field.ErrorList, error) {
+	config := &v1alpha1.DeviceAddOnConfig{}
+	oldConfig := &v1alpha1.DeviceAddOnConfig{}
+	skip, err := decode(req, config, oldConfig)
+	if err != nil || skip {
+		return nil, err
+	}
+
+	if !config.DeletionTimestamp.IsZero() ||
+		(req.Operation == admissionv1.Update && equality.Semantic.DeepEqual(config.Spec, oldConfig.Spec)) {
+		return nil, nil
+	}
+
+	specPath := field.NewPath("spec")
+	errs := field.ErrorList{}
+	for i, msgBus := range config.Spec.MessageBuses {
+		typePath := specPath.Child("messageBuses").Index(i).Child("type")
+		switch {
+		case len(msgBus.MessageBusType) == 0:
+			errs = append(errs, field.Required(typePath, ""))
+		case !sets.New(messagebuses.SupportedTypes...).Has(msgBus.MessageBusType):
+			errs = append(errs, field.NotSupported(typePath, msgBus.MessageBusType, messagebuses.SupportedTypes))
+		}
+	}
+
+	for i, rule := range config.Spec.Rules {
+		if err := rules.Validate(rule); err != nil {
+			errs = append(errs, field.Invalid(specPath.Child("rules").Index(i), rule.Name, err.Error()))
+		}
+	}
+
+	return errs, nil
+}
+
+// decode decodes the object and the old object of the request, the old object is only decoded for the update
+// requests, the request should be skipped if it is not a create or an update request
+func decode(req *admissionv1.AdmissionRequest, obj, oldObj interface{}) (bool, error) {
+	switch req.Operation {
+	case admissionv1.Create:
+	case admissionv1.Update:
+		if err := json.Unmarshal(req.OldObject.Raw, oldObj); err != nil {
+			return false, fmt.Errorf("failed to decode the old object, %v", err)
+		}
+	default:
+		return true, nil
+	}
+
+	if err := json.Unmarshal(req.Object.Raw, obj); err != nil {
+		return false, fmt.Errorf("failed to decode the object, %v", err)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,328 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	fakedeviceclient "open-cluster-management-io/addon-contrib/device-addon/pkg/client/clientset/versioned/fake"
+)
+
+func newRequest(t *testing.T, operation admissionv1.Operation, obj, oldObj interface{}) *admissionv1.AdmissionRequest {
+	req := &admissionv1.AdmissionRequest{Operation: operation}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Object = runtime.RawExtension{Raw: data}
+
+	if oldObj != nil {
+		data, err := json.Marshal(oldObj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.OldObject = runtime.RawExtension{Raw: data}
+	}
+
+	return req
+}
+
+func newModbusDevice(attrs map[string]interface{}, readWrite v1alpha1.ReadWrite) *v1alpha1.Device {
+	return &v1alpha1.Device{
+		ObjectMeta: metav1.ObjectMeta{Name: "plc", Namespace: "cluster1"},
+		Spec: v1alpha1.DeviceSpec{
+			DeviceConfig: v1alpha1.DeviceConfig{
+				Name:               "plc",
+				DriverType:         "modbus",
+				ProtocolProperties: v1alpha1.Values{Data: map[string]interface{}{"address": "10.0.0.1:502"}},
+				Profile: v1alpha1.DeviceProfileSpec{
+					DeviceResources: []v1alpha1.DeviceResource{{
+						Name:       "temperature",
+						Properties: v1alpha1.ResourceProperties{ReadWrite: readWrite, ValueType: "Int16"},
+						Attributes: v1alpha1.Values{Data: attrs},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func hasError(errs field.ErrorList, fieldPath string) bool {
+	for _, err := range errs {
+		if err.Field == fieldPath {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateDevice(t *testing.T) {
+	holdingRegister := map[string]interface{}{"functionCode": 3, "address": 0, "dataType": "Int16"}
+	inputRegister := map[string]interface{}{"functionCode": 4, "address": 0, "dataType": "Int16"}
+	remoteDriver := &v1alpha1.Driver{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom", Namespace: "cluster1"},
+		Spec: v1alpha1.DriverSpec{DriverConfig: v1alpha1.DriverConfig{
+			DriverType: "custom",
+			Properties: v1alpha1.Values{Data: map[string]interface{}{"remote": map[string]interface{}{"address": "unix:///tmp/custom.sock"}}},
+		}},
+	}
+
+	now := metav1.NewTime(time.Now())
+	deleting := newModbusDevice(map[string]interface{}{"functionCode": 10}, "RW")
+	deleting.DeletionTimestamp = &now
+
+	remoteDevice := newModbusDevice(nil, "RW")
+	remoteDevice.Spec.DriverType = "custom"
+	unknownDevice := newModbusDevice(nil, "RW")
+	unknownDevice.Spec.DriverType = "unknown"
+
+	desiredDevice := newModbusDevice(holdingRegister, "R")
+	desiredDevice.Spec.Desired = v1alpha1.Values{Data: map[string]interface{}{"temperature": 20}}
+
+	cases := []struct {
+		name          string
+		req           *admissionv1.AdmissionRequest
+		expectedField string
+	}{
+		{
+			name: "valid device",
+			req:  newRequest(t, admissionv1.Create, newModbusDevice(holdingRegister, "RW"), nil),
+		},
+		{
+			name: "default readWrite",
+			req:  newRequest(t, admissionv1.Create, newModbusDevice(holdingRegister, ""), nil),
+		},
+		{
+			name:          "unsupported readWrite",
+			req:           newRequest(t, admissionv1.Create, newModbusDevice(holdingRegister, "X"), nil),
+			expectedField: "spec.profile.deviceResources[0].properties.readWrite",
+		},
+		{
+			name:          "the default readWrite of a read only resource",
+			req:           newRequest(t, admissionv1.Create, newModbusDevice(inputRegister, ""), nil),
+			expectedField: "spec.profile.deviceResources[0].properties.readWrite",
+		},
+		{
+			name:          "unsupported function code",
+			req:           newRequest(t, admissionv1.Create, newModbusDevice(map[string]interface{}{"functionCode": 10}, "RW"), nil),
+			expectedField: "spec.profile.deviceResources[0].attributes.functionCode",
+		},
+		{
+			name:          "desired value of a read only resource",
+			req:           newRequest(t, admissionv1.Create, desiredDevice, nil),
+			expectedField: "spec.desired[temperature]",
+		},
+		{
+			name: "remote driver type",
+			req:  newRequest(t, admissionv1.Create, remoteDevice, nil),
+		},
+		{
+			name:          "unknown driver type",
+			req:           newRequest(t, admissionv1.Create, unknownDevice, nil),
+			expectedField: "spec.driverType",
+		},
+		{
+			name: "deleting device",
+			req:  newRequest(t, admissionv1.Update, deleting, newModbusDevice(holdingRegister, "RW")),
+		},
+		{
+			name: "unchanged spec",
+			req: newRequest(t, admissionv1.Update,
+				newModbusDevice(map[string]interface{}{"functionCode": 10}, "RW"),
+				newModbusDevice(map[string]interface{}{"functionCode": 10}, "RW")),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			validator := newDeviceValidator(fakedeviceclient.NewSimpleClientset(remoteDriver))
+			errs, err := validator.validate(context.TODO(), c.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(c.expectedField) == 0 && len(errs) != 0 {
+				t.Errorf("expected no error, but got %v", errs)
+			}
+			if len(c.expectedField) != 0 && !hasError(errs, c.expectedField) {
+				t.Errorf("expected the error of %s, but got %v", c.expectedField, errs)
+			}
+		})
+	}
+}
+
+func TestValidateDeviceProfile(t *testing.T) {
+	newProfile := func(resources []v1alpha1.DeviceResource, commands []v1alpha1.DeviceCommand) *v1alpha1.DeviceProfile {
+		return &v1alpha1.DeviceProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "thermostat", Namespace: "cluster1"},
+			Spec:       v1alpha1.DeviceProfileSpec{DeviceResources: resources, DeviceCommands: commands},
+		}
+	}
+	setpoint := v1alpha1.DeviceResource{
+		Name:       "setpoint",
+		Properties: v1alpha1.ResourceProperties{ValueType: "Float64"},
+	}
+
+	cases := []struct {
+		name          string
+		req           *admissionv1.AdmissionRequest
+		expectedField string
+	}{
+		{
+			name: "valid profile",
+			req: newRequest(t, admissionv1.Create, newProfile([]v1alpha1.DeviceResource{setpoint}, []v1alpha1.DeviceCommand{
+				{Name: "set", Resources: []v1alpha1.DeviceCommandResource{{DeviceResource: "setpoint", DefaultValue: "20"}}},
+			}), nil),
+		},
+		{
+			name: "unsupported value type",
+			req: newRequest(t, admissionv1.Create, newProfile([]v1alpha1.DeviceResource{
+				{Name: "setpoint", Properties: v1alpha1.ResourceProperties{ValueType: "Decimal"}},
+			}, nil), nil),
+			expectedField: "spec.deviceResources[0].properties.valueType",
+		},
+		{
+			name: "duplicated resources",
+			req: newRequest(t, admissionv1.Create,
+				newProfile([]v1alpha1.DeviceResource{setpoint, setpoint}, nil), nil),
+			expectedField: "spec.deviceResources[1].name",
+		},
+		{
+			name: "command resource not found",
+			req: newRequest(t, admissionv1.Update, newProfile([]v1alpha1.DeviceResource{setpoint}, []v1alpha1.DeviceCommand{
+				{Name: "set", Resources: []v1alpha1.DeviceCommandResource{{DeviceResource: "mode"}}},
+			}), newProfile([]v1alpha1.DeviceResource{setpoint}, nil)),
+			expectedField: "spec.deviceCommands[0].resources[0].deviceResource",
+		},
+		{
+			name: "delete",
+			req: &admissionv1.AdmissionRequest{
+				Operation: admissionv1.Delete,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			errs, err := validateDeviceProfile(context.TODO(), c.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(c.expectedField) == 0 && len(errs) != 0 {
+				t.Errorf("expected no error, but got %v", errs)
+			}
+			if len(c.expectedField) != 0 && !hasError(errs, c.expectedField) {
+				t.Errorf("expected the error of %s, but got %v", c.expectedField, errs)
+			}
+		})
+	}
+}
+
+func TestValidateDriver(t *testing.T) {
+	newDriver := func(driverType string, properties map[string]interface{}) *v1alpha1.Driver {
+		return &v1alpha1.Driver{
+			ObjectMeta: metav1.ObjectMeta{Name: driverType, Namespace: "cluster1"},
+			Spec: v1alpha1.DriverSpec{DriverConfig: v1alpha1.DriverConfig{
+				DriverType: driverType,
+				Properties: v1alpha1.Values{Data: properties},
+			}},
+		}
+	}
+
+	cases := []struct {
+		name          string
+		driver        *v1alpha1.Driver
+		expectedField string
+	}{
+		{
+			name:   "valid driver",
+			driver: newDriver("modbus", map[string]interface{}{"pollInterval": "10s"}),
+		},
+		{
+			name:          "invalid property",
+			driver:        newDriver("modbus", map[string]interface{}{"pollInterval": "ten seconds"}),
+			expectedField: "spec.properties.pollInterval",
+		},
+		{
+			name:          "unknown driver type",
+			driver:        newDriver("unknown", map[string]interface{}{}),
+			expectedField: "spec.type",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			errs, err := validateDriver(context.TODO(), newRequest(t, admissionv1.Create, c.driver, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(c.expectedField) == 0 && len(errs) != 0 {
+				t.Errorf("expected no error, but got %v", errs)
+			}
+			if len(c.expectedField) != 0 && !hasError(errs, c.expectedField) {
+				t.Errorf("expected the error of %s, but got %v", c.expectedField, errs)
+			}
+		})
+	}
+}
+
+func TestValidateDeviceAddOnConfig(t *testing.T) {
+	newConfig := func(msgBusType string, rules ...v1alpha1.ProcessingRule) *v1alpha1.DeviceAddOnConfig {
+		return &v1alpha1.DeviceAddOnConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "device-addon", Namespace: "cluster1"},
+			Spec: v1alpha1.DeviceAddOnConfigSpec{
+				MessageBuses: []v1alpha1.MessageBusConfig{{MessageBusType: msgBusType, Enabled: true}},
+				Rules:        rules,
+			},
+		}
+	}
+
+	cases := []struct {
+		name          string
+		config        *v1alpha1.DeviceAddOnConfig
+		expectedField string
+	}{
+		{
+			name: "valid config",
+			config: newConfig("mqtt", v1alpha1.ProcessingRule{
+				Name: "downsample", Downsample: &v1alpha1.DownsampleRule{Interval: "10s"}}),
+		},
+		{
+			name:          "unsupported message bus",
+			config:        newConfig("amqp"),
+			expectedField: "spec.messageBuses[0].type",
+		},
+		{
+			name: "invalid rule",
+			config: newConfig("mqtt", v1alpha1.ProcessingRule{
+				Name: "downsample", Downsample: &v1alpha1.DownsampleRule{Interval: "-10s"}}),
+			expectedField: "spec.rules[0]",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			errs, err := validateDeviceAddOnConfig(context.TODO(), newRequest(t, admissionv1.Create, c.config, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(c.expectedField) == 0 && len(errs) != 0 {
+				t.Errorf("expected no error, but got %v", errs)
+			}
+			if len(c.expectedField) != 0 && !hasError(errs, c.expectedField) {
+				t.Errorf("expected the error of %s, but got %v", c.expectedField, errs)
+			}
+		})
+	}
+}
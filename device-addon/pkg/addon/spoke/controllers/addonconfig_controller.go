@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/addon-framework/pkg/basecontroller/factory"
+	"open-cluster-management.io/addon-framework/pkg/utils"
+	addoninformerv1alpha1 "open-cluster-management.io/api/client/addon/informers/externalversions/addon/v1alpha1"
+	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/addon/spoke/credentials"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	deviceinformerv1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/client/informers/externalversions/apis/v1alpha1"
+	devicelisterv1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/client/listers/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/equipment"
+)
+
+// addOnConfigKey is the only queue key of the add-on config controller, an agent has one add-on config
+const addOnConfigKey = "addonconfig"
+
+type addOnConfigController struct {
+	lister        devicelisterv1alpha1.DeviceAddOnConfigLister
+	addOnLister   addonlisterv1alpha1.ManagedClusterAddOnLister
+	equipment     *equipment.Equipment
+	resolver      *credentials.Resolver
+	clusterName   string
+	addOnName     string
+	defaultConfig *v1alpha1.DeviceAddOnConfigSpec
+	// current is the applied add-on config, the message bus properties are resolved with the credentials
+	current *v1alpha1.DeviceAddOnConfigSpec
+}
+
+// NewAddOnConfigController returns a controller that applies the DeviceAddOnConfig of the add-on to the equipment
+// once the config or its credential Secrets are changed on the hub. The changed message buses are replaced and the
+// changed rules are reset, the running drivers and devices are not affected. The default config is applied if the
+// add-on has no DeviceAddOnConfig.
+func NewAddOnConfigController(
+	clusterName, addOnName string,
+	configInformer deviceinformerv1alpha1.DeviceAddOnConfigInformer,
+	addOnInformer addoninformerv1alpha1.ManagedClusterAddOnInformer,
+	secretInformer corev1informers.SecretInformer,
+	resolver *credentials.Resolver,
+	equipment *equipment.Equipment,
+	defaultConfig, current *v1alpha1.DeviceAddOnConfigSpec,
+) factory.Controller {
+	c := &addOnConfigController{
+		lister:        configInformer.Lister(),
+		addOnLister:   addOnInformer.Lister(),
+		equipment:     equipment,
+		resolver:      resolver,
+		clusterName:   clusterName,
+		addOnName:     addOnName,
+		defaultConfig: defaultConfig,
+		current:       current.DeepCopy(),
+	}
+
+	return factory.New().
+		WithInformersQueueKeysFunc(c.addOnQueueKeys, addOnInformer.Informer()).
+		WithInformersQueueKeysFunc(c.configQueueKeys, configInformer.Informer()).
+		WithInformersQueueKeysFunc(c.secretQueueKeys, secretInformer.Informer()).
+		WithSync(c.sync).
+		ToController("addonconfig-controller")
+}
+
+func (c *addOnConfigController) sync(ctx context.Context, syncCtx factory.SyncContext, key string) error {
+	config, err := c.getAddOnConfig()
+	if err != nil {
+		return err
+	}
+
+	desired := config.DeepCopy()
+	for i, msgBus := range desired.MessageBuses {
+		properties, err := c.resolver.Resolve(ctx, fmt.Sprintf("messagebus-%s", msgBus.MessageBusType), msgBus.Properties)
+		if err != nil {
+			return err
+		}
+		desired.MessageBuses[i].Properties = properties
+	}
+
+	if !equality.Semantic.DeepEqual(c.current.MessageBuses, desired.MessageBuses) {
+		klog.Infof("Update the message buses")
+		if err := c.equipment.SetMessageBuses(ctx, desired.MessageBuses); err != nil {
+			return fmt.Errorf("failed to update the message buses, %v", err)
+		}
+		c.current.MessageBuses = desired.MessageBuses
+	}
+
+	if !equality.Semantic.DeepEqual(c.current.Rules, desired.Rules) {
+		klog.Infof("Update the processing rules")
+		if err := c.equipment.SetRules(desired.Rules); err != nil {
+			return fmt.Errorf("failed to update the processing rules, %v", err)
+		}
+		c.current.Rules = desired.Rules
+	}
+
+	return nil
+}
+
+// getAddOnConfig returns the spec of the DeviceAddOnConfig of the add-on, it is resolved in the same way as the
+// add-on manager, the DeviceAddOnConfig is referenced by the configs of the add-on, or it is the DeviceAddOnConfig
+// that has the add-on name in the cluster namespace.
+func (c *addOnConfigController) getAddOnConfig() (*v1alpha1.DeviceAddOnConfigSpec, error) {
+	namespace, name, err := c.getAddOnConfigName()
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := c.lister.DeviceAddOnConfigs(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		return c.defaultConfig, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &config.Spec, nil
+}
+
+func (c *addOnConfigController) getAddOnConfigName() (string, string, error) {
+	namespace, name := c.clusterName, c.addOnName
+
+	addOn, err := c.addOnLister.ManagedClusterAddOns(c.clusterName).Get(c.addOnName)
+	if errors.IsNotFound(err) {
+		return namespace, name, nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	if ok, configRef := utils.GetAddOnConfigRef(addOn.Status.ConfigReferences,
+		v1alpha1.GroupVersion.Group, "deviceaddonconfigs"); ok {
+		namespace, name = configRef.Namespace, configRef.Name
+		if configRef.DesiredConfig != nil {
+			namespace, name = configRef.DesiredConfig.Namespace, configRef.DesiredConfig.Name
+		}
+	}
+
+	return namespace, name, nil
+}
+
+// addOnQueueKeys returns the queue key if the ManagedClusterAddOn is the add-on, its config references are changed
+// once the referenced configs are changed
+func (c *addOnConfigController) addOnQueueKeys(obj runtime.Object) []string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return []string{}
+	}
+
+	if accessor.GetNamespace() != c.clusterName || accessor.GetName() != c.addOnName {
+		return []string{}
+	}
+
+	return []string{addOnConfigKey}
+}
+
+// configQueueKeys returns the queue key if the DeviceAddOnConfig is the config of the add-on
+func (c *addOnConfigController) configQueueKeys(obj runtime.Object) []string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return []string{}
+	}
+
+	namespace, name, err := c.getAddOnConfigName()
+	if err != nil {
+		klog.Errorf("failed to get the add-on config name, %v", err)
+		return []string{}
+	}
+
+	if accessor.GetNamespace() != namespace || accessor.GetName() != name {
+		return []string{}
+	}
+
+	return []string{addOnConfigKey}
+}
+
+// secretQueueKeys returns the queue key if the Secret is referenced by the message buses of the add-on config, so
+// the message buses are restarted with the new credentials when the Secret is changed
+func (c *addOnConfigController) secretQueueKeys(obj runtime.Object) []string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return []string{}
+	}
+
+	config, err := c.getAddOnConfig()
+	if err != nil {
+		klog.Errorf("failed to get the add-on config, %v", err)
+		return []string{}
+	}
+
+	for _, msgBus := range config.MessageBuses {
+		if credentials.GetSecretName(msgBus.Properties) == accessor.GetName() {
+			return []string{addOnConfigKey}
+		}
+	}
+
+	return []string{}
+}
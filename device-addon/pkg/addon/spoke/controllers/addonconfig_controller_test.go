@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonlisterv1alpha1 "open-cluster-management.io/api/client/addon/listers/addon/v1alpha1"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/addon/spoke/credentials"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	devicelisterv1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/client/listers/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/equipment"
+)
+
+func newAddOnConfig(namespace, name, ruleName string) *v1alpha1.DeviceAddOnConfig {
+	return &v1alpha1.DeviceAddOnConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: v1alpha1.DeviceAddOnConfigSpec{
+			// the message bus is disabled, so it is not started
+			MessageBuses: []v1alpha1.MessageBusConfig{{
+				MessageBusType: "mqtt",
+				Properties:     v1alpha1.Values{Data: map[string]interface{}{"credentialSecret": "mqtt-credentials"}},
+			}},
+			Rules: []v1alpha1.ProcessingRule{{
+				Name:     ruleName,
+				Deadband: &v1alpha1.DeadbandRule{Absolute: 1},
+			}},
+		},
+	}
+}
+
+func newAddOnConfigController(t *testing.T, objs ...interface{}) *addOnConfigController {
+	configStore := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	addOnStore := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+
+	kubeClient := fakekube.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mqtt-credentials",
+			Namespace: "cluster1",
+			Labels:    map[string]string{credentials.CredentialLabel: "true"},
+		},
+		Data: map[string][]byte{"username": []byte("agent")},
+	})
+
+	for _, obj := range objs {
+		var err error
+		switch o := obj.(type) {
+		case *v1alpha1.DeviceAddOnConfig:
+			err = configStore.Add(o)
+		case *addonv1alpha1.ManagedClusterAddOn:
+			err = addOnStore.Add(o)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	e := equipment.NewEquipment()
+	t.Cleanup(e.Stop)
+
+	return &addOnConfigController{
+		lister:        devicelisterv1alpha1.NewDeviceAddOnConfigLister(configStore),
+		addOnLister:   addonlisterv1alpha1.NewManagedClusterAddOnLister(addOnStore),
+		equipment:     e,
+		resolver:      credentials.NewResolver(kubeClient, "cluster1", t.TempDir()),
+		clusterName:   "cluster1",
+		addOnName:     "device-addon",
+		defaultConfig: &v1alpha1.DeviceAddOnConfigSpec{},
+		current:       &v1alpha1.DeviceAddOnConfigSpec{},
+	}
+}
+
+func TestAddOnConfigSync(t *testing.T) {
+	referenced := &addonv1alpha1.ManagedClusterAddOn{
+		ObjectMeta: metav1.ObjectMeta{Name: "device-addon", Namespace: "cluster1"},
+		Status: addonv1alpha1.ManagedClusterAddOnStatus{
+			ConfigReferences: []addonv1alpha1.ConfigReference{{
+				ConfigGroupResource: addonv1alpha1.ConfigGroupResource{
+					Group:    v1alpha1.GroupVersion.Group,
+					Resource: "deviceaddonconfigs",
+				},
+				DesiredConfig: &addonv1alpha1.ConfigSpecHash{
+					ConfigReferent: addonv1alpha1.ConfigReferent{Namespace: "default", Name: "shared"},
+				},
+			}},
+		},
+	}
+
+	cases := []struct {
+		name         string
+		objs         []interface{}
+		expectedRule string
+	}{
+		{
+			name:         "the config in the cluster namespace",
+			objs:         []interface{}{newAddOnConfig("cluster1", "device-addon", "local")},
+			expectedRule: "local",
+		},
+		{
+			name: "the referenced config",
+			objs: []interface{}{
+				referenced,
+				newAddOnConfig("cluster1", "device-addon", "local"),
+				newAddOnConfig("default", "shared", "shared"),
+			},
+			expectedRule: "shared",
+		},
+		{
+			name: "no config",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			controller := newAddOnConfigController(t, c.objs...)
+			if err := controller.sync(context.TODO(), nil, addOnConfigKey); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(c.expectedRule) == 0 {
+				if len(controller.current.Rules) != 0 || len(controller.current.MessageBuses) != 0 {
+					t.Errorf("expected the default config, but got %v", controller.current)
+				}
+				return
+			}
+
+			if len(controller.current.Rules) != 1 || controller.current.Rules[0].Name != c.expectedRule {
+				t.Errorf("expected the rule %s, but got %v", c.expectedRule, controller.current.Rules)
+			}
+
+			// the credentials of the message buses are resolved
+			if len(controller.current.MessageBuses) != 1 ||
+				credentials.GetSecretName(controller.current.MessageBuses[0].Properties) != "mqtt-credentials" ||
+				controller.current.MessageBuses[0].Properties.Data["credentialDir"] == nil {
+				t.Errorf("expected the resolved message bus, but got %v", controller.current.MessageBuses)
+			}
+		})
+	}
+}
+
+func TestAddOnConfigQueueKeys(t *testing.T) {
+	controller := newAddOnConfigController(t, newAddOnConfig("cluster1", "device-addon", "local"))
+
+	cases := []struct {
+		name     string
+		keys     []string
+		expected bool
+	}{
+		{
+			name:     "the config of the add-on",
+			keys:     controller.configQueueKeys(newAddOnConfig("cluster1", "device-addon", "local")),
+			expected: true,
+		},
+		{
+			name: "the config of the other cluster",
+			keys: controller.configQueueKeys(newAddOnConfig("cluster2", "device-addon", "local")),
+		},
+		{
+			name: "the credential secret of the message bus",
+			keys: controller.secretQueueKeys(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "mqtt-credentials", Namespace: "cluster1"}}),
+			expected: true,
+		},
+		{
+			name: "the credential secret of a driver",
+			keys: controller.secretQueueKeys(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "opcua-credentials", Namespace: "cluster1"}}),
+		},
+		{
+			name: "the add-on",
+			keys: controller.addOnQueueKeys(&addonv1alpha1.ManagedClusterAddOn{
+				ObjectMeta: metav1.ObjectMeta{Name: "device-addon", Namespace: "cluster1"}}),
+			expected: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.expected != (len(c.keys) == 1) {
+				t.Errorf("expected the config is enqueued %v, but got %v", c.expected, c.keys)
+			}
+		})
+	}
+}
@@ -2,11 +2,20 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/spf13/cast"
+
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
@@ -20,29 +29,57 @@ import (
 	deviceinformerv1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/client/informers/externalversions/apis/v1alpha1"
 	devicelisterv1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/client/listers/apis/v1alpha1"
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/equipment"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+const (
+	// deviceStateResyncInterval is the interval to report the device live state to the device status
+	deviceStateResyncInterval = 1 * time.Minute
+	// dataFlowingTimeout is the max time that there is no reading from a device when its data is flowing
+	dataFlowingTimeout = 5 * time.Minute
+	// desiredRetryInterval is the min interval to write a desired value again if the reported value is drifted
+	desiredRetryInterval = 1 * time.Minute
+)
+
+const (
+	DeviceConditionConnected     = "Connected"
+	DeviceConditionDataFlowing   = "DataFlowing"
+	DeviceConditionDesiredSynced = "DesiredSynced"
 )
 
-const deviceFinalizer = "edge.open-cluster-management.io/device-cleanup"
+// desiredWrite is the last write of a desired value to a device resource
+type desiredWrite struct {
+	value string
+	time  time.Time
+}
 
 type devicesController struct {
-	client      deviceclient.Interface
-	lister      devicelisterv1alpha1.DeviceLister
-	equipment   *equipment.Equipment
-	clusterName string
-	patcher     patcher.Patcher[*v1alpha1.Device, v1alpha1.DeviceSpec, v1alpha1.DeviceStatus]
+	sync.Mutex
+	client        deviceclient.Interface
+	lister        devicelisterv1alpha1.DeviceLister
+	profileLister devicelisterv1alpha1.DeviceProfileLister
+	equipment     *equipment.Equipment
+	clusterName   string
+	patcher       patcher.Patcher[*v1alpha1.Device, v1alpha1.DeviceSpec, v1alpha1.DeviceStatus]
+	// writes records the last writes of the desired values, they are keyed by the device names and then the
+	// resource names
+	writes map[string]map[string]desiredWrite
 }
 
 func NewDevicesController(
 	clusterName string,
 	client deviceclient.Interface,
 	deviceInformer deviceinformerv1alpha1.DeviceInformer,
+	profileInformer deviceinformerv1alpha1.DeviceProfileInformer,
 	equipment *equipment.Equipment,
 ) factory.Controller {
 	c := &devicesController{
-		client:      client,
-		lister:      deviceInformer.Lister(),
-		equipment:   equipment,
-		clusterName: clusterName,
+		client:        client,
+		lister:        deviceInformer.Lister(),
+		profileLister: profileInformer.Lister(),
+		equipment:     equipment,
+		clusterName:   clusterName,
+		writes:        make(map[string]map[string]desiredWrite),
 		patcher: patcher.NewPatcher[*v1alpha1.Device, v1alpha1.DeviceSpec, v1alpha1.DeviceStatus](
 			client.EdgeV1alpha1().Devices(clusterName)),
 	}
@@ -52,6 +89,7 @@ func NewDevicesController(
 			key, _ := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
 			return []string{key}
 		}, deviceInformer.Informer()).
+		WithInformersQueueKeysFunc(c.profileQueueKeys, profileInformer.Informer()).
 		WithSync(c.sync).
 		ToController("device-controller")
 }
@@ -74,24 +112,35 @@ func (c *devicesController) sync(ctx context.Context, syncCtx factory.SyncContex
 		return err
 	}
 
-	//
+	switch device.Labels[v1alpha1.DiscoveredLabel] {
+	case v1alpha1.DiscoveredProposed, v1alpha1.DiscoveredRejected:
+		// the discovered device is not added until it is accepted
+		if err := c.equipment.RemoveDevice(device.Spec.Name); err != nil {
+			return err
+		}
+		c.removeWrites(device.Spec.Name)
 
-	driver := c.equipment.GetDriver(device.Spec.DriverType)
-	if driver == nil {
-		// requeue
-		syncCtx.Queue().AddAfter(key, 5*time.Second)
-		return nil
+		return c.patcher.RemoveFinalizer(ctx, device, v1alpha1.DeviceFinalizer)
 	}
 
+	// the device is removed even if its driver is uninstalled, e.g. the device and its driver are deleted together
 	if !device.DeletionTimestamp.IsZero() {
-		if err := driver.RemoveDevice(device.Spec.Name); err != nil {
+		if err := c.equipment.RemoveDevice(device.Spec.Name); err != nil {
 			return err
 		}
+		c.removeWrites(device.Spec.Name)
+
+		return c.patcher.RemoveFinalizer(ctx, device, v1alpha1.DeviceFinalizer)
+	}
 
-		return c.patcher.RemoveFinalizer(ctx, device, deviceFinalizer)
+	driver := c.equipment.GetDriver(device.Spec.DriverType)
+	if driver == nil {
+		// requeue
+		syncCtx.Queue().AddAfter(key, 5*time.Second)
+		return nil
 	}
 
-	updated, err := c.patcher.AddFinalizer(ctx, device, deviceFinalizer)
+	updated, err := c.patcher.AddFinalizer(ctx, device, v1alpha1.DeviceFinalizer)
 	if err != nil || updated {
 		return err
 	}
@@ -103,15 +152,345 @@ func (c *devicesController) sync(ctx context.Context, syncCtx factory.SyncContex
 		Message: "Device is added",
 	}
 
-	if err := driver.AddDevice(device.Spec.DeviceConfig); err != nil {
+	added := true
+	config, err := c.resolveDevice(device)
+	switch {
+	case err != nil:
+		added = false
 		addedCondition.Status = metav1.ConditionFalse
-		addedCondition.Reason = "DeviceNotAdded"
-		addedCondition.Message = fmt.Sprintf("Device is failed to add, %v", err)
+		addedCondition.Reason = "ProfileNotResolved"
+		addedCondition.Message = fmt.Sprintf("Device profile is failed to resolve, %v", err)
+	default:
+		if err := c.equipment.AddDevice(config); err != nil {
+			added = false
+			addedCondition.Status = metav1.ConditionFalse
+			addedCondition.Reason = "DeviceNotAdded"
+			addedCondition.Message = fmt.Sprintf("Device is failed to add, %v", err)
+		}
 	}
 
 	newDevice := device.DeepCopy()
 	meta.SetStatusCondition(&newDevice.Status.Conditions, addedCondition)
 
-	_, updatedErr := c.patcher.PatchStatus(ctx, newDevice, newDevice.Status, device.Status)
-	return updatedErr
+	state := c.equipment.GetDeviceState(device.Spec.Name)
+	connectedCondition, dataFlowingCondition := toStateConditions(state)
+	meta.SetStatusCondition(&newDevice.Status.Conditions, connectedCondition)
+	meta.SetStatusCondition(&newDevice.Status.Conditions, dataFlowingCondition)
+	newDevice.Status.State = toLiveState(state)
+
+	reported := c.equipment.GetReportedValues(device.Spec.Name)
+	newDevice.Status.Reported = toReportedValues(reported, device.Status.Reported)
+	switch {
+	case len(device.Spec.Desired.Data) == 0:
+		c.removeWrites(device.Spec.Name)
+		meta.RemoveStatusCondition(&newDevice.Status.Conditions, DeviceConditionDesiredSynced)
+	case added:
+		meta.SetStatusCondition(&newDevice.Status.Conditions, c.syncDesired(config, device.Spec.Desired, reported))
+	}
+
+	if _, updatedErr := c.patcher.PatchStatus(ctx, newDevice, newDevice.Status, device.Status); updatedErr != nil {
+		return updatedErr
+	}
+
+	// resync the device to report its live state
+	syncCtx.Queue().AddAfter(key, deviceStateResyncInterval)
+	return nil
+}
+
+// syncDesired writes the desired values to the device resources whose reported values are drifted from them,
+// the write-only resources are written once their desired values are changed
+func (c *devicesController) syncDesired(config v1alpha1.DeviceConfig, desiredValues v1alpha1.Values,
+	reported map[string]util.Result) metav1.Condition {
+	c.Lock()
+	defer c.Unlock()
+
+	deviceName := config.Name
+	lastWrites := c.writes[deviceName]
+	writes := map[string]desiredWrite{}
+	drifted := []string{}
+	failed := []string{}
+
+	names := []string{}
+	for name := range desiredValues.Data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		requests, err := util.ToWriteRequests(config, util.Command{
+			DeviceName:    deviceName,
+			DeviceCommand: name,
+			Attributes:    util.Attributes{name: desiredValues.Data[name]},
+		})
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+
+		res := requests[0].Resource
+		desired := desiredValues.Data[name]
+		desiredValue := fmt.Sprintf("%v", requests[0].Value)
+		last, written := lastWrites[name]
+		if written && last.value == desiredValue {
+			writes[name] = last
+		}
+
+		readable := util.IsReadable(res.Properties.ReadWrite)
+		var expected interface{}
+		if readable {
+			expected, err = toReportedValue(requests[0])
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+				continue
+			}
+		}
+		current, ok := reported[name]
+		switch {
+		case readable && ok && valuesEqual(res, expected, current.Value):
+			continue
+		case !readable && written && last.value == desiredValue:
+			// the write-only resource is written with the desired value
+			continue
+		case readable && ok:
+			drifted = append(drifted, fmt.Sprintf("%s: desired %v, reported %v", name, desired, current.Value))
+		case readable:
+			drifted = append(drifted, fmt.Sprintf("%s: desired %v, not reported", name, desired))
+		}
+
+		if written && last.value == desiredValue && time.Since(last.time) < desiredRetryInterval {
+			continue
+		}
+
+		klog.Infof("Write the desired value %v to the resource %s of device %s", desired, name, deviceName)
+		if err := c.equipment.RunCommand(util.Command{
+			DeviceName:    deviceName,
+			DeviceCommand: name,
+			Attributes:    util.Attributes{name: desired},
+		}); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+
+		writes[name] = desiredWrite{value: desiredValue, time: time.Now()}
+	}
+	c.writes[deviceName] = writes
+
+	switch {
+	case len(failed) != 0:
+		return metav1.Condition{
+			Type:    DeviceConditionDesiredSynced,
+			Status:  metav1.ConditionFalse,
+			Reason:  "DesiredValuesNotWritten",
+			Message: fmt.Sprintf("Failed to write the desired values, %s", strings.Join(failed, "; ")),
+		}
+	case len(drifted) != 0:
+		return metav1.Condition{
+			Type:    DeviceConditionDesiredSynced,
+			Status:  metav1.ConditionFalse,
+			Reason:  "DesiredValuesDrifted",
+			Message: fmt.Sprintf("The reported values are drifted, %s", strings.Join(drifted, "; ")),
+		}
+	}
+
+	return metav1.Condition{
+		Type:    DeviceConditionDesiredSynced,
+		Status:  metav1.ConditionTrue,
+		Reason:  "DesiredValuesSynced",
+		Message: "The reported values converge to the desired values",
+	}
+}
+
+// resolveDevice returns the device config with the profile that is merged from its referenced DeviceProfile
+func (c *devicesController) resolveDevice(device *v1alpha1.Device) (v1alpha1.DeviceConfig, error) {
+	config := *device.Spec.DeviceConfig.DeepCopy()
+	if len(device.Spec.ProfileRef) == 0 {
+		return config, nil
+	}
+
+	profile, err := c.profileLister.DeviceProfiles(c.clusterName).Get(device.Spec.ProfileRef)
+	if err != nil {
+		return config, fmt.Errorf("failed to get the profile %s, %v", device.Spec.ProfileRef, err)
+	}
+
+	config.Profile = util.MergeProfile(*profile.Spec.DeepCopy(), config.Profile)
+	return config, nil
+}
+
+// profileQueueKeys returns the keys of the devices that reference the profile, the key of the profile is got from
+// its tombstone if the profile is deleted when the informer is disconnected
+func (c *devicesController) profileQueueKeys(obj runtime.Object) []string {
+	profileKey, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return []string{}
+	}
+
+	_, profileName, err := cache.SplitMetaNamespaceKey(profileKey)
+	if err != nil {
+		return []string{}
+	}
+
+	devices, err := c.lister.Devices(c.clusterName).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list devices, %v", err)
+		return []string{}
+	}
+
+	keys := []string{}
+	for _, device := range devices {
+		if device.Spec.ProfileRef != profileName {
+			continue
+		}
+
+		key, _ := cache.MetaNamespaceKeyFunc(device)
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func (c *devicesController) removeWrites(deviceName string) {
+	c.Lock()
+	defer c.Unlock()
+
+	delete(c.writes, deviceName)
+}
+
+// toReportedValues converts the readings to the reported values, the last reported values are kept if the
+// readings are not recorded, e.g. the agent is restarted, or if the readings are not changed, so the device status
+// is not updated by every reading
+func toReportedValues(readings map[string]util.Result, last []v1alpha1.ReportedValue) []v1alpha1.ReportedValue {
+	values := map[string]v1alpha1.ReportedValue{}
+	for _, value := range last {
+		values[value.Name] = value
+	}
+
+	for name, result := range readings {
+		value := formatValue(result)
+		if lastValue, ok := values[name]; ok && lastValue.Value == value {
+			continue
+		}
+
+		values[name] = v1alpha1.ReportedValue{
+			Name:      name,
+			Value:     value,
+			Timestamp: metav1.NewTime(time.Unix(0, result.CreateTimestamp).UTC().Truncate(time.Second)),
+		}
+	}
+
+	reported := []v1alpha1.ReportedValue{}
+	for _, value := range values {
+		reported = append(reported, value)
+	}
+	sort.Slice(reported, func(i, j int) bool { return reported[i].Name < reported[j].Name })
+
+	if len(reported) == 0 {
+		return nil
+	}
+	return reported
+}
+
+func formatValue(result util.Result) string {
+	val := util.ToJSONValue(result)
+	if str, ok := val.(string); ok {
+		return str
+	}
+
+	data, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+	return string(data)
+}
+
+// toReportedValue returns the value that is reported once the raw value of a write request is read back from the
+// device, so a desired value is compared with the reported value in the same value space
+func toReportedValue(req util.WriteRequest) (interface{}, error) {
+	result, err := util.NewResult(req.Resource, req.Value)
+	if err != nil {
+		return nil, err
+	}
+	return result.Value, nil
+}
+
+// valuesEqual returns true if the reported value equals the desired value, the numeric values are compared with
+// a relative tolerance as they may be transformed by the resource properties
+func valuesEqual(res v1alpha1.DeviceResource, desired, reported interface{}) bool {
+	if util.IsNumericValueType(res.Properties.ValueType) {
+		d, derr := cast.ToFloat64E(desired)
+		r, rerr := cast.ToFloat64E(reported)
+		if derr == nil && rerr == nil {
+			return math.Abs(d-r) <= 1e-6*math.Max(1, math.Abs(d))
+		}
+	}
+
+	return reflect.DeepEqual(desired, reported)
+}
+
+// toStateConditions converts the device state to the conditions, the condition messages do not contain the
+// counters and times of the state, so the conditions are only changed when the device state is changed
+func toStateConditions(state *util.DeviceState) (metav1.Condition, metav1.Condition) {
+	if state == nil {
+		return metav1.Condition{
+			Type:    DeviceConditionConnected,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "DeviceStateUnknown",
+			Message: "The device state is not reported by its driver",
+		}, metav1.Condition{
+			Type:    DeviceConditionDataFlowing,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "DeviceStateUnknown",
+			Message: "The device state is not reported by its driver",
+		}
+	}
+
+	connectedCondition := metav1.Condition{
+		Type:    DeviceConditionConnected,
+		Status:  metav1.ConditionTrue,
+		Reason:  "DeviceConnected",
+		Message: fmt.Sprintf("Device is connected, subscribed=%t", state.Subscribed),
+	}
+	if !state.Connected {
+		connectedCondition.Status = metav1.ConditionFalse
+		connectedCondition.Reason = "DeviceDisconnected"
+		connectedCondition.Message = "Device is disconnected"
+	}
+
+	dataFlowingCondition := metav1.Condition{
+		Type:   DeviceConditionDataFlowing,
+		Status: metav1.ConditionFalse,
+		Reason: "NoDataReceived",
+	}
+	switch {
+	case state.LastSeen.IsZero():
+		dataFlowingCondition.Message = "No data is received from the device"
+	case time.Since(state.LastSeen) > dataFlowingTimeout:
+		dataFlowingCondition.Message = fmt.Sprintf("No data is received from the device in the last %s", dataFlowingTimeout)
+	default:
+		dataFlowingCondition.Status = metav1.ConditionTrue
+		dataFlowingCondition.Reason = "DataReceived"
+		dataFlowingCondition.Message = "The data is received from the device"
+	}
+
+	return connectedCondition, dataFlowingCondition
+}
+
+// toLiveState converts the device state to the live state of the device status, the last data time is truncated
+// to seconds and it is reported once the device is resynced, so the status is not patched by each reading
+func toLiveState(state *util.DeviceState) *v1alpha1.DeviceLiveState {
+	if state == nil {
+		return nil
+	}
+
+	liveState := &v1alpha1.DeviceLiveState{
+		ErrorCount:    state.ErrorCount,
+		RejectedCount: state.RejectedCount,
+		LastError:     state.LastError,
+	}
+
+	if !state.LastSeen.IsZero() {
+		lastDataTime := metav1.NewTime(state.LastSeen.UTC().Truncate(time.Second))
+		liveState.LastDataTime = &lastDataTime
+	}
+
+	return liveState
 }
@@ -0,0 +1,261 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"open-cluster-management.io/addon-framework/pkg/basecontroller/factory"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/addon/patcher"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	fakedeviceclient "open-cluster-management-io/addon-contrib/device-addon/pkg/client/clientset/versioned/fake"
+	devicelisterv1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/client/listers/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/equipment"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+func TestToStateConditions(t *testing.T) {
+	cases := []struct {
+		name                string
+		state               *util.DeviceState
+		expectedConnected   metav1.ConditionStatus
+		expectedDataFlowing metav1.ConditionStatus
+		expectedLastData    bool
+	}{
+		{
+			name:                "unknown",
+			expectedConnected:   metav1.ConditionUnknown,
+			expectedDataFlowing: metav1.ConditionUnknown,
+		},
+		{
+			name:                "no data",
+			state:               &util.DeviceState{Connected: true},
+			expectedConnected:   metav1.ConditionTrue,
+			expectedDataFlowing: metav1.ConditionFalse,
+		},
+		{
+			name:                "data flowing",
+			state:               &util.DeviceState{Connected: true, LastSeen: time.Now()},
+			expectedConnected:   metav1.ConditionTrue,
+			expectedDataFlowing: metav1.ConditionTrue,
+			expectedLastData:    true,
+		},
+		{
+			name:                "data stopped",
+			state:               &util.DeviceState{LastSeen: time.Now().Add(-2 * dataFlowingTimeout), ErrorCount: 3},
+			expectedConnected:   metav1.ConditionFalse,
+			expectedDataFlowing: metav1.ConditionFalse,
+			expectedLastData:    true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			connected, dataFlowing := toStateConditions(c.state)
+			if connected.Status != c.expectedConnected {
+				t.Errorf("expected connected %s, but got %s", c.expectedConnected, connected.Status)
+			}
+			if dataFlowing.Status != c.expectedDataFlowing {
+				t.Errorf("expected data flowing %s, but got %s", c.expectedDataFlowing, dataFlowing.Status)
+			}
+
+			liveState := toLiveState(c.state)
+			if c.state == nil {
+				if liveState != nil {
+					t.Errorf("expected no live state, but got %v", liveState)
+				}
+				return
+			}
+			if (liveState.LastDataTime != nil) != c.expectedLastData {
+				t.Errorf("expected last data time %t, but got %v", c.expectedLastData, liveState.LastDataTime)
+			}
+			if liveState.ErrorCount != c.state.ErrorCount {
+				t.Errorf("expected error count %d, but got %d", c.state.ErrorCount, liveState.ErrorCount)
+			}
+		})
+	}
+}
+
+func TestStateConditionsAreStable(t *testing.T) {
+	state := &util.DeviceState{Connected: true, LastSeen: time.Now(), ReadingCount: 1}
+	connected, dataFlowing := toStateConditions(state)
+
+	// the new readings do not change the conditions, but the last data time follows the last reading
+	state.LastSeen = state.LastSeen.Add(30 * time.Second)
+	state.ReadingCount = 100
+	newConnected, newDataFlowing := toStateConditions(state)
+	newLiveState := toLiveState(state)
+
+	if !equality.Semantic.DeepEqual(connected, newConnected) || !equality.Semantic.DeepEqual(dataFlowing, newDataFlowing) {
+		t.Errorf("expected the conditions are not changed, but got %v, %v", newConnected, newDataFlowing)
+	}
+	expectedLastDataTime := metav1.NewTime(state.LastSeen.UTC().Truncate(time.Second))
+	if newLiveState.LastDataTime == nil || !newLiveState.LastDataTime.Equal(&expectedLastDataTime) {
+		t.Errorf("expected the last data time %v, but got %v", expectedLastDataTime, newLiveState.LastDataTime)
+	}
+}
+
+func TestDesiredValuesEqual(t *testing.T) {
+	scale := 0.1
+	offset := -40.0
+	cases := []struct {
+		name     string
+		props    v1alpha1.ResourceProperties
+		desired  interface{}
+		reported interface{}
+		expected bool
+	}{
+		{
+			name:     "no transformation",
+			props:    v1alpha1.ResourceProperties{ReadWrite: "RW", ValueType: util.ValueTypeInt32},
+			desired:  10,
+			reported: int32(10),
+			expected: true,
+		},
+		{
+			name:     "scaled float",
+			props:    v1alpha1.ResourceProperties{ReadWrite: "RW", ValueType: util.ValueTypeFloat32, Scale: &scale},
+			desired:  21.5,
+			reported: float32(21.5),
+			expected: true,
+		},
+		{
+			name: "scaled and offset",
+			props: v1alpha1.ResourceProperties{
+				ReadWrite: "RW", ValueType: util.ValueTypeFloat64, Scale: &scale, Offset: &offset},
+			desired:  -18.5,
+			reported: -18.5,
+			expected: true,
+		},
+		{
+			name:     "drifted",
+			props:    v1alpha1.ResourceProperties{ReadWrite: "RW", ValueType: util.ValueTypeFloat32, Scale: &scale},
+			desired:  21.5,
+			reported: float32(20),
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			device := v1alpha1.DeviceConfig{
+				Name:    "device1",
+				Profile: v1alpha1.DeviceProfileSpec{DeviceResources: []v1alpha1.DeviceResource{{Name: "setpoint", Properties: c.props}}},
+			}
+			requests, err := util.ToWriteRequests(device, util.Command{
+				DeviceName:    "device1",
+				DeviceCommand: "setpoint",
+				Attributes:    util.Attributes{"setpoint": c.desired},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected, err := toReportedValue(requests[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if actual := valuesEqual(requests[0].Resource, expected, c.reported); actual != c.expected {
+				t.Errorf("expected %v, but got %v, the desired value is reported as %v", c.expected, actual, expected)
+			}
+		})
+	}
+}
+
+func TestReportedValuesAreStable(t *testing.T) {
+	now := time.Now()
+	readings := map[string]util.Result{
+		"temperature": {Name: "temperature", Type: util.ValueTypeFloat64, Value: 21.5, CreateTimestamp: now.UnixNano()},
+	}
+	reported := toReportedValues(readings, nil)
+
+	// the unchanged readings do not change the reported values
+	readings["temperature"] = util.Result{
+		Name: "temperature", Type: util.ValueTypeFloat64, Value: 21.5, CreateTimestamp: now.Add(10 * time.Second).UnixNano()}
+	if actual := toReportedValues(readings, reported); !equality.Semantic.DeepEqual(reported, actual) {
+		t.Errorf("expected the reported values are not changed, but got %v", actual)
+	}
+
+	// the changed readings are reported with their timestamps
+	readings["temperature"] = util.Result{
+		Name: "temperature", Type: util.ValueTypeFloat64, Value: 22.0, CreateTimestamp: now.Add(20 * time.Second).UnixNano()}
+	actual := toReportedValues(readings, reported)
+	if len(actual) != 1 || actual[0].Value != "22" || !actual[0].Timestamp.After(reported[0].Timestamp.Time) {
+		t.Errorf("expected the changed value is reported, but got %v", actual)
+	}
+}
+
+func TestSyncDeletingDeviceWithoutDriver(t *testing.T) {
+	driverConfig := v1alpha1.DriverConfig{DriverType: "modbus"}
+	deviceConfig := v1alpha1.DeviceConfig{
+		Name:               "plc",
+		DriverType:         "modbus",
+		ProtocolProperties: v1alpha1.Values{Data: map[string]interface{}{"address": "127.0.0.1:1"}},
+	}
+
+	e := equipment.NewEquipment()
+	defer e.Stop()
+	if err := e.InstallDriver(driverConfig); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.AddDevice(deviceConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	// the driver is deleted before the device
+	if err := e.UnInstallDriver(driverConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	now := metav1.NewTime(time.Now())
+	device := &v1alpha1.Device{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "plc",
+			Namespace:         "cluster1",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{v1alpha1.DeviceFinalizer},
+		},
+		Spec: v1alpha1.DeviceSpec{DeviceConfig: deviceConfig},
+	}
+
+	deviceStore := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := deviceStore.Add(device); err != nil {
+		t.Fatal(err)
+	}
+	client := fakedeviceclient.NewSimpleClientset(device)
+
+	c := &devicesController{
+		client:      client,
+		lister:      devicelisterv1alpha1.NewDeviceLister(deviceStore),
+		equipment:   e,
+		clusterName: "cluster1",
+		writes:      make(map[string]map[string]desiredWrite),
+		patcher: patcher.NewPatcher[*v1alpha1.Device, v1alpha1.DeviceSpec, v1alpha1.DeviceStatus](
+			client.EdgeV1alpha1().Devices("cluster1")),
+	}
+
+	if err := c.sync(context.TODO(), factory.NewSyncContext("test"), "cluster1/plc"); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := client.EdgeV1alpha1().Devices("cluster1").Get(context.TODO(), "plc", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated.Finalizers) != 0 {
+		t.Errorf("expected the finalizer is removed, but got %v", updated.Finalizers)
+	}
+
+	// the removed device is not added back once the driver is installed again
+	if err := e.InstallDriver(driverConfig); err != nil {
+		t.Fatal(err)
+	}
+	if state := e.GetDeviceState("plc"); state != nil {
+		t.Errorf("expected the device is removed, but got %v", state)
+	}
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"open-cluster-management.io/addon-framework/pkg/basecontroller/factory"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	deviceclient "open-cluster-management-io/addon-contrib/device-addon/pkg/client/clientset/versioned"
+	deviceinformerv1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/client/informers/externalversions/apis/v1alpha1"
+	devicelisterv1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/client/listers/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/equipment"
+)
+
+// discoveryController proposes the devices that are discovered by the drivers on the hub, a discovered device is
+// created with the discovered label, it is added to its driver after the operator accepts it by setting the
+// label to accepted or removing the label, or it is never proposed again if the label is set to rejected.
+type discoveryController struct {
+	client      deviceclient.Interface
+	lister      devicelisterv1alpha1.DeviceLister
+	equipment   *equipment.Equipment
+	clusterName string
+}
+
+func NewDiscoveryController(
+	clusterName string,
+	client deviceclient.Interface,
+	deviceInformer deviceinformerv1alpha1.DeviceInformer,
+	equipment *equipment.Equipment,
+	interval time.Duration,
+) factory.Controller {
+	c := &discoveryController{
+		client:      client,
+		lister:      deviceInformer.Lister(),
+		equipment:   equipment,
+		clusterName: clusterName,
+	}
+
+	return factory.New().
+		WithBareInformers(deviceInformer.Informer()).
+		WithSync(c.sync).
+		ResyncEvery(interval).
+		ToController("discovery-controller")
+}
+
+func (c *discoveryController) sync(ctx context.Context, syncCtx factory.SyncContext, key string) error {
+	for _, config := range c.equipment.DiscoverDevices(ctx) {
+		if errs := validation.IsDNS1123Subdomain(config.Name); len(errs) != 0 {
+			klog.Errorf("the discovered device name %s is invalid, %v", config.Name, errs)
+			continue
+		}
+
+		device, err := c.lister.Devices(c.clusterName).Get(config.Name)
+		if errors.IsNotFound(err) {
+			klog.Infof("Propose the discovered device %s/%s", c.clusterName, config.Name)
+			_, err := c.client.EdgeV1alpha1().Devices(c.clusterName).Create(ctx, &v1alpha1.Device{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      config.Name,
+					Namespace: c.clusterName,
+					Labels:    map[string]string{v1alpha1.DiscoveredLabel: v1alpha1.DiscoveredProposed},
+				},
+				Spec: v1alpha1.DeviceSpec{DeviceConfig: config},
+			}, metav1.CreateOptions{})
+			if err != nil && !errors.IsAlreadyExists(err) {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// only the proposed device is refreshed, the accepted and the user defined devices are kept as they are
+		if device.Labels[v1alpha1.DiscoveredLabel] != v1alpha1.DiscoveredProposed {
+			continue
+		}
+
+		if equality.Semantic.DeepEqual(device.Spec.DeviceConfig, config) {
+			continue
+		}
+
+		klog.Infof("Refresh the proposed device %s/%s", c.clusterName, config.Name)
+		newDevice := device.DeepCopy()
+		newDevice.Spec.DeviceConfig = config
+		if _, err := c.client.EdgeV1alpha1().Devices(c.clusterName).Update(ctx, newDevice, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -7,12 +7,15 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	corev1informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	"open-cluster-management.io/addon-framework/pkg/basecontroller/factory"
 
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/addon/patcher"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/addon/spoke/credentials"
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
 	deviceclient "open-cluster-management-io/addon-contrib/device-addon/pkg/client/clientset/versioned"
 	deviceinformerv1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/client/informers/externalversions/apis/v1alpha1"
@@ -20,12 +23,11 @@ import (
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/equipment"
 )
 
-const driverFinalizer = "edge.open-cluster-management.io/driver-cleanup"
-
 type driversController struct {
 	client      deviceclient.Interface
 	lister      devicelisterv1alpha1.DriverLister
 	equipment   *equipment.Equipment
+	resolver    *credentials.Resolver
 	clusterName string
 	patcher     patcher.Patcher[*v1alpha1.Driver, v1alpha1.DriverSpec, v1alpha1.DriverStatus]
 }
@@ -34,12 +36,15 @@ func NewDriversController(
 	clusterName string,
 	client deviceclient.Interface,
 	driverInformer deviceinformerv1alpha1.DriverInformer,
+	secretInformer corev1informers.SecretInformer,
+	resolver *credentials.Resolver,
 	equipment *equipment.Equipment,
 ) factory.Controller {
 	c := &driversController{
 		client:      client,
 		lister:      driverInformer.Lister(),
 		equipment:   equipment,
+		resolver:    resolver,
 		clusterName: clusterName,
 		patcher: patcher.NewPatcher[*v1alpha1.Driver, v1alpha1.DriverSpec, v1alpha1.DriverStatus](
 			client.EdgeV1alpha1().Drivers(clusterName)),
@@ -50,6 +55,7 @@ func NewDriversController(
 			key, _ := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
 			return []string{key}
 		}, driverInformer.Informer()).
+		WithInformersQueueKeysFunc(c.secretToDriverKeys, secretInformer.Informer()).
 		WithSync(c.sync).
 		ToController("driver-controller")
 }
@@ -77,10 +83,10 @@ func (c *driversController) sync(ctx context.Context, syncCtx factory.SyncContex
 			return err
 		}
 
-		return c.patcher.RemoveFinalizer(ctx, driver, driverFinalizer)
+		return c.patcher.RemoveFinalizer(ctx, driver, v1alpha1.DriverFinalizer)
 	}
 
-	updated, err := c.patcher.AddFinalizer(ctx, driver, driverFinalizer)
+	updated, err := c.patcher.AddFinalizer(ctx, driver, v1alpha1.DriverFinalizer)
 	if err != nil || updated {
 		return err
 	}
@@ -92,10 +98,19 @@ func (c *driversController) sync(ctx context.Context, syncCtx factory.SyncContex
 		Message: "Driver is installed",
 	}
 
-	if err := c.equipment.InstallDriver(driver.Spec.DriverConfig); err != nil {
+	driverConfig := driver.Spec.DriverConfig
+	properties, err := c.resolver.Resolve(ctx, fmt.Sprintf("driver-%s", driverConfig.DriverType), driverConfig.Properties)
+	if err != nil {
 		installedCondition.Status = metav1.ConditionFalse
-		installedCondition.Reason = "DriverNotInstalled"
-		installedCondition.Message = fmt.Sprintf("Driver is failed to install, %v", err)
+		installedCondition.Reason = "CredentialsNotResolved"
+		installedCondition.Message = fmt.Sprintf("Driver credentials are failed to resolve, %v", err)
+	} else {
+		driverConfig.Properties = properties
+		if err := c.equipment.InstallDriver(driverConfig); err != nil {
+			installedCondition.Status = metav1.ConditionFalse
+			installedCondition.Reason = "DriverNotInstalled"
+			installedCondition.Message = fmt.Sprintf("Driver is failed to install, %v", err)
+		}
 	}
 
 	newDriver := driver.DeepCopy()
@@ -104,3 +119,30 @@ func (c *driversController) sync(ctx context.Context, syncCtx factory.SyncContex
 	_, updatedErr := c.patcher.PatchStatus(ctx, newDriver, newDriver.Status, driver.Status)
 	return updatedErr
 }
+
+// secretToDriverKeys returns the keys of the drivers that reference the secret, so the drivers are reinstalled
+// with the new credentials when the secret is changed
+func (c *driversController) secretToDriverKeys(obj runtime.Object) []string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return []string{}
+	}
+
+	drivers, err := c.lister.Drivers(c.clusterName).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list drivers, %v", err)
+		return []string{}
+	}
+
+	keys := []string{}
+	for _, driver := range drivers {
+		if credentials.GetSecretName(driver.Spec.DriverConfig.Properties) != accessor.GetName() {
+			continue
+		}
+
+		key, _ := cache.MetaNamespaceKeyFunc(driver)
+		keys = append(keys, key)
+	}
+
+	return keys
+}
@@ -0,0 +1,101 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+)
+
+const (
+	// CredentialSecretProperty is the driver or message bus property that references a Secret in the cluster
+	// namespace on the hub, the keys of the Secret are saved as the credential files, e.g. username, password,
+	// ca.crt, tls.crt and tls.key
+	CredentialSecretProperty = "credentialSecret"
+
+	// CredentialLabel labels the Secrets that can be referenced as the credentials, the agent only watches and
+	// resolves the Secrets with the label edge.open-cluster-management.io/credential=true
+	CredentialLabel = "edge.open-cluster-management.io/credential"
+
+	credentialDirProperty     = "credentialDir"
+	credentialVersionProperty = "credentialVersion"
+)
+
+// DefaultCredentialsDir is the directory that the credentials are saved in on the agent
+const DefaultCredentialsDir = "/var/run/device-addon/credentials"
+
+// Resolver resolves the credential Secrets that are referenced by the drivers and message buses, the Secret data
+// is saved to a credential dir and the credential dir is set to the properties.
+type Resolver struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+	baseDir    string
+}
+
+func NewResolver(kubeClient kubernetes.Interface, namespace, baseDir string) *Resolver {
+	return &Resolver{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		baseDir:    baseDir,
+	}
+}
+
+// GetSecretName returns the referenced credential Secret name of the properties
+func GetSecretName(properties v1alpha1.Values) string {
+	secretName, ok := properties.Data[CredentialSecretProperty]
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("%s", secretName)
+}
+
+// Resolve saves the referenced Secret data to the credential dir of the owner, and returns the properties with
+// the credential dir, the Secret must have the credential label. The resource version of the Secret is also set to the properties, so the owner can be
+// restarted when the Secret is changed. The properties are returned directly if there is no Secret referenced.
+func (r *Resolver) Resolve(ctx context.Context, owner string, properties v1alpha1.Values) (v1alpha1.Values, error) {
+	secretName := GetSecretName(properties)
+	if len(secretName) == 0 {
+		return properties, nil
+	}
+
+	secret, err := r.kubeClient.CoreV1().Secrets(r.namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return properties, fmt.Errorf("failed to get the credential secret %s/%s, %v", r.namespace, secretName, err)
+	}
+
+	if secret.Labels[CredentialLabel] != "true" {
+		return properties, fmt.Errorf("the secret %s/%s is not labeled with %s=true", r.namespace, secretName, CredentialLabel)
+	}
+
+	dir := path.Join(r.baseDir, owner)
+	if err := os.RemoveAll(dir); err != nil {
+		return properties, fmt.Errorf("failed to clean the credential dir %s, %v", dir, err)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return properties, fmt.Errorf("failed to create the credential dir %s, %v", dir, err)
+	}
+
+	for key, data := range secret.Data {
+		if err := os.WriteFile(path.Join(dir, key), data, 0o600); err != nil {
+			return properties, fmt.Errorf("failed to save the credential %s, %v", key, err)
+		}
+	}
+
+	klog.Infof("The credentials of %s are saved to %s from the secret %s/%s", owner, dir, r.namespace, secretName)
+
+	resolved := v1alpha1.Values{Data: make(map[string]interface{}, len(properties.Data)+2)}
+	for key, value := range properties.Data {
+		resolved.Data[key] = value
+	}
+	resolved.Data[credentialDirProperty] = dir
+	resolved.Data[credentialVersionProperty] = secret.ResourceVersion
+
+	return resolved, nil
+}
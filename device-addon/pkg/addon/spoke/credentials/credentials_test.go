@@ -0,0 +1,57 @@
+package credentials
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+)
+
+func TestResolve(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "mqtt",
+				Namespace:       "cluster1",
+				Labels:          map[string]string{CredentialLabel: "true"},
+				ResourceVersion: "1",
+			},
+			Data: map[string][]byte{"username": []byte("user"), "password": []byte("pass")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "cluster1"},
+			Data:       map[string][]byte{"token": []byte("token")},
+		},
+	)
+
+	resolver := NewResolver(kubeClient, "cluster1", t.TempDir())
+
+	resolved, err := resolver.Resolve(context.TODO(), "driver-mqtt", v1alpha1.Values{Data: map[string]interface{}{
+		CredentialSecretProperty: "mqtt",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, _ := resolved.Data[credentialDirProperty].(string)
+	data, err := os.ReadFile(path.Join(dir, "password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "pass" {
+		t.Errorf("expected the password is saved, but got %s", string(data))
+	}
+
+	// the secret without the credential label cannot be referenced
+	if _, err := resolver.Resolve(context.TODO(), "driver-http", v1alpha1.Values{Data: map[string]interface{}{
+		CredentialSecretProperty: "other",
+	}}); err == nil {
+		t.Errorf("expected the secret without the credential label is rejected")
+	}
+}
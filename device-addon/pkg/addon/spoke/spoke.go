@@ -2,22 +2,31 @@ package spoke
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/spf13/pflag"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	addonclientset "open-cluster-management.io/api/client/addon/clientset/versioned"
+	addoninformers "open-cluster-management.io/api/client/addon/informers/externalversions"
 
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/addon/spoke/controllers"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/addon/spoke/credentials"
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
 	deviceaddonclientset "open-cluster-management-io/addon-contrib/device-addon/pkg/client/clientset/versioned"
 	deviceaddoninformers "open-cluster-management-io/addon-contrib/device-addon/pkg/client/informers/externalversions"
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/equipment"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/metrics"
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
 )
 
 const (
+	addOnName            = "device-addon"
 	defaultDataTopic     = "devices/+/data/+"
 	defaultPayloadFormat = "jsonMap"
 )
@@ -27,21 +36,35 @@ type AgentOptions struct {
 	SpokeClusterName  string
 	HubKubeConfigFile string
 	AddOnConfigFile   string
-}
-
-type messageBusList struct {
-	MessageBuses []v1alpha1.MessageBusConfig `yaml:"messageBuses" json:"messageBuses"`
+	CredentialsDir    string
+	DiscoveryInterval time.Duration
+	MetricsAddress    string
+	ReadingMetrics    bool
 }
 
 // NewAgentOptions returns the flags with default value set
 func NewAgentOptions() *AgentOptions {
-	return &AgentOptions{}
+	return &AgentOptions{
+		CredentialsDir:    credentials.DefaultCredentialsDir,
+		DiscoveryInterval: 10 * time.Minute,
+		MetricsAddress:    "127.0.0.1:8080",
+	}
 }
 
 func (o *AgentOptions) AddFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&o.SpokeClusterName, "cluster-name", o.SpokeClusterName, "Name of spoke cluster.")
 	flags.StringVar(&o.HubKubeConfigFile, "hub-kubeconfig", o.HubKubeConfigFile, "Location of kubeconfig file to connect to hub cluster.")
 	flags.StringVar(&o.AddOnConfigFile, "addonconfig", o.AddOnConfigFile, "Location of add-on config file.")
+	flags.StringVar(&o.CredentialsDir, "credentials-dir", o.CredentialsDir,
+		"Directory to save the credentials that are referenced by the drivers and message buses.")
+	flags.DurationVar(&o.DiscoveryInterval, "discovery-interval", o.DiscoveryInterval,
+		"Interval to discover the devices with the drivers, the discovery is disabled if it is 0.")
+	flags.StringVar(&o.MetricsAddress, "metrics-address", o.MetricsAddress,
+		"Address to serve the metrics, it is bound to the localhost by default, "+
+			"the metrics are not authenticated, so they should be exposed with an authenticating proxy, "+
+			"e.g. kube-rbac-proxy. The metrics are not served if it is empty.")
+	flags.BoolVar(&o.ReadingMetrics, "reading-metrics", o.ReadingMetrics,
+		"Expose the latest numeric readings of the devices as the metrics.")
 }
 
 // RunAgent starts the controllers on agent to process work from hub.
@@ -56,22 +79,74 @@ func (o *AgentOptions) RunAgent(ctx context.Context, kubeconfig *rest.Config) er
 		return err
 	}
 
-	config, err := o.LoadAddOnConfig()
+	hubKubeClient, err := kubernetes.NewForConfig(hubRestConfig)
+	if err != nil {
+		return err
+	}
+
+	hubAddOnClient, err := addonclientset.NewForConfig(hubRestConfig)
+	if err != nil {
+		return err
+	}
+
+	resolver := credentials.NewResolver(hubKubeClient, o.SpokeClusterName, o.CredentialsDir)
+
+	config, err := o.loadAddOnConfig(ctx, resolver)
 	if err != nil {
 		return err
 	}
 
 	equipment := equipment.NewEquipment()
-	if err := equipment.Start(ctx, config); err != nil {
+	if err := equipment.SetRules(config.Rules); err != nil {
+		return err
+	}
+
+	if err := equipment.Start(ctx, config.MessageBuses); err != nil {
 		return err
 	}
 
+	if len(o.MetricsAddress) != 0 {
+		if o.ReadingMetrics {
+			metrics.EnableReadingValues()
+		}
+
+		if err := equipment.RegisterMetrics(); err != nil {
+			return err
+		}
+
+		go metrics.Serve(ctx, o.MetricsAddress)
+	}
+
 	deviceinformerFactory := deviceaddoninformers.NewSharedInformerFactory(deviceClient, 10*time.Minute)
+	// only the credential Secrets are watched, the other Secrets in the cluster namespace are not cached
+	hubKubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(
+		hubKubeClient, 10*time.Minute, kubeinformers.WithNamespace(o.SpokeClusterName),
+		kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = fmt.Sprintf("%s=true", credentials.CredentialLabel)
+		}))
+	hubAddOnInformerFactory := addoninformers.NewSharedInformerFactoryWithOptions(
+		hubAddOnClient, 10*time.Minute, addoninformers.WithNamespace(o.SpokeClusterName))
+
+	// the add-on config is mounted as a file when the agent is deployed, once the agent is started, it is
+	// watched through the hub, so the changes are applied without waiting for the file to be updated
+	addOnConfigController := controllers.NewAddOnConfigController(
+		o.SpokeClusterName,
+		addOnName,
+		deviceinformerFactory.Edge().V1alpha1().DeviceAddOnConfigs(),
+		hubAddOnInformerFactory.Addon().V1alpha1().ManagedClusterAddOns(),
+		hubKubeInformerFactory.Core().V1().Secrets(),
+		resolver,
+		equipment,
+		defaultAddOnConfig(),
+		config,
+	)
 
 	driverController := controllers.NewDriversController(
 		o.SpokeClusterName,
 		deviceClient,
 		deviceinformerFactory.Edge().V1alpha1().Drivers(),
+		hubKubeInformerFactory.Core().V1().Secrets(),
+		resolver,
 		equipment,
 	)
 
@@ -79,11 +154,26 @@ func (o *AgentOptions) RunAgent(ctx context.Context, kubeconfig *rest.Config) er
 		o.SpokeClusterName,
 		deviceClient,
 		deviceinformerFactory.Edge().V1alpha1().Devices(),
+		deviceinformerFactory.Edge().V1alpha1().DeviceProfiles(),
 		equipment,
 	)
 
+	if o.DiscoveryInterval > 0 {
+		discoveryController := controllers.NewDiscoveryController(
+			o.SpokeClusterName,
+			deviceClient,
+			deviceinformerFactory.Edge().V1alpha1().Devices(),
+			equipment,
+			o.DiscoveryInterval,
+		)
+		go discoveryController.Run(ctx, 1)
+	}
+
 	go deviceinformerFactory.Start(ctx.Done())
+	go hubKubeInformerFactory.Start(ctx.Done())
+	go hubAddOnInformerFactory.Start(ctx.Done())
 
+	go addOnConfigController.Run(ctx, 1)
 	go deviceController.Run(ctx, 1)
 	go driverController.Run(ctx, 1)
 
@@ -92,17 +182,24 @@ func (o *AgentOptions) RunAgent(ctx context.Context, kubeconfig *rest.Config) er
 	return nil
 }
 
-func (o *AgentOptions) LoadAddOnConfig() ([]v1alpha1.MessageBusConfig, error) {
+// LoadAddOnConfig loads the message buses and rules from the add-on config file, the build-in MQTT broker is
+// used if there is no add-on config file
+func (o *AgentOptions) LoadAddOnConfig() (*v1alpha1.DeviceAddOnConfigSpec, error) {
 	if len(o.AddOnConfigFile) != 0 {
-		config := &messageBusList{}
+		config := &v1alpha1.DeviceAddOnConfigSpec{}
 		if err := util.LoadConfig(o.AddOnConfigFile, config); err != nil {
 			return nil, err
 		}
 
-		return config.MessageBuses, nil
+		return config, nil
 	}
 
-	return []v1alpha1.MessageBusConfig{
+	return defaultAddOnConfig(), nil
+}
+
+// defaultAddOnConfig returns the add-on config that uses the build-in MQTT broker
+func defaultAddOnConfig() *v1alpha1.DeviceAddOnConfigSpec {
+	return &v1alpha1.DeviceAddOnConfigSpec{MessageBuses: []v1alpha1.MessageBusConfig{
 		{
 			MessageBusType: "mqtt",
 			Enabled:        true,
@@ -113,5 +210,23 @@ func (o *AgentOptions) LoadAddOnConfig() ([]v1alpha1.MessageBusConfig, error) {
 				},
 			},
 		},
-	}, nil
+	}}
+}
+
+// loadAddOnConfig loads the add-on config and resolves the credentials of the message buses
+func (o *AgentOptions) loadAddOnConfig(ctx context.Context, resolver *credentials.Resolver) (*v1alpha1.DeviceAddOnConfigSpec, error) {
+	config, err := o.LoadAddOnConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, msgBus := range config.MessageBuses {
+		properties, err := resolver.Resolve(ctx, fmt.Sprintf("messagebus-%s", msgBus.MessageBusType), msgBus.Properties)
+		if err != nil {
+			return nil, err
+		}
+		config.MessageBuses[i].Properties = properties
+	}
+
+	return config, nil
 }
@@ -38,6 +38,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&DriverList{},
 		&Device{},
 		&DeviceList{},
+		&DeviceProfile{},
+		&DeviceProfileList{},
+		&DeviceSet{},
+		&DeviceSetList{},
 	)
 	metav1.AddToGroupVersion(scheme, GroupVersion)
 	return nil
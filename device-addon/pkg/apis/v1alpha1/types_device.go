@@ -4,9 +4,27 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// DiscoveredLabel is set on the devices that are discovered by the device drivers, the discovered devices are
+	// proposed and they are not added to their drivers until they are accepted by the operator
+	DiscoveredLabel = "edge.open-cluster-management.io/discovered"
+
+	DiscoveredProposed = "proposed"
+	DiscoveredAccepted = "accepted"
+	DiscoveredRejected = "rejected"
+
+	// DeviceFinalizer is added to the devices by the agent, the devices are removed from their drivers before
+	// they are deleted
+	DeviceFinalizer = "edge.open-cluster-management.io/device-cleanup"
+)
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Driver",type=string,JSONPath=`.spec.driverType`
+// +kubebuilder:printcolumn:name="Connected",type=string,JSONPath=`.status.conditions[?(@.type=="Connected")].status`
+// +kubebuilder:printcolumn:name="DataFlowing",type=string,JSONPath=`.status.conditions[?(@.type=="DataFlowing")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Device is the Schema for the devices API
 type Device struct {
@@ -33,6 +51,18 @@ type DeviceList struct {
 
 type DeviceSpec struct {
 	DeviceConfig `json:",inline"`
+
+	// ProfileRef represents the name of a DeviceProfile in the same namespace, the device resources and commands
+	// of the referenced profile are merged into the profile of the device, the ones in the profile of the device
+	// override the ones of the referenced profile with the same names.
+	// +optional
+	ProfileRef string `json:"profileRef,omitempty"`
+
+	// Desired represents the desired values of the writable device resources, the key is the device resource
+	// name, the values are written to the device until the reported values converge to them.
+	// +optional
+	// +kubebuilder:validation:XPreserveUnknownFields
+	Desired Values `json:"desired,omitempty"`
 }
 
 type DeviceStatus struct {
@@ -41,6 +71,47 @@ type DeviceStatus struct {
 	// +patchStrategy=merge
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// Reported represents the last values that are reported by the device resources
+	// +optional
+	Reported []ReportedValue `json:"reported,omitempty"`
+
+	// State represents the live state of the device that is reported by its driver
+	// +optional
+	State *DeviceLiveState `json:"state,omitempty"`
+}
+
+type DeviceLiveState struct {
+	// LastDataTime represents the time that the last data is received from the device
+	// +optional
+	LastDataTime *metav1.Time `json:"lastDataTime,omitempty"`
+
+	// ErrorCount represents the number of the errors that occurred when the driver handles the device
+	// +optional
+	ErrorCount int64 `json:"errorCount,omitempty"`
+
+	// RejectedCount represents the number of the readings that are rejected by the validation of their device
+	// resources
+	// +optional
+	RejectedCount int64 `json:"rejectedCount,omitempty"`
+
+	// LastError represents the last error that occurred when the driver handles the device
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+type ReportedValue struct {
+	// Name represents the device resource name
+	// +required
+	Name string `json:"name"`
+
+	// Value represents the last reported value of the device resource
+	// +required
+	Value string `json:"value"`
+
+	// Timestamp represents the time that the value is reported, it is not updated until the value is changed
+	// +required
+	Timestamp metav1.Time `json:"timestamp"`
 }
 
 // 'R' 'W' 'RW' 'WR' are supported
@@ -74,20 +145,26 @@ type DeviceConfig struct {
 
 	// Profile represents the device data profile
 	// +required
-	Profile DeviceProfile `yaml:"profile" json:"profile"`
+	Profile DeviceProfileSpec `yaml:"profile" json:"profile"`
 }
 
-type DeviceProfile struct {
+// DeviceProfileSpec represents the device resources and commands of a device, it is set in the device spec or
+// shared by the devices that reference the same DeviceProfile
+type DeviceProfileSpec struct {
 	// DeviceResources represents device supporting resources
 	// +optional
 	DeviceResources []DeviceResource `yaml:"deviceResources" json:"deviceResources"`
 
-	//DeviceCommands []DeviceCommand `yaml:"deviceCommands" json:"deviceCommands"`
+	// DeviceCommands represents device supporting commands
+	// +optional
+	DeviceCommands []DeviceCommand `yaml:"deviceCommands,omitempty" json:"deviceCommands,omitempty"`
 }
 
 type ResourceProperties struct {
-	// ReadWrite
-	ReadWrite ReadWrite `yaml:"readWrite" json:"readWrite"`
+	// ReadWrite represents the device resource permission, default is RW
+	// +optional
+	// +kubebuilder:default=RW
+	ReadWrite ReadWrite `yaml:"readWrite,omitempty" json:"readWrite,omitempty"`
 
 	// ValueType
 	ValueType string `yaml:"valueType" json:"valueType"`
@@ -100,11 +177,12 @@ type ResourceProperties struct {
 	// +optional
 	DefaultValue string `yaml:"defaultValue,omitempty" json:"defaultValue,omitempty"`
 
-	// Minimum
+	// Assertion represents the expected value of a scalar resource, an alarm is raised once the reading
+	// does not equal it
 	// +optional
 	Assertion string `yaml:"assertion,omitempty" json:"assertion,omitempty"`
 
-	// Minimum
+	// MediaType represents the media type of a Binary resource, default is application/octet-stream
 	// +optional
 	MediaType string `yaml:"mediaType,omitempty" json:"mediaType,omitempty"`
 
@@ -161,13 +239,27 @@ type DeviceResource struct {
 	Attributes Values `yaml:"attributes,omitempty" json:"attributes,omitempty"`
 }
 
-// type DeviceCommand struct {
-// 	Name      string                  `yaml:"name" json:"name"`
-// 	ReadWrite ReadWrite               `yaml:"readWrite" json:"readWrite"`
-// 	Resources []DeviceCommandResource `yaml:"resources" json:"resources"`
-// }
+type DeviceCommand struct {
+	// Name represents the device command name
+	// +required
+	Name string `yaml:"name" json:"name"`
+
+	// ReadWrite represents the device command permission, default is RW
+	// +optional
+	// +kubebuilder:default=RW
+	ReadWrite ReadWrite `yaml:"readWrite,omitempty" json:"readWrite,omitempty"`
+
+	// Resources represents the device resources that are operated by the command
+	// +required
+	Resources []DeviceCommandResource `yaml:"resources" json:"resources"`
+}
+
+type DeviceCommandResource struct {
+	// DeviceResource represents the name of the operated device resource
+	// +required
+	DeviceResource string `yaml:"deviceResource" json:"deviceResource"`
 
-// type DeviceCommandResource struct {
-// 	DeviceResource string `yaml:"deviceResource" json:"deviceResource"`
-// 	DefaultValue   string `yaml:"defaultValue" json:"defaultValue"`
-// }
+	// DefaultValue represents the value that is used when the command does not give a value
+	// +optional
+	DefaultValue string `yaml:"defaultValue,omitempty" json:"defaultValue,omitempty"`
+}
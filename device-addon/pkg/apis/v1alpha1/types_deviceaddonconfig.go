@@ -33,6 +33,11 @@ type DeviceAddOnConfigList struct {
 
 type DeviceAddOnConfigSpec struct {
 	MessageBuses []MessageBusConfig `yaml:"messageBuses" json:"messageBuses"`
+
+	// Rules process the device readings on the edge before they are published to the message buses, the rules
+	// are applied in order, a reading is processed by the rules that match its device and resource.
+	// +optional
+	Rules []ProcessingRule `yaml:"rules,omitempty" json:"rules,omitempty"`
 }
 
 type DeviceAddOnConfigSpecStatus struct {
@@ -48,3 +53,79 @@ type MessageBusConfig struct {
 	MessageBusType string `yaml:"type" json:"type"`
 	Properties     Values `yaml:"properties" json:"properties"`
 }
+
+// ProcessingRule transforms or suppresses the readings of the matched device resources, one and only one of
+// deadband, downsample, aggregate and threshold should be set.
+type ProcessingRule struct {
+	// Name of the rule
+	// +required
+	Name string `yaml:"name" json:"name"`
+
+	// Devices are the names of the devices that the rule applies to, the shell patterns are supported, e.g.
+	// sensor-*, the rule applies to all of the devices if it is not set.
+	// +optional
+	Devices []string `yaml:"devices,omitempty" json:"devices,omitempty"`
+
+	// Resources are the names of the device resources that the rule applies to, the shell patterns are
+	// supported, the rule applies to all of the resources if it is not set.
+	// +optional
+	Resources []string `yaml:"resources,omitempty" json:"resources,omitempty"`
+
+	// Deadband publishes a reading only if it changes beyond the deadband from the last published reading
+	// +optional
+	Deadband *DeadbandRule `yaml:"deadband,omitempty" json:"deadband,omitempty"`
+
+	// Downsample publishes one reading at most in an interval
+	// +optional
+	Downsample *DownsampleRule `yaml:"downsample,omitempty" json:"downsample,omitempty"`
+
+	// Aggregate publishes the aggregate of the readings in a window instead of the readings
+	// +optional
+	Aggregate *AggregateRule `yaml:"aggregate,omitempty" json:"aggregate,omitempty"`
+
+	// Threshold raises an alarm once a reading crosses the thresholds
+	// +optional
+	Threshold *ThresholdRule `yaml:"threshold,omitempty" json:"threshold,omitempty"`
+}
+
+type DeadbandRule struct {
+	// Absolute is the absolute change of a numeric reading that is published, a reading is published on any
+	// change if both of the absolute and percent are not set.
+	// +optional
+	Absolute float64 `yaml:"absolute,omitempty" json:"absolute,omitempty"`
+
+	// Percent is the change of a numeric reading that is published in percent of the last published reading
+	// +optional
+	Percent float64 `yaml:"percent,omitempty" json:"percent,omitempty"`
+
+	// MaxInterval publishes the unchanged reading once the last reading is published for the interval, e.g. 5m
+	// +optional
+	MaxInterval string `yaml:"maxInterval,omitempty" json:"maxInterval,omitempty"`
+}
+
+type DownsampleRule struct {
+	// Interval is the minimum interval between two published readings, e.g. 10s
+	// +required
+	Interval string `yaml:"interval" json:"interval"`
+}
+
+type AggregateRule struct {
+	// Window is the duration of the window to aggregate the readings, e.g. 60s
+	// +required
+	Window string `yaml:"window" json:"window"`
+
+	// Function is the aggregate function, it can be avg, min or max, default is avg
+	// +optional
+	// +kubebuilder:validation:Enum=avg;min;max
+	Function string `yaml:"function,omitempty" json:"function,omitempty"`
+}
+
+type ThresholdRule struct {
+	// High raises an alarm once a reading is greater than it
+	// +optional
+	High *float64 `yaml:"high,omitempty" json:"high,omitempty"`
+
+	// Low raises an alarm once a reading is less than it
+	// +optional
+	Low *float64 `yaml:"low,omitempty" json:"low,omitempty"`
+}
@@ -0,0 +1,28 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeviceProfile is the schema for the device profile API, it is referenced by the devices that have the same
+// resources and commands
+type DeviceProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// spec holds the device resources and commands of the profile.
+	// +kubebuilder:validation:Required
+	// +required
+	Spec DeviceProfileSpec `json:"spec"`
+}
+
+// DeviceProfileList is a list of DeviceProfile
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DeviceProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []DeviceProfile `json:"items"`
+}
@@ -0,0 +1,137 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DeviceSetNamespaceLabel and DeviceSetNameLabel are set on the devices and drivers that are created by a
+	// device set
+	DeviceSetNamespaceLabel = "edge.open-cluster-management.io/device-set-namespace"
+	DeviceSetNameLabel      = "edge.open-cluster-management.io/device-set-name"
+
+	// ClusterNameParameter is the build-in parameter of the templates, it is the name of the managed cluster
+	ClusterNameParameter = "clusterName"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Placement",type=string,JSONPath=`.spec.placementRef.name`
+// +kubebuilder:printcolumn:name="Clusters",type=integer,JSONPath=`.status.clusters`
+// +kubebuilder:printcolumn:name="Applied",type=integer,JSONPath=`.status.appliedClusters`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// DeviceSet is the schema for the device set API, it creates the drivers and devices from its templates in the
+// namespaces of the managed clusters that are selected by a placement
+type DeviceSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// spec holds the templates of the drivers and devices and the placement.
+	// +kubebuilder:validation:Required
+	// +required
+	Spec DeviceSetSpec `json:"spec"`
+
+	// status holds the state of the device set.
+	// +optional
+	Status DeviceSetStatus `json:"status,omitempty"`
+}
+
+// DeviceSetList is a list of DeviceSet
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DeviceSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []DeviceSet `json:"items"`
+}
+
+type DeviceSetSpec struct {
+	// PlacementRef represents the Placement in the same namespace, the drivers and devices are created in the
+	// namespaces of the managed clusters that are selected by the placement, and they are deleted once the
+	// clusters are not selected.
+	// +kubebuilder:validation:Required
+	// +required
+	PlacementRef PlacementRef `json:"placementRef"`
+
+	// Parameters represents the parameters that are substituted in the templates of each cluster, a parameter is
+	// referenced by ${<name>} in the names and specs of the templates, the ${clusterName} is build-in.
+	// +optional
+	Parameters []TemplateParameter `json:"parameters,omitempty"`
+
+	// Drivers represents the templates of the drivers
+	// +optional
+	Drivers []DriverTemplate `json:"drivers,omitempty"`
+
+	// Devices represents the templates of the devices
+	// +optional
+	Devices []DeviceTemplate `json:"devices,omitempty"`
+}
+
+type PlacementRef struct {
+	// Name represents the name of the placement
+	// +kubebuilder:validation:Required
+	// +required
+	Name string `json:"name"`
+}
+
+type TemplateParameter struct {
+	// Name represents the parameter name
+	// +kubebuilder:validation:Required
+	// +required
+	Name string `json:"name"`
+
+	// ClusterClaim represents the name of a ClusterClaim of the managed cluster, its value is the parameter value
+	// +optional
+	ClusterClaim string `json:"clusterClaim,omitempty"`
+
+	// ClusterLabel represents a label of the managed cluster, its value is the parameter value if there is no
+	// cluster claim
+	// +optional
+	ClusterLabel string `json:"clusterLabel,omitempty"`
+
+	// Default represents the parameter value if the cluster has no the cluster claim and label, the cluster is
+	// not applied if there is no value of a parameter
+	// +optional
+	Default string `json:"default,omitempty"`
+}
+
+type DriverTemplate struct {
+	// Name represents the name of the driver
+	// +kubebuilder:validation:Required
+	// +required
+	Name string `json:"name"`
+
+	// Spec represents the spec of the driver
+	// +kubebuilder:validation:Required
+	// +required
+	Spec DriverSpec `json:"spec"`
+}
+
+type DeviceTemplate struct {
+	// Name represents the name of the device
+	// +kubebuilder:validation:Required
+	// +required
+	Name string `json:"name"`
+
+	// Spec represents the spec of the device
+	// +kubebuilder:validation:Required
+	// +required
+	Spec DeviceSpec `json:"spec"`
+}
+
+type DeviceSetStatus struct {
+	// conditions describe the state of the device set.
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// Clusters represents the number of the clusters that are selected by the placement
+	// +optional
+	Clusters int32 `json:"clusters"`
+
+	// AppliedClusters represents the number of the clusters that the drivers and devices are applied to
+	// +optional
+	AppliedClusters int32 `json:"appliedClusters"`
+}
@@ -4,6 +4,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// DriverFinalizer is added to the drivers by the agent, the drivers are stopped before they are deleted
+const DriverFinalizer = "edge.open-cluster-management.io/driver-cleanup"
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:subresource:status
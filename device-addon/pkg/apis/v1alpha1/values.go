@@ -26,7 +26,7 @@ func (in *Values) UnmarshalJSON(data []byte) error {
 
 // DeepCopyInto implements the DeepCopyInto interface.
 func (in *Values) DeepCopyInto(out *Values) {
-	bytes, err := json.Marshal(*in)
+	bytes, err := json.Marshal(in.Data)
 	if err != nil {
 		panic(err)
 	}
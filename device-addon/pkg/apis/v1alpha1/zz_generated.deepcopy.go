@@ -10,6 +10,38 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AggregateRule) DeepCopyInto(out *AggregateRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AggregateRule.
+func (in *AggregateRule) DeepCopy() *AggregateRule {
+	if in == nil {
+		return nil
+	}
+	out := new(AggregateRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeadbandRule) DeepCopyInto(out *DeadbandRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeadbandRule.
+func (in *DeadbandRule) DeepCopy() *DeadbandRule {
+	if in == nil {
+		return nil
+	}
+	out := new(DeadbandRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Device) DeepCopyInto(out *Device) {
 	*out = *in
@@ -109,6 +141,13 @@ func (in *DeviceAddOnConfigSpec) DeepCopyInto(out *DeviceAddOnConfigSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]ProcessingRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -145,6 +184,43 @@ func (in *DeviceAddOnConfigSpecStatus) DeepCopy() *DeviceAddOnConfigSpecStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceCommand) DeepCopyInto(out *DeviceCommand) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]DeviceCommandResource, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceCommand.
+func (in *DeviceCommand) DeepCopy() *DeviceCommand {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceCommand)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceCommandResource) DeepCopyInto(out *DeviceCommandResource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceCommandResource.
+func (in *DeviceCommandResource) DeepCopy() *DeviceCommandResource {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceCommandResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceConfig) DeepCopyInto(out *DeviceConfig) {
 	*out = *in
@@ -196,8 +272,88 @@ func (in *DeviceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceLiveState) DeepCopyInto(out *DeviceLiveState) {
+	*out = *in
+	if in.LastDataTime != nil {
+		in, out := &in.LastDataTime, &out.LastDataTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceLiveState.
+func (in *DeviceLiveState) DeepCopy() *DeviceLiveState {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceLiveState)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceProfile) DeepCopyInto(out *DeviceProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceProfile.
+func (in *DeviceProfile) DeepCopy() *DeviceProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceProfileList) DeepCopyInto(out *DeviceProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DeviceProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceProfileList.
+func (in *DeviceProfileList) DeepCopy() *DeviceProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceProfileSpec) DeepCopyInto(out *DeviceProfileSpec) {
 	*out = *in
 	if in.DeviceResources != nil {
 		in, out := &in.DeviceResources, &out.DeviceResources
@@ -206,15 +362,22 @@ func (in *DeviceProfile) DeepCopyInto(out *DeviceProfile) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DeviceCommands != nil {
+		in, out := &in.DeviceCommands, &out.DeviceCommands
+		*out = make([]DeviceCommand, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceProfile.
-func (in *DeviceProfile) DeepCopy() *DeviceProfile {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceProfileSpec.
+func (in *DeviceProfileSpec) DeepCopy() *DeviceProfileSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DeviceProfile)
+	out := new(DeviceProfileSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -237,10 +400,131 @@ func (in *DeviceResource) DeepCopy() *DeviceResource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceSet) DeepCopyInto(out *DeviceSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSet.
+func (in *DeviceSet) DeepCopy() *DeviceSet {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceSetList) DeepCopyInto(out *DeviceSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DeviceSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSetList.
+func (in *DeviceSetList) DeepCopy() *DeviceSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceSetSpec) DeepCopyInto(out *DeviceSetSpec) {
+	*out = *in
+	out.PlacementRef = in.PlacementRef
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]TemplateParameter, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drivers != nil {
+		in, out := &in.Drivers, &out.Drivers
+		*out = make([]DriverTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]DeviceTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSetSpec.
+func (in *DeviceSetSpec) DeepCopy() *DeviceSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceSetStatus) DeepCopyInto(out *DeviceSetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSetStatus.
+func (in *DeviceSetStatus) DeepCopy() *DeviceSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceSpec) DeepCopyInto(out *DeviceSpec) {
 	*out = *in
 	in.DeviceConfig.DeepCopyInto(&out.DeviceConfig)
+	in.Desired.DeepCopyInto(&out.Desired)
 	return
 }
 
@@ -264,6 +548,18 @@ func (in *DeviceStatus) DeepCopyInto(out *DeviceStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Reported != nil {
+		in, out := &in.Reported, &out.Reported
+		*out = make([]ReportedValue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.State != nil {
+		in, out := &in.State, &out.State
+		*out = new(DeviceLiveState)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -277,6 +573,39 @@ func (in *DeviceStatus) DeepCopy() *DeviceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceTemplate) DeepCopyInto(out *DeviceTemplate) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceTemplate.
+func (in *DeviceTemplate) DeepCopy() *DeviceTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DownsampleRule) DeepCopyInto(out *DownsampleRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DownsampleRule.
+func (in *DownsampleRule) DeepCopy() *DownsampleRule {
+	if in == nil {
+		return nil
+	}
+	out := new(DownsampleRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Driver) DeepCopyInto(out *Driver) {
 	*out = *in
@@ -395,6 +724,23 @@ func (in *DriverStatus) DeepCopy() *DriverStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverTemplate) DeepCopyInto(out *DriverTemplate) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverTemplate.
+func (in *DriverTemplate) DeepCopy() *DriverTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MessageBusConfig) DeepCopyInto(out *MessageBusConfig) {
 	*out = *in
@@ -412,6 +758,85 @@ func (in *MessageBusConfig) DeepCopy() *MessageBusConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementRef) DeepCopyInto(out *PlacementRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementRef.
+func (in *PlacementRef) DeepCopy() *PlacementRef {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessingRule) DeepCopyInto(out *ProcessingRule) {
+	*out = *in
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deadband != nil {
+		in, out := &in.Deadband, &out.Deadband
+		*out = new(DeadbandRule)
+		**out = **in
+	}
+	if in.Downsample != nil {
+		in, out := &in.Downsample, &out.Downsample
+		*out = new(DownsampleRule)
+		**out = **in
+	}
+	if in.Aggregate != nil {
+		in, out := &in.Aggregate, &out.Aggregate
+		*out = new(AggregateRule)
+		**out = **in
+	}
+	if in.Threshold != nil {
+		in, out := &in.Threshold, &out.Threshold
+		*out = new(ThresholdRule)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessingRule.
+func (in *ProcessingRule) DeepCopy() *ProcessingRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessingRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportedValue) DeepCopyInto(out *ReportedValue) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportedValue.
+func (in *ReportedValue) DeepCopy() *ReportedValue {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportedValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceProperties) DeepCopyInto(out *ResourceProperties) {
 	*out = *in
@@ -463,3 +888,45 @@ func (in *ResourceProperties) DeepCopy() *ResourceProperties {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateParameter) DeepCopyInto(out *TemplateParameter) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateParameter.
+func (in *TemplateParameter) DeepCopy() *TemplateParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThresholdRule) DeepCopyInto(out *ThresholdRule) {
+	*out = *in
+	if in.High != nil {
+		in, out := &in.High, &out.High
+		*out = new(float64)
+		**out = **in
+	}
+	if in.Low != nil {
+		in, out := &in.Low, &out.Low
+		*out = new(float64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThresholdRule.
+func (in *ThresholdRule) DeepCopy() *ThresholdRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ThresholdRule)
+	in.DeepCopyInto(out)
+	return out
+}
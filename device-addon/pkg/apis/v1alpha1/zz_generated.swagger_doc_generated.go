@@ -70,24 +70,43 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"k8s.io/apimachinery/pkg/runtime.TypeMeta":                                                            schema_k8sio_apimachinery_pkg_runtime_TypeMeta(ref),
 		"k8s.io/apimachinery/pkg/runtime.Unknown":                                                             schema_k8sio_apimachinery_pkg_runtime_Unknown(ref),
 		"k8s.io/apimachinery/pkg/version.Info":                                                                schema_k8sio_apimachinery_pkg_version_Info(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.AggregateRule":               schema_device_addon_pkg_apis_v1alpha1_AggregateRule(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeadbandRule":                schema_device_addon_pkg_apis_v1alpha1_DeadbandRule(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.Device":                      schema_device_addon_pkg_apis_v1alpha1_Device(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceAddOnConfig":           schema_device_addon_pkg_apis_v1alpha1_DeviceAddOnConfig(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceAddOnConfigList":       schema_device_addon_pkg_apis_v1alpha1_DeviceAddOnConfigList(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceAddOnConfigSpec":       schema_device_addon_pkg_apis_v1alpha1_DeviceAddOnConfigSpec(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceAddOnConfigSpecStatus": schema_device_addon_pkg_apis_v1alpha1_DeviceAddOnConfigSpecStatus(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceCommand":               schema_device_addon_pkg_apis_v1alpha1_DeviceCommand(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceCommandResource":       schema_device_addon_pkg_apis_v1alpha1_DeviceCommandResource(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceConfig":                schema_device_addon_pkg_apis_v1alpha1_DeviceConfig(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceList":                  schema_device_addon_pkg_apis_v1alpha1_DeviceList(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceLiveState":             schema_device_addon_pkg_apis_v1alpha1_DeviceLiveState(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceProfile":               schema_device_addon_pkg_apis_v1alpha1_DeviceProfile(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceProfileList":           schema_device_addon_pkg_apis_v1alpha1_DeviceProfileList(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceProfileSpec":           schema_device_addon_pkg_apis_v1alpha1_DeviceProfileSpec(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceResource":              schema_device_addon_pkg_apis_v1alpha1_DeviceResource(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceSet":                   schema_device_addon_pkg_apis_v1alpha1_DeviceSet(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceSetList":               schema_device_addon_pkg_apis_v1alpha1_DeviceSetList(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceSetSpec":               schema_device_addon_pkg_apis_v1alpha1_DeviceSetSpec(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceSetStatus":             schema_device_addon_pkg_apis_v1alpha1_DeviceSetStatus(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceSpec":                  schema_device_addon_pkg_apis_v1alpha1_DeviceSpec(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceStatus":                schema_device_addon_pkg_apis_v1alpha1_DeviceStatus(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceTemplate":              schema_device_addon_pkg_apis_v1alpha1_DeviceTemplate(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DownsampleRule":              schema_device_addon_pkg_apis_v1alpha1_DownsampleRule(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.Driver":                      schema_device_addon_pkg_apis_v1alpha1_Driver(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DriverConfig":                schema_device_addon_pkg_apis_v1alpha1_DriverConfig(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DriverList":                  schema_device_addon_pkg_apis_v1alpha1_DriverList(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DriverSpec":                  schema_device_addon_pkg_apis_v1alpha1_DriverSpec(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DriverStatus":                schema_device_addon_pkg_apis_v1alpha1_DriverStatus(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DriverTemplate":              schema_device_addon_pkg_apis_v1alpha1_DriverTemplate(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.MessageBusConfig":            schema_device_addon_pkg_apis_v1alpha1_MessageBusConfig(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.PlacementRef":                schema_device_addon_pkg_apis_v1alpha1_PlacementRef(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.ProcessingRule":              schema_device_addon_pkg_apis_v1alpha1_ProcessingRule(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.ReportedValue":               schema_device_addon_pkg_apis_v1alpha1_ReportedValue(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.ResourceProperties":          schema_device_addon_pkg_apis_v1alpha1_ResourceProperties(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.TemplateParameter":           schema_device_addon_pkg_apis_v1alpha1_TemplateParameter(ref),
+		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.ThresholdRule":               schema_device_addon_pkg_apis_v1alpha1_ThresholdRule(ref),
 		"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.Values":                      schema_device_addon_pkg_apis_v1alpha1_Values(ref),
 	}
 }
@@ -2570,6 +2589,67 @@ func schema_k8sio_apimachinery_pkg_version_Info(ref common.ReferenceCallback) co
 	}
 }
 
+func schema_device_addon_pkg_apis_v1alpha1_AggregateRule(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"window": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Window is the duration of the window to aggregate the readings, e.g. 60s",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"function": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Function is the aggregate function, it can be avg, min or max, default is avg",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"window"},
+			},
+		},
+	}
+}
+
+func schema_device_addon_pkg_apis_v1alpha1_DeadbandRule(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"absolute": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Absolute is the absolute change of a numeric reading that is published, a reading is published on any change if both of the absolute and percent are not set.",
+							Type:        []string{"number"},
+							Format:      "double",
+						},
+					},
+					"percent": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Percent is the change of a numeric reading that is published in percent of the last published reading",
+							Type:        []string{"number"},
+							Format:      "double",
+						},
+					},
+					"maxInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxInterval publishes the unchanged reading once the last reading is published for the interval, e.g. 5m",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_device_addon_pkg_apis_v1alpha1_Device(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2738,12 +2818,26 @@ func schema_device_addon_pkg_apis_v1alpha1_DeviceAddOnConfigSpec(ref common.Refe
 							},
 						},
 					},
+					"rules": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Rules process the device readings on the edge before they are published to the message buses, the rules are applied in order, a reading is processed by the rules that match its device and resource.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.ProcessingRule"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"messageBuses"},
 			},
 		},
 		Dependencies: []string{
-			"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.MessageBusConfig"},
+			"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.MessageBusConfig", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.ProcessingRule"},
 	}
 }
 
@@ -2781,6 +2875,78 @@ func schema_device_addon_pkg_apis_v1alpha1_DeviceAddOnConfigSpecStatus(ref commo
 	}
 }
 
+func schema_device_addon_pkg_apis_v1alpha1_DeviceCommand(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name represents the device command name",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"readWrite": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadWrite represents the device command permission, default is RW",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resources represents the device resources that are operated by the command",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceCommandResource"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"name", "resources"},
+			},
+		},
+		Dependencies: []string{
+			"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceCommandResource"},
+	}
+}
+
+func schema_device_addon_pkg_apis_v1alpha1_DeviceCommandResource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"deviceResource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeviceResource represents the name of the operated device resource",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"defaultValue": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DefaultValue represents the value that is used when the command does not give a value",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"deviceResource"},
+			},
+		},
+	}
+}
+
 func schema_device_addon_pkg_apis_v1alpha1_DeviceConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2835,7 +3001,7 @@ func schema_device_addon_pkg_apis_v1alpha1_DeviceConfig(ref common.ReferenceCall
 						SchemaProps: spec.SchemaProps{
 							Description: "Profile represents the device data profile",
 							Default:     map[string]interface{}{},
-							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceProfile"),
+							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceProfileSpec"),
 						},
 					},
 				},
@@ -2843,7 +3009,7 @@ func schema_device_addon_pkg_apis_v1alpha1_DeviceConfig(ref common.ReferenceCall
 			},
 		},
 		Dependencies: []string{
-			"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceProfile", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.Values"},
+			"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceProfileSpec", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.Values"},
 	}
 }
 
@@ -2896,165 +3062,169 @@ func schema_device_addon_pkg_apis_v1alpha1_DeviceList(ref common.ReferenceCallba
 	}
 }
 
-func schema_device_addon_pkg_apis_v1alpha1_DeviceProfile(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_device_addon_pkg_apis_v1alpha1_DeviceLiveState(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"deviceResources": {
+					"lastDataTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "DeviceResources represents device supporting resources",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceResource"),
-									},
-								},
-							},
+							Description: "LastDataTime represents the time that the last data is received from the device",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"errorCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ErrorCount represents the number of the errors that occurred when the driver handles the device",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"rejectedCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RejectedCount represents the number of the readings that are rejected by the validation of their device resources",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"lastError": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastError represents the last error that occurred when the driver handles the device",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceResource"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
-func schema_device_addon_pkg_apis_v1alpha1_DeviceResource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_device_addon_pkg_apis_v1alpha1_DeviceProfile(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "DeviceProfile is the schema for the device profile API, it is referenced by the devices that have the same resources and commands",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name represents the device resource name",
-							Default:     "",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"description": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Description represents the device resource description",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"properties": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name represents the device resource properties",
-							Default:     map[string]interface{}{},
-							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.ResourceProperties"),
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"attributes": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Attributes represents the device resource attributes",
+							Description: "spec holds the device resources and commands of the profile.",
 							Default:     map[string]interface{}{},
-							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.Values"),
+							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceProfileSpec"),
 						},
 					},
 				},
-				Required: []string{"name", "properties"},
+				Required: []string{"metadata", "spec"},
 			},
 		},
 		Dependencies: []string{
-			"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.ResourceProperties", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.Values"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceProfileSpec"},
 	}
 }
 
-func schema_device_addon_pkg_apis_v1alpha1_DeviceSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_device_addon_pkg_apis_v1alpha1_DeviceProfileList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "DeviceProfileList is a list of DeviceProfile",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Name represents the device name",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"driverType": {
-						SchemaProps: spec.SchemaProps{
-							Description: "DriverType represents the device driver type",
-							Default:     "",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"manufacturer": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Manufacturer represents the device manufacturer",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"model": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Model represents the device model",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"description": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Description describe the device information",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"protocolProperties": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ProtocolProperties represents device protocol properties",
-							Default:     map[string]interface{}{},
-							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.Values"),
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
 						},
 					},
-					"profile": {
+					"items": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Profile represents the device data profile",
-							Default:     map[string]interface{}{},
-							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceProfile"),
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceProfile"),
+									},
+								},
+							},
 						},
 					},
 				},
-				Required: []string{"name", "driverType", "profile"},
+				Required: []string{"metadata", "items"},
 			},
 		},
 		Dependencies: []string{
-			"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceProfile", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.Values"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceProfile"},
 	}
 }
 
-func schema_device_addon_pkg_apis_v1alpha1_DeviceStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_device_addon_pkg_apis_v1alpha1_DeviceProfileSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "DeviceProfileSpec represents the device resources and commands of a device, it is set in the device spec or shared by the devices that reference the same DeviceProfile",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"conditions": {
-						VendorExtensible: spec.VendorExtensible{
-							Extensions: spec.Extensions{
-								"x-kubernetes-patch-merge-key": "type",
-								"x-kubernetes-patch-strategy":  "merge",
+					"deviceResources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeviceResources represents device supporting resources",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceResource"),
+									},
+								},
 							},
 						},
+					},
+					"deviceCommands": {
 						SchemaProps: spec.SchemaProps{
-							Description: "conditions describe the state of the current device.",
+							Description: "DeviceCommands represents device supporting commands",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Condition"),
+										Ref:     ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceCommand"),
 									},
 								},
 							},
@@ -3064,15 +3234,457 @@ func schema_device_addon_pkg_apis_v1alpha1_DeviceStatus(ref common.ReferenceCall
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Condition"},
+			"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceCommand", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceResource"},
 	}
 }
 
-func schema_device_addon_pkg_apis_v1alpha1_Driver(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_device_addon_pkg_apis_v1alpha1_DeviceResource(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Driver is the schema for the device driver configuration API",
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name represents the device resource name",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"description": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Description represents the device resource description",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"properties": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name represents the device resource properties",
+							Default:     map[string]interface{}{},
+							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.ResourceProperties"),
+						},
+					},
+					"attributes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Attributes represents the device resource attributes",
+							Default:     map[string]interface{}{},
+							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.Values"),
+						},
+					},
+				},
+				Required: []string{"name", "properties"},
+			},
+		},
+		Dependencies: []string{
+			"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.ResourceProperties", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.Values"},
+	}
+}
+
+func schema_device_addon_pkg_apis_v1alpha1_DeviceSet(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DeviceSet is the schema for the device set API, it creates the drivers and devices from its templates in the namespaces of the managed clusters that are selected by a placement",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "spec holds the templates of the drivers and devices and the placement.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceSetSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "status holds the state of the device set.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceSetStatus"),
+						},
+					},
+				},
+				Required: []string{"metadata", "spec"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceSetSpec", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceSetStatus"},
+	}
+}
+
+func schema_device_addon_pkg_apis_v1alpha1_DeviceSetList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DeviceSetList is a list of DeviceSet",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceSet"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceSet"},
+	}
+}
+
+func schema_device_addon_pkg_apis_v1alpha1_DeviceSetSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"placementRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PlacementRef represents the Placement in the same namespace, the drivers and devices are created in the namespaces of the managed clusters that are selected by the placement, and they are deleted once the clusters are not selected.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.PlacementRef"),
+						},
+					},
+					"parameters": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Parameters represents the parameters that are substituted in the templates of each cluster, a parameter is referenced by ${<name>} in the names and specs of the templates, the ${clusterName} is build-in.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.TemplateParameter"),
+									},
+								},
+							},
+						},
+					},
+					"drivers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Drivers represents the templates of the drivers",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DriverTemplate"),
+									},
+								},
+							},
+						},
+					},
+					"devices": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Devices represents the templates of the devices",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceTemplate"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"placementRef"},
+			},
+		},
+		Dependencies: []string{
+			"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceTemplate", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DriverTemplate", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.PlacementRef", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.TemplateParameter"},
+	}
+}
+
+func schema_device_addon_pkg_apis_v1alpha1_DeviceSetStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"conditions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-patch-merge-key": "type",
+								"x-kubernetes-patch-strategy":  "merge",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "conditions describe the state of the device set.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Condition"),
+									},
+								},
+							},
+						},
+					},
+					"clusters": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Clusters represents the number of the clusters that are selected by the placement",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"appliedClusters": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AppliedClusters represents the number of the clusters that the drivers and devices are applied to",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Condition"},
+	}
+}
+
+func schema_device_addon_pkg_apis_v1alpha1_DeviceSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name represents the device name",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"driverType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DriverType represents the device driver type",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"manufacturer": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Manufacturer represents the device manufacturer",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"model": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Model represents the device model",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"description": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Description describe the device information",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"protocolProperties": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ProtocolProperties represents device protocol properties",
+							Default:     map[string]interface{}{},
+							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.Values"),
+						},
+					},
+					"profile": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Profile represents the device data profile",
+							Default:     map[string]interface{}{},
+							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceProfileSpec"),
+						},
+					},
+					"profileRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ProfileRef represents the name of a DeviceProfile in the same namespace, the device resources and commands of the referenced profile are merged into the profile of the device, the ones in the profile of the device override the ones of the referenced profile with the same names.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"desired": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Desired represents the desired values of the writable device resources, the key is the device resource name, the values are written to the device until the reported values converge to them.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.Values"),
+						},
+					},
+				},
+				Required: []string{"name", "driverType", "profile"},
+			},
+		},
+		Dependencies: []string{
+			"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceProfileSpec", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.Values"},
+	}
+}
+
+func schema_device_addon_pkg_apis_v1alpha1_DeviceStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"conditions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-patch-merge-key": "type",
+								"x-kubernetes-patch-strategy":  "merge",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "conditions describe the state of the current device.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Condition"),
+									},
+								},
+							},
+						},
+					},
+					"reported": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reported represents the last values that are reported by the device resources",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.ReportedValue"),
+									},
+								},
+							},
+						},
+					},
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Description: "State represents the live state of the device that is reported by its driver",
+							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceLiveState"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Condition", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceLiveState", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.ReportedValue"},
+	}
+}
+
+func schema_device_addon_pkg_apis_v1alpha1_DeviceTemplate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name represents the name of the device",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Spec represents the spec of the device",
+							Default:     map[string]interface{}{},
+							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceSpec"),
+						},
+					},
+				},
+				Required: []string{"name", "spec"},
+			},
+		},
+		Dependencies: []string{
+			"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeviceSpec"},
+	}
+}
+
+func schema_device_addon_pkg_apis_v1alpha1_DownsampleRule(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"interval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Interval is the minimum interval between two published readings, e.g. 10s",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"interval"},
+			},
+		},
+	}
+}
+
+func schema_device_addon_pkg_apis_v1alpha1_Driver(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Driver is the schema for the device driver configuration API",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -3261,6 +3873,36 @@ func schema_device_addon_pkg_apis_v1alpha1_DriverStatus(ref common.ReferenceCall
 	}
 }
 
+func schema_device_addon_pkg_apis_v1alpha1_DriverTemplate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name represents the name of the driver",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Spec represents the spec of the driver",
+							Default:     map[string]interface{}{},
+							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DriverSpec"),
+						},
+					},
+				},
+				Required: []string{"name", "spec"},
+			},
+		},
+		Dependencies: []string{
+			"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DriverSpec"},
+	}
+}
+
 func schema_device_addon_pkg_apis_v1alpha1_MessageBusConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -3296,6 +3938,143 @@ func schema_device_addon_pkg_apis_v1alpha1_MessageBusConfig(ref common.Reference
 	}
 }
 
+func schema_device_addon_pkg_apis_v1alpha1_PlacementRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name represents the name of the placement",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func schema_device_addon_pkg_apis_v1alpha1_ProcessingRule(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ProcessingRule transforms or suppresses the readings of the matched device resources, one and only one of deadband, downsample, aggregate and threshold should be set.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the rule",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"devices": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Devices are the names of the devices that the rule applies to, the shell patterns are supported, e.g. sensor-*, the rule applies to all of the devices if it is not set.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"resources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resources are the names of the device resources that the rule applies to, the shell patterns are supported, the rule applies to all of the resources if it is not set.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"deadband": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Deadband publishes a reading only if it changes beyond the deadband from the last published reading",
+							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeadbandRule"),
+						},
+					},
+					"downsample": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Downsample publishes one reading at most in an interval",
+							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DownsampleRule"),
+						},
+					},
+					"aggregate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Aggregate publishes the aggregate of the readings in a window instead of the readings",
+							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.AggregateRule"),
+						},
+					},
+					"threshold": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Threshold raises an alarm once a reading crosses the thresholds",
+							Ref:         ref("open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.ThresholdRule"),
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.AggregateRule", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DeadbandRule", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.DownsampleRule", "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1.ThresholdRule"},
+	}
+}
+
+func schema_device_addon_pkg_apis_v1alpha1_ReportedValue(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name represents the device resource name",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Value represents the last reported value of the device resource",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"timestamp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timestamp represents the time that the value is reported, it is not updated until the value is changed",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"name", "value", "timestamp"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_device_addon_pkg_apis_v1alpha1_ResourceProperties(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -3304,8 +4083,7 @@ func schema_device_addon_pkg_apis_v1alpha1_ResourceProperties(ref common.Referen
 				Properties: map[string]spec.Schema{
 					"readWrite": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ReadWrite",
-							Default:     "",
+							Description: "ReadWrite represents the device resource permission, default is RW",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -3334,14 +4112,14 @@ func schema_device_addon_pkg_apis_v1alpha1_ResourceProperties(ref common.Referen
 					},
 					"assertion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Minimum",
+							Description: "Assertion represents the expected value of a scalar resource, an alarm is raised once the reading does not equal it",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 					"mediaType": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Minimum",
+							Description: "MediaType represents the media type of a Binary resource, default is application/octet-stream",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -3403,7 +4181,7 @@ func schema_device_addon_pkg_apis_v1alpha1_ResourceProperties(ref common.Referen
 						},
 					},
 				},
-				Required: []string{"readWrite", "valueType"},
+				Required: []string{"valueType"},
 			},
 		},
 		Dependencies: []string{
@@ -3411,6 +4189,74 @@ func schema_device_addon_pkg_apis_v1alpha1_ResourceProperties(ref common.Referen
 	}
 }
 
+func schema_device_addon_pkg_apis_v1alpha1_TemplateParameter(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name represents the parameter name",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clusterClaim": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClusterClaim represents the name of a ClusterClaim of the managed cluster, its value is the parameter value",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clusterLabel": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClusterLabel represents a label of the managed cluster, its value is the parameter value if there is no cluster claim",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"default": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Default represents the parameter value if the cluster has no the cluster claim and label, the cluster is not applied if there is no value of a parameter",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func schema_device_addon_pkg_apis_v1alpha1_ThresholdRule(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"high": {
+						SchemaProps: spec.SchemaProps{
+							Description: "High raises an alarm once a reading is greater than it",
+							Type:        []string{"number"},
+							Format:      "double",
+						},
+					},
+					"low": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Low raises an alarm once a reading is less than it",
+							Type:        []string{"number"},
+							Format:      "double",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_device_addon_pkg_apis_v1alpha1_Values(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
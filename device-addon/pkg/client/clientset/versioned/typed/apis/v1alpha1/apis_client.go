@@ -14,6 +14,8 @@ type EdgeV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	DevicesGetter
 	DeviceAddOnConfigsGetter
+	DeviceProfilesGetter
+	DeviceSetsGetter
 	DriversGetter
 }
 
@@ -30,6 +32,14 @@ func (c *EdgeV1alpha1Client) DeviceAddOnConfigs(namespace string) DeviceAddOnCon
 	return newDeviceAddOnConfigs(c, namespace)
 }
 
+func (c *EdgeV1alpha1Client) DeviceProfiles(namespace string) DeviceProfileInterface {
+	return newDeviceProfiles(c, namespace)
+}
+
+func (c *EdgeV1alpha1Client) DeviceSets(namespace string) DeviceSetInterface {
+	return newDeviceSets(c, namespace)
+}
+
 func (c *EdgeV1alpha1Client) Drivers(namespace string) DriverInterface {
 	return newDrivers(c, namespace)
 }
@@ -0,0 +1,162 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	v1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	scheme "open-cluster-management-io/addon-contrib/device-addon/pkg/client/clientset/versioned/scheme"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// DeviceProfilesGetter has a method to return a DeviceProfileInterface.
+// A group's client should implement this interface.
+type DeviceProfilesGetter interface {
+	DeviceProfiles(namespace string) DeviceProfileInterface
+}
+
+// DeviceProfileInterface has methods to work with DeviceProfile resources.
+type DeviceProfileInterface interface {
+	Create(ctx context.Context, deviceProfile *v1alpha1.DeviceProfile, opts v1.CreateOptions) (*v1alpha1.DeviceProfile, error)
+	Update(ctx context.Context, deviceProfile *v1alpha1.DeviceProfile, opts v1.UpdateOptions) (*v1alpha1.DeviceProfile, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.DeviceProfile, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.DeviceProfileList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DeviceProfile, err error)
+	DeviceProfileExpansion
+}
+
+// deviceProfiles implements DeviceProfileInterface
+type deviceProfiles struct {
+	client rest.Interface
+	ns     string
+}
+
+// newDeviceProfiles returns a DeviceProfiles
+func newDeviceProfiles(c *EdgeV1alpha1Client, namespace string) *deviceProfiles {
+	return &deviceProfiles{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the deviceProfile, and returns the corresponding deviceProfile object, and an error if there is any.
+func (c *deviceProfiles) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.DeviceProfile, err error) {
+	result = &v1alpha1.DeviceProfile{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("deviceprofiles").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of DeviceProfiles that match those selectors.
+func (c *deviceProfiles) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.DeviceProfileList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.DeviceProfileList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("deviceprofiles").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested deviceProfiles.
+func (c *deviceProfiles) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("deviceprofiles").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a deviceProfile and creates it.  Returns the server's representation of the deviceProfile, and an error, if there is any.
+func (c *deviceProfiles) Create(ctx context.Context, deviceProfile *v1alpha1.DeviceProfile, opts v1.CreateOptions) (result *v1alpha1.DeviceProfile, err error) {
+	result = &v1alpha1.DeviceProfile{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("deviceprofiles").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(deviceProfile).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a deviceProfile and updates it. Returns the server's representation of the deviceProfile, and an error, if there is any.
+func (c *deviceProfiles) Update(ctx context.Context, deviceProfile *v1alpha1.DeviceProfile, opts v1.UpdateOptions) (result *v1alpha1.DeviceProfile, err error) {
+	result = &v1alpha1.DeviceProfile{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("deviceprofiles").
+		Name(deviceProfile.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(deviceProfile).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the deviceProfile and deletes it. Returns an error if one occurs.
+func (c *deviceProfiles) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("deviceprofiles").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *deviceProfiles) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("deviceprofiles").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched deviceProfile.
+func (c *deviceProfiles) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DeviceProfile, err error) {
+	result = &v1alpha1.DeviceProfile{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("deviceprofiles").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
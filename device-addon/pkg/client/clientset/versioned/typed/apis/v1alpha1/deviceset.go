@@ -0,0 +1,179 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	v1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	scheme "open-cluster-management-io/addon-contrib/device-addon/pkg/client/clientset/versioned/scheme"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// DeviceSetsGetter has a method to return a DeviceSetInterface.
+// A group's client should implement this interface.
+type DeviceSetsGetter interface {
+	DeviceSets(namespace string) DeviceSetInterface
+}
+
+// DeviceSetInterface has methods to work with DeviceSet resources.
+type DeviceSetInterface interface {
+	Create(ctx context.Context, deviceSet *v1alpha1.DeviceSet, opts v1.CreateOptions) (*v1alpha1.DeviceSet, error)
+	Update(ctx context.Context, deviceSet *v1alpha1.DeviceSet, opts v1.UpdateOptions) (*v1alpha1.DeviceSet, error)
+	UpdateStatus(ctx context.Context, deviceSet *v1alpha1.DeviceSet, opts v1.UpdateOptions) (*v1alpha1.DeviceSet, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.DeviceSet, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.DeviceSetList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DeviceSet, err error)
+	DeviceSetExpansion
+}
+
+// deviceSets implements DeviceSetInterface
+type deviceSets struct {
+	client rest.Interface
+	ns     string
+}
+
+// newDeviceSets returns a DeviceSets
+func newDeviceSets(c *EdgeV1alpha1Client, namespace string) *deviceSets {
+	return &deviceSets{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the deviceSet, and returns the corresponding deviceSet object, and an error if there is any.
+func (c *deviceSets) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.DeviceSet, err error) {
+	result = &v1alpha1.DeviceSet{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("devicesets").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of DeviceSets that match those selectors.
+func (c *deviceSets) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.DeviceSetList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.DeviceSetList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("devicesets").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested deviceSets.
+func (c *deviceSets) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("devicesets").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a deviceSet and creates it.  Returns the server's representation of the deviceSet, and an error, if there is any.
+func (c *deviceSets) Create(ctx context.Context, deviceSet *v1alpha1.DeviceSet, opts v1.CreateOptions) (result *v1alpha1.DeviceSet, err error) {
+	result = &v1alpha1.DeviceSet{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("devicesets").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(deviceSet).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a deviceSet and updates it. Returns the server's representation of the deviceSet, and an error, if there is any.
+func (c *deviceSets) Update(ctx context.Context, deviceSet *v1alpha1.DeviceSet, opts v1.UpdateOptions) (result *v1alpha1.DeviceSet, err error) {
+	result = &v1alpha1.DeviceSet{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("devicesets").
+		Name(deviceSet.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(deviceSet).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *deviceSets) UpdateStatus(ctx context.Context, deviceSet *v1alpha1.DeviceSet, opts v1.UpdateOptions) (result *v1alpha1.DeviceSet, err error) {
+	result = &v1alpha1.DeviceSet{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("devicesets").
+		Name(deviceSet.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(deviceSet).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the deviceSet and deletes it. Returns an error if one occurs.
+func (c *deviceSets) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("devicesets").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *deviceSets) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("devicesets").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched deviceSet.
+func (c *deviceSets) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DeviceSet, err error) {
+	result = &v1alpha1.DeviceSet{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("devicesets").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -21,6 +21,14 @@ func (c *FakeEdgeV1alpha1) DeviceAddOnConfigs(namespace string) v1alpha1.DeviceA
 	return &FakeDeviceAddOnConfigs{c, namespace}
 }
 
+func (c *FakeEdgeV1alpha1) DeviceProfiles(namespace string) v1alpha1.DeviceProfileInterface {
+	return &FakeDeviceProfiles{c, namespace}
+}
+
+func (c *FakeEdgeV1alpha1) DeviceSets(namespace string) v1alpha1.DeviceSetInterface {
+	return &FakeDeviceSets{c, namespace}
+}
+
 func (c *FakeEdgeV1alpha1) Drivers(namespace string) v1alpha1.DriverInterface {
 	return &FakeDrivers{c, namespace}
 }
@@ -0,0 +1,113 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+	v1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeDeviceProfiles implements DeviceProfileInterface
+type FakeDeviceProfiles struct {
+	Fake *FakeEdgeV1alpha1
+	ns   string
+}
+
+var deviceprofilesResource = v1alpha1.SchemeGroupVersion.WithResource("deviceprofiles")
+
+var deviceprofilesKind = v1alpha1.SchemeGroupVersion.WithKind("DeviceProfile")
+
+// Get takes name of the deviceProfile, and returns the corresponding deviceProfile object, and an error if there is any.
+func (c *FakeDeviceProfiles) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.DeviceProfile, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(deviceprofilesResource, c.ns, name), &v1alpha1.DeviceProfile{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceProfile), err
+}
+
+// List takes label and field selectors, and returns the list of DeviceProfiles that match those selectors.
+func (c *FakeDeviceProfiles) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.DeviceProfileList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(deviceprofilesResource, deviceprofilesKind, c.ns, opts), &v1alpha1.DeviceProfileList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.DeviceProfileList{ListMeta: obj.(*v1alpha1.DeviceProfileList).ListMeta}
+	for _, item := range obj.(*v1alpha1.DeviceProfileList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested deviceProfiles.
+func (c *FakeDeviceProfiles) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(deviceprofilesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a deviceProfile and creates it.  Returns the server's representation of the deviceProfile, and an error, if there is any.
+func (c *FakeDeviceProfiles) Create(ctx context.Context, deviceProfile *v1alpha1.DeviceProfile, opts v1.CreateOptions) (result *v1alpha1.DeviceProfile, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(deviceprofilesResource, c.ns, deviceProfile), &v1alpha1.DeviceProfile{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceProfile), err
+}
+
+// Update takes the representation of a deviceProfile and updates it. Returns the server's representation of the deviceProfile, and an error, if there is any.
+func (c *FakeDeviceProfiles) Update(ctx context.Context, deviceProfile *v1alpha1.DeviceProfile, opts v1.UpdateOptions) (result *v1alpha1.DeviceProfile, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(deviceprofilesResource, c.ns, deviceProfile), &v1alpha1.DeviceProfile{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceProfile), err
+}
+
+// Delete takes name of the deviceProfile and deletes it. Returns an error if one occurs.
+func (c *FakeDeviceProfiles) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(deviceprofilesResource, c.ns, name, opts), &v1alpha1.DeviceProfile{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeDeviceProfiles) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(deviceprofilesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.DeviceProfileList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched deviceProfile.
+func (c *FakeDeviceProfiles) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DeviceProfile, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(deviceprofilesResource, c.ns, name, pt, data, subresources...), &v1alpha1.DeviceProfile{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceProfile), err
+}
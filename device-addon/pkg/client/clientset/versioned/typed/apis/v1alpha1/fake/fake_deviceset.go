@@ -0,0 +1,125 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+	v1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeDeviceSets implements DeviceSetInterface
+type FakeDeviceSets struct {
+	Fake *FakeEdgeV1alpha1
+	ns   string
+}
+
+var devicesetsResource = v1alpha1.SchemeGroupVersion.WithResource("devicesets")
+
+var devicesetsKind = v1alpha1.SchemeGroupVersion.WithKind("DeviceSet")
+
+// Get takes name of the deviceSet, and returns the corresponding deviceSet object, and an error if there is any.
+func (c *FakeDeviceSets) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.DeviceSet, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(devicesetsResource, c.ns, name), &v1alpha1.DeviceSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceSet), err
+}
+
+// List takes label and field selectors, and returns the list of DeviceSets that match those selectors.
+func (c *FakeDeviceSets) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.DeviceSetList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(devicesetsResource, devicesetsKind, c.ns, opts), &v1alpha1.DeviceSetList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.DeviceSetList{ListMeta: obj.(*v1alpha1.DeviceSetList).ListMeta}
+	for _, item := range obj.(*v1alpha1.DeviceSetList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested deviceSets.
+func (c *FakeDeviceSets) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(devicesetsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a deviceSet and creates it.  Returns the server's representation of the deviceSet, and an error, if there is any.
+func (c *FakeDeviceSets) Create(ctx context.Context, deviceSet *v1alpha1.DeviceSet, opts v1.CreateOptions) (result *v1alpha1.DeviceSet, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(devicesetsResource, c.ns, deviceSet), &v1alpha1.DeviceSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceSet), err
+}
+
+// Update takes the representation of a deviceSet and updates it. Returns the server's representation of the deviceSet, and an error, if there is any.
+func (c *FakeDeviceSets) Update(ctx context.Context, deviceSet *v1alpha1.DeviceSet, opts v1.UpdateOptions) (result *v1alpha1.DeviceSet, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(devicesetsResource, c.ns, deviceSet), &v1alpha1.DeviceSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceSet), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeDeviceSets) UpdateStatus(ctx context.Context, deviceSet *v1alpha1.DeviceSet, opts v1.UpdateOptions) (*v1alpha1.DeviceSet, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(devicesetsResource, "status", c.ns, deviceSet), &v1alpha1.DeviceSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceSet), err
+}
+
+// Delete takes name of the deviceSet and deletes it. Returns an error if one occurs.
+func (c *FakeDeviceSets) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(devicesetsResource, c.ns, name, opts), &v1alpha1.DeviceSet{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeDeviceSets) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(devicesetsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.DeviceSetList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched deviceSet.
+func (c *FakeDeviceSets) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DeviceSet, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(devicesetsResource, c.ns, name, pt, data, subresources...), &v1alpha1.DeviceSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceSet), err
+}
@@ -6,4 +6,8 @@ type DeviceExpansion interface{}
 
 type DeviceAddOnConfigExpansion interface{}
 
+type DeviceProfileExpansion interface{}
+
+type DeviceSetExpansion interface{}
+
 type DriverExpansion interface{}
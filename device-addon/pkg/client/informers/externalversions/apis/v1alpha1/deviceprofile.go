@@ -0,0 +1,74 @@
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	apisv1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	versioned "open-cluster-management-io/addon-contrib/device-addon/pkg/client/clientset/versioned"
+	internalinterfaces "open-cluster-management-io/addon-contrib/device-addon/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/client/listers/apis/v1alpha1"
+	time "time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// DeviceProfileInformer provides access to a shared informer and lister for
+// DeviceProfiles.
+type DeviceProfileInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha1.DeviceProfileLister
+}
+
+type deviceProfileInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewDeviceProfileInformer constructs a new informer for DeviceProfile type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewDeviceProfileInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredDeviceProfileInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredDeviceProfileInformer constructs a new informer for DeviceProfile type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredDeviceProfileInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.EdgeV1alpha1().DeviceProfiles(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.EdgeV1alpha1().DeviceProfiles(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&apisv1alpha1.DeviceProfile{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *deviceProfileInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredDeviceProfileInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *deviceProfileInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&apisv1alpha1.DeviceProfile{}, f.defaultInformer)
+}
+
+func (f *deviceProfileInformer) Lister() v1alpha1.DeviceProfileLister {
+	return v1alpha1.NewDeviceProfileLister(f.Informer().GetIndexer())
+}
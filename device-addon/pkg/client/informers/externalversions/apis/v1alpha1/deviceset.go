@@ -0,0 +1,74 @@
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	apisv1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	versioned "open-cluster-management-io/addon-contrib/device-addon/pkg/client/clientset/versioned"
+	internalinterfaces "open-cluster-management-io/addon-contrib/device-addon/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/client/listers/apis/v1alpha1"
+	time "time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// DeviceSetInformer provides access to a shared informer and lister for
+// DeviceSets.
+type DeviceSetInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha1.DeviceSetLister
+}
+
+type deviceSetInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewDeviceSetInformer constructs a new informer for DeviceSet type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewDeviceSetInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredDeviceSetInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredDeviceSetInformer constructs a new informer for DeviceSet type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredDeviceSetInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.EdgeV1alpha1().DeviceSets(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.EdgeV1alpha1().DeviceSets(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&apisv1alpha1.DeviceSet{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *deviceSetInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredDeviceSetInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *deviceSetInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&apisv1alpha1.DeviceSet{}, f.defaultInformer)
+}
+
+func (f *deviceSetInformer) Lister() v1alpha1.DeviceSetLister {
+	return v1alpha1.NewDeviceSetLister(f.Informer().GetIndexer())
+}
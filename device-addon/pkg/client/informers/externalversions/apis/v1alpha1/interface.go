@@ -12,6 +12,10 @@ type Interface interface {
 	Devices() DeviceInformer
 	// DeviceAddOnConfigs returns a DeviceAddOnConfigInformer.
 	DeviceAddOnConfigs() DeviceAddOnConfigInformer
+	// DeviceProfiles returns a DeviceProfileInformer.
+	DeviceProfiles() DeviceProfileInformer
+	// DeviceSets returns a DeviceSetInformer.
+	DeviceSets() DeviceSetInformer
 	// Drivers returns a DriverInformer.
 	Drivers() DriverInformer
 }
@@ -37,6 +41,16 @@ func (v *version) DeviceAddOnConfigs() DeviceAddOnConfigInformer {
 	return &deviceAddOnConfigInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// DeviceProfiles returns a DeviceProfileInformer.
+func (v *version) DeviceProfiles() DeviceProfileInformer {
+	return &deviceProfileInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// DeviceSets returns a DeviceSetInformer.
+func (v *version) DeviceSets() DeviceSetInformer {
+	return &deviceSetInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Drivers returns a DriverInformer.
 func (v *version) Drivers() DriverInformer {
 	return &driverInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -41,6 +41,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Edge().V1alpha1().Devices().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("deviceaddonconfigs"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Edge().V1alpha1().DeviceAddOnConfigs().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("deviceprofiles"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Edge().V1alpha1().DeviceProfiles().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("devicesets"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Edge().V1alpha1().DeviceSets().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("drivers"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Edge().V1alpha1().Drivers().Informer()}, nil
 
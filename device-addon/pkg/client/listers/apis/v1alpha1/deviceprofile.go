@@ -0,0 +1,84 @@
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DeviceProfileLister helps list DeviceProfiles.
+// All objects returned here must be treated as read-only.
+type DeviceProfileLister interface {
+	// List lists all DeviceProfiles in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.DeviceProfile, err error)
+	// DeviceProfiles returns an object that can list and get DeviceProfiles.
+	DeviceProfiles(namespace string) DeviceProfileNamespaceLister
+	DeviceProfileListerExpansion
+}
+
+// deviceProfileLister implements the DeviceProfileLister interface.
+type deviceProfileLister struct {
+	indexer cache.Indexer
+}
+
+// NewDeviceProfileLister returns a new DeviceProfileLister.
+func NewDeviceProfileLister(indexer cache.Indexer) DeviceProfileLister {
+	return &deviceProfileLister{indexer: indexer}
+}
+
+// List lists all DeviceProfiles in the indexer.
+func (s *deviceProfileLister) List(selector labels.Selector) (ret []*v1alpha1.DeviceProfile, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.DeviceProfile))
+	})
+	return ret, err
+}
+
+// DeviceProfiles returns an object that can list and get DeviceProfiles.
+func (s *deviceProfileLister) DeviceProfiles(namespace string) DeviceProfileNamespaceLister {
+	return deviceProfileNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// DeviceProfileNamespaceLister helps list and get DeviceProfiles.
+// All objects returned here must be treated as read-only.
+type DeviceProfileNamespaceLister interface {
+	// List lists all DeviceProfiles in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.DeviceProfile, err error)
+	// Get retrieves the DeviceProfile from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.DeviceProfile, error)
+	DeviceProfileNamespaceListerExpansion
+}
+
+// deviceProfileNamespaceLister implements the DeviceProfileNamespaceLister
+// interface.
+type deviceProfileNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all DeviceProfiles in the indexer for a given namespace.
+func (s deviceProfileNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.DeviceProfile, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.DeviceProfile))
+	})
+	return ret, err
+}
+
+// Get retrieves the DeviceProfile from the indexer for a given namespace and name.
+func (s deviceProfileNamespaceLister) Get(name string) (*v1alpha1.DeviceProfile, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("deviceprofile"), name)
+	}
+	return obj.(*v1alpha1.DeviceProfile), nil
+}
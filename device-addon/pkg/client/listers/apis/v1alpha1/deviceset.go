@@ -0,0 +1,84 @@
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DeviceSetLister helps list DeviceSets.
+// All objects returned here must be treated as read-only.
+type DeviceSetLister interface {
+	// List lists all DeviceSets in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.DeviceSet, err error)
+	// DeviceSets returns an object that can list and get DeviceSets.
+	DeviceSets(namespace string) DeviceSetNamespaceLister
+	DeviceSetListerExpansion
+}
+
+// deviceSetLister implements the DeviceSetLister interface.
+type deviceSetLister struct {
+	indexer cache.Indexer
+}
+
+// NewDeviceSetLister returns a new DeviceSetLister.
+func NewDeviceSetLister(indexer cache.Indexer) DeviceSetLister {
+	return &deviceSetLister{indexer: indexer}
+}
+
+// List lists all DeviceSets in the indexer.
+func (s *deviceSetLister) List(selector labels.Selector) (ret []*v1alpha1.DeviceSet, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.DeviceSet))
+	})
+	return ret, err
+}
+
+// DeviceSets returns an object that can list and get DeviceSets.
+func (s *deviceSetLister) DeviceSets(namespace string) DeviceSetNamespaceLister {
+	return deviceSetNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// DeviceSetNamespaceLister helps list and get DeviceSets.
+// All objects returned here must be treated as read-only.
+type DeviceSetNamespaceLister interface {
+	// List lists all DeviceSets in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.DeviceSet, err error)
+	// Get retrieves the DeviceSet from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.DeviceSet, error)
+	DeviceSetNamespaceListerExpansion
+}
+
+// deviceSetNamespaceLister implements the DeviceSetNamespaceLister
+// interface.
+type deviceSetNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all DeviceSets in the indexer for a given namespace.
+func (s deviceSetNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.DeviceSet, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.DeviceSet))
+	})
+	return ret, err
+}
+
+// Get retrieves the DeviceSet from the indexer for a given namespace and name.
+func (s deviceSetNamespaceLister) Get(name string) (*v1alpha1.DeviceSet, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("deviceset"), name)
+	}
+	return obj.(*v1alpha1.DeviceSet), nil
+}
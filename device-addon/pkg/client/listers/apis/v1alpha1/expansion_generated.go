@@ -18,6 +18,22 @@ type DeviceAddOnConfigListerExpansion interface{}
 // DeviceAddOnConfigNamespaceLister.
 type DeviceAddOnConfigNamespaceListerExpansion interface{}
 
+// DeviceProfileListerExpansion allows custom methods to be added to
+// DeviceProfileLister.
+type DeviceProfileListerExpansion interface{}
+
+// DeviceProfileNamespaceListerExpansion allows custom methods to be added to
+// DeviceProfileNamespaceLister.
+type DeviceProfileNamespaceListerExpansion interface{}
+
+// DeviceSetListerExpansion allows custom methods to be added to
+// DeviceSetLister.
+type DeviceSetListerExpansion interface{}
+
+// DeviceSetNamespaceListerExpansion allows custom methods to be added to
+// DeviceSetNamespaceLister.
+type DeviceSetNamespaceListerExpansion interface{}
+
 // DriverListerExpansion allows custom methods to be added to
 // DriverLister.
 type DriverListerExpansion interface{}
@@ -1,17 +1,40 @@
 package addon
 
 import (
+	"context"
+
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/addon/hub"
 
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/component-base/version"
+	"k8s.io/klog/v2"
 	"open-cluster-management.io/addon-framework/pkg/cmd/factory"
 )
 
 func NewManagerCommand() *cobra.Command {
-	cmd := factory.NewControllerCommandConfig("device-addon-manager", version.Get(), hub.Run).NewCommand()
+	o := hub.NewManagerOptions()
+	cmd := factory.NewControllerCommandConfig("device-addon-manager", version.Get(), o.RunManager).NewCommand()
 	cmd.Use = "manager"
 	cmd.Short = "Start the addon manager"
 
+	// the webhook is served by every replica before the leader election of the manager
+	run := cmd.Run
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		kubeConfigFile, _ := cmd.Flags().GetString("kubeconfig")
+		kubeConfig, err := clientcmd.BuildConfigFromFlags("", kubeConfigFile)
+		if err != nil {
+			klog.Fatal(err)
+		}
+
+		if err := o.RunWebhook(context.Background(), kubeConfig); err != nil {
+			klog.Fatal(err)
+		}
+
+		run(cmd, args)
+	}
+
+	o.AddFlags(cmd.Flags())
+
 	return cmd
 }
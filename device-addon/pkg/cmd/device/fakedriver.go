@@ -0,0 +1,75 @@
+package device
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"k8s.io/apiserver/pkg/server"
+	"k8s.io/component-base/logs"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers/remote"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers/remote/fake"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// NewFakeDriverCommand starts the reference remote driver, it can be used as a sidecar to test the remote drivers
+func NewFakeDriverCommand() *cobra.Command {
+	address := "unix:///var/run/device-addon/drivers/fake.sock"
+	interval := 5 * time.Second
+	certFile := ""
+	keyFile := ""
+	clientCAFile := ""
+	cmd := &cobra.Command{
+		Use:   "fake-driver",
+		Short: "Start the fake remote driver",
+		Run: func(cmd *cobra.Command, args []string) {
+			logs.InitLogs()
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			shutdownHandler := server.SetupSignalHandler()
+			go func() {
+				defer cancel()
+				<-shutdownHandler
+			}()
+
+			opts := []grpc.ServerOption{}
+			if len(certFile) != 0 {
+				tlsConfig, err := util.NewTLSConfig("", certFile, keyFile, false)
+				if err != nil {
+					klog.Fatal(err)
+				}
+
+				// the client certificates are verified if the client CA file is set
+				if len(clientCAFile) != 0 {
+					caTLSConfig, err := util.NewTLSConfig(clientCAFile, "", "", false)
+					if err != nil {
+						klog.Fatal(err)
+					}
+					tlsConfig.ClientCAs = caTLSConfig.RootCAs
+					tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+				}
+				opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+			}
+
+			if err := remote.Serve(ctx, address, fake.NewFakeDriver(interval), opts...); err != nil {
+				klog.Fatal(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&address, "address", address, "Address to serve the driver, unix:///<path> or <host>:<port>.")
+	cmd.Flags().DurationVar(&interval, "interval", interval, "Interval to generate the fake readings.")
+	cmd.Flags().StringVar(&certFile, "tls-cert-file", certFile,
+		"File of the server certificate, the driver is served with TLS if it is set.")
+	cmd.Flags().StringVar(&keyFile, "tls-private-key-file", keyFile, "File of the server private key.")
+	cmd.Flags().StringVar(&clientCAFile, "client-ca-file", clientCAFile,
+		"File of the CA to verify the client certificates, the client certificates are required if it is set.")
+
+	return cmd
+}
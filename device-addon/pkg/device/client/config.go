@@ -1,5 +1,20 @@
 package client
 
+const (
+	AuthModeAnonymous    = "anonymous"
+	AuthModeBasic        = "basic"
+	AuthModeCertificates = "certificates"
+)
+
+// The credential files in the credential dir
+const (
+	UsernameFile = "username"
+	PasswordFile = "password"
+	CAFile       = "ca.crt"
+	CertFile     = "tls.crt"
+	KeyFile      = "tls.key"
+)
+
 type MQTTBrokerInfo struct {
 	Host      string `json:"host"`
 	ClientId  string `json:"clientId"`
@@ -9,6 +24,17 @@ type MQTTBrokerInfo struct {
 	ConnEstablishingRetry int `json:"connEstablishingRetry"`
 
 	// anonymous, basic or certificates (ca, cert and key)
+	// - anonymous: connect to the broker without credentials, the ca.crt in the credential dir is used to
+	//   verify the broker with TLS if it exists
+	// - basic: connect to the broker with the username and password files in the credential dir, the ca.crt
+	//   is used in the same way as anonymous
+	// - certificates: connect to the broker with TLS, the ca.crt, tls.crt and tls.key files in the credential
+	//   dir are required, the username and password files are also used if they exist
 	AuthMode      string `json:"authMode"`
 	CredentialDir string `json:"credentialDir"`
+
+	// Username and Password are used by the basic auth mode, they are read from the username and password files
+	// in the credential dir if they are not set
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
 }
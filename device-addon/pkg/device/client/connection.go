@@ -0,0 +1,230 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	dialTimeout          = 10 * time.Second
+	minReconnectInterval = 1 * time.Second
+	maxReconnectInterval = 2 * time.Minute
+)
+
+// MQTTConnection is a managed connection to a MQTT broker, it reconnects to the broker with an exponential
+// backoff when the connection is lost, and the subscriptions are resubscribed after it is reconnected.
+type MQTTConnection struct {
+	sync.RWMutex
+	brokerInfo         *MQTTBrokerInfo
+	router             paho.Router
+	client             *paho.Client
+	subscriptions      map[string]paho.SubscribeOptions
+	onConnectionChange func(connected bool)
+	cancelFunc         context.CancelFunc
+	done               chan struct{}
+}
+
+func NewMQTTConnection(brokerInfo *MQTTBrokerInfo, router paho.Router) *MQTTConnection {
+	return &MQTTConnection{
+		brokerInfo:    brokerInfo,
+		router:        router,
+		subscriptions: make(map[string]paho.SubscribeOptions),
+	}
+}
+
+// OnConnectionChange sets a func that is called when the connection state is changed, it should be set
+// before the connection is started
+func (c *MQTTConnection) OnConnectionChange(f func(connected bool)) {
+	c.onConnectionChange = f
+}
+
+// Start connects to the broker in the background, the first connection is waited until it is established or
+// the connection establishing retry times are exhausted, in the latter case, the connection keeps retrying in
+// the background.
+func (c *MQTTConnection) Start(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	c.cancelFunc = cancel
+	c.done = make(chan struct{})
+
+	firstConnected := make(chan struct{})
+	go func() {
+		defer close(c.done)
+		c.run(runCtx, firstConnected)
+	}()
+
+	for i := 0; i <= c.brokerInfo.ConnEstablishingRetry; i++ {
+		select {
+		case <-firstConnected:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(dialTimeout):
+		}
+	}
+
+	klog.Warningf("The connection to MQTT broker %s is not established, keep retrying in the background", c.brokerInfo.Host)
+	return nil
+}
+
+// Disconnect stops the reconnecting and disconnects from the broker
+func (c *MQTTConnection) Disconnect() {
+	if c.cancelFunc == nil {
+		return
+	}
+
+	c.cancelFunc()
+	<-c.done
+}
+
+func (c *MQTTConnection) IsConnected() bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.client != nil
+}
+
+// Subscribe subscribes to a topic, the subscription is kept and resubscribed after the connection is
+// reconnected. If the connection is not established, the subscription is done once it is established.
+func (c *MQTTConnection) Subscribe(ctx context.Context, topic string, options paho.SubscribeOptions) error {
+	c.Lock()
+	c.subscriptions[topic] = options
+	client := c.client
+	c.Unlock()
+
+	if client == nil {
+		klog.Warningf("The MQTT broker %s is not connected, subscribe to %s once it is connected", c.brokerInfo.Host, topic)
+		return nil
+	}
+
+	return subscribe(ctx, client, map[string]paho.SubscribeOptions{topic: options})
+}
+
+// Unsubscribe unsubscribes from a topic, the topic is not resubscribed after the connection is reconnected
+func (c *MQTTConnection) Unsubscribe(ctx context.Context, topic string) error {
+	c.Lock()
+	delete(c.subscriptions, topic)
+	client := c.client
+	c.Unlock()
+
+	if client == nil {
+		return nil
+	}
+
+	if _, err := client.Unsubscribe(ctx, &paho.Unsubscribe{Topics: []string{topic}}); err != nil {
+		return fmt.Errorf("failed to unsubscribe from %s, %v", topic, err)
+	}
+
+	return nil
+}
+
+// Publish publishes a message to the broker, an error is returned if the connection is not established
+func (c *MQTTConnection) Publish(ctx context.Context, p *paho.Publish) (*paho.PublishResponse, error) {
+	c.RLock()
+	client := c.client
+	c.RUnlock()
+
+	if client == nil {
+		return nil, fmt.Errorf("the MQTT broker %s is not connected", c.brokerInfo.Host)
+	}
+
+	return client.Publish(ctx, p)
+}
+
+func (c *MQTTConnection) run(ctx context.Context, firstConnected chan struct{}) {
+	interval := minReconnectInterval
+	for {
+		lost := make(chan struct{})
+		var lostOnce sync.Once
+		client, err := connect(ctx, c.brokerInfo, c.router, func(err error) {
+			lostOnce.Do(func() {
+				klog.Warningf("The connection to MQTT broker %s is lost, %v", c.brokerInfo.Host, err)
+				close(lost)
+			})
+		})
+		if err == nil {
+			err = subscribe(ctx, client, c.getSubscriptions())
+			if err != nil {
+				_ = client.Disconnect(&paho.Disconnect{ReasonCode: 0})
+			}
+		}
+		if err != nil {
+			klog.Warningf("Unable to connect to MQTT broker %s, retry after %s, %v", c.brokerInfo.Host, interval, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+
+			interval = interval * 2
+			if interval > maxReconnectInterval {
+				interval = maxReconnectInterval
+			}
+			continue
+		}
+
+		interval = minReconnectInterval
+		c.setClient(client)
+		if firstConnected != nil {
+			close(firstConnected)
+			firstConnected = nil
+		}
+
+		select {
+		case <-ctx.Done():
+			c.setClient(nil)
+			_ = client.Disconnect(&paho.Disconnect{ReasonCode: 0})
+			klog.Infof("MQTT client %s is disconnected from %s", c.brokerInfo.ClientId, c.brokerInfo.Host)
+			return
+		case <-lost:
+			c.setClient(nil)
+			// close the lost client before it is redialed, so its connection and goroutines are released
+			_ = client.Disconnect(&paho.Disconnect{ReasonCode: 0})
+		}
+	}
+}
+
+func (c *MQTTConnection) setClient(client *paho.Client) {
+	c.Lock()
+	c.client = client
+	c.Unlock()
+
+	if c.onConnectionChange != nil {
+		c.onConnectionChange(client != nil)
+	}
+}
+
+func (c *MQTTConnection) getSubscriptions() map[string]paho.SubscribeOptions {
+	c.RLock()
+	defer c.RUnlock()
+
+	subscriptions := make(map[string]paho.SubscribeOptions, len(c.subscriptions))
+	for topic, options := range c.subscriptions {
+		subscriptions[topic] = options
+	}
+	return subscriptions
+}
+
+func subscribe(ctx context.Context, client *paho.Client, subscriptions map[string]paho.SubscribeOptions) error {
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	suback, err := client.Subscribe(ctx, &paho.Subscribe{Subscriptions: subscriptions})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %v, %v", subscriptions, err)
+	}
+
+	for _, reason := range suback.Reasons {
+		if reason >= 0x80 {
+			return fmt.Errorf("failed to subscribe to %v, the reason code is %d", subscriptions, reason)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	mochi "github.com/mochi-co/mqtt/v2"
+	"github.com/mochi-co/mqtt/v2/hooks/auth"
+	"github.com/mochi-co/mqtt/v2/listeners"
+	"github.com/rs/zerolog"
+)
+
+func freeAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	return listener.Addr().String()
+}
+
+// startBroker starts a broker that allows the anonymous clients on the address
+func startBroker(t *testing.T, address string) *mochi.Server {
+	server := mochi.New(nil)
+	l := server.Log.Level(zerolog.Disabled)
+	server.Log = &l
+
+	if err := server.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.AddListener(listeners.NewTCP("tcp", address, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Serve(); err != nil {
+		t.Fatal(err)
+	}
+
+	return server
+}
+
+func waitForConnection(t *testing.T, changes chan bool, expected bool) {
+	select {
+	case connected := <-changes:
+		if connected != expected {
+			t.Fatalf("expected the connection state %v, but got %v", expected, connected)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("expected the connection state %v, but it is not changed", expected)
+	}
+}
+
+// waitForMessage publishes the payload until it is received, the subscription may not be done by the broker
+// right after the client is connected
+func waitForMessage(t *testing.T, broker *mochi.Server, received chan string, payload string) {
+	timeout := time.After(10 * time.Second)
+	for {
+		if err := broker.Publish("devices/device1/data", []byte(payload), false, 0); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case msg := <-received:
+			if msg == payload {
+				return
+			}
+		case <-time.After(100 * time.Millisecond):
+		case <-timeout:
+			t.Fatalf("the message %s is not received", payload)
+		}
+	}
+}
+
+func TestReconnectAndResubscribe(t *testing.T) {
+	address := freeAddress(t)
+	broker := startBroker(t, address)
+
+	received := make(chan string, 100)
+	router := paho.NewSingleHandlerRouter(func(p *paho.Publish) {
+		received <- string(p.Payload)
+	})
+
+	changes := make(chan bool, 10)
+	conn := NewMQTTConnection(&MQTTBrokerInfo{Host: address, ClientId: "test-client", KeepAlive: 30}, router)
+	conn.OnConnectionChange(func(connected bool) { changes <- connected })
+
+	if err := conn.Start(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Disconnect()
+
+	waitForConnection(t, changes, true)
+	if err := conn.Subscribe(context.TODO(), "devices/+/data", paho.SubscribeOptions{QoS: 0}); err != nil {
+		t.Fatal(err)
+	}
+	waitForMessage(t, broker, received, "r1")
+
+	// the connection is lost once the broker is stopped
+	if err := broker.Close(); err != nil {
+		t.Fatal(err)
+	}
+	waitForConnection(t, changes, false)
+	if conn.IsConnected() {
+		t.Errorf("expected the connection is lost")
+	}
+	if _, err := conn.Publish(context.TODO(), &paho.Publish{Topic: "devices/device1/data"}); err == nil {
+		t.Errorf("expected the message is failed to publish without connection")
+	}
+
+	// the topic is resubscribed on the restarted broker once the connection is reconnected
+	broker = startBroker(t, address)
+	defer broker.Close()
+
+	waitForConnection(t, changes, true)
+	waitForMessage(t, broker, received, "r2")
+
+	// the unsubscribed topic is not resubscribed
+	if err := conn.Unsubscribe(context.TODO(), "devices/+/data"); err != nil {
+		t.Fatal(err)
+	}
+	if subscriptions := conn.getSubscriptions(); len(subscriptions) != 0 {
+		t.Errorf("expected no subscription, but got %v", subscriptions)
+	}
+}
@@ -2,19 +2,25 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
-	"time"
+	"os"
+	"path"
+	"strings"
 
+	"github.com/eclipse/paho.golang/packets"
 	"github.com/eclipse/paho.golang/paho"
 
 	"k8s.io/klog/v2"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
 )
 
 type debugLogger struct{}
 
 func (l *debugLogger) Println(v ...interface{}) {
-	klog.Infoln(v)
+	klog.Infoln(v...)
 }
 
 func (l *debugLogger) Printf(format string, v ...interface{}) {
@@ -24,38 +30,37 @@ func (l *debugLogger) Printf(format string, v ...interface{}) {
 type errorLogger struct{}
 
 func (l *errorLogger) Println(v ...interface{}) {
-	klog.Errorln(v)
+	klog.Errorln(v...)
 }
 
 func (l *errorLogger) Printf(format string, v ...interface{}) {
 	klog.Errorf(format, v...)
 }
 
-func ConnectToMQTTBroker(ctx context.Context, brokerInfo *MQTTBrokerInfo, router paho.Router) (*paho.Client, error) {
-	var err error
-	var conn net.Conn
-
-	username, password, err := getUser()
+// connect dials the MQTT broker and connects to it once, the onLost is called when the connection is lost
+func connect(ctx context.Context, brokerInfo *MQTTBrokerInfo, router paho.Router, onLost func(err error)) (*paho.Client, error) {
+	username, password, err := getUser(brokerInfo)
 	if err != nil {
 		return nil, err
 	}
 
-	for i := 0; i <= brokerInfo.ConnEstablishingRetry; i++ {
-		conn, err = net.Dial("tcp", brokerInfo.Host)
-		if err != nil {
-			if i >= brokerInfo.ConnEstablishingRetry {
-				return nil, fmt.Errorf("failed to connect to MQTT broker %s, %v", brokerInfo.Host, err)
-			}
-
-			klog.Warningf("Unable to connect to MQTT broker, %s, retrying", brokerInfo.Host)
-			time.Sleep(10 * time.Second)
-			continue
-		}
+	tlsConfig, err := getTLSConfig(brokerInfo)
+	if err != nil {
+		return nil, err
+	}
 
-		break
+	conn, err := dial(ctx, brokerInfo.Host, tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial MQTT broker %s, %v", brokerInfo.Host, err)
 	}
 
-	client := paho.NewClient(paho.ClientConfig{Conn: conn})
+	client := paho.NewClient(paho.ClientConfig{
+		Conn:          conn,
+		OnClientError: onLost,
+		OnServerDisconnect: func(d *paho.Disconnect) {
+			onLost(fmt.Errorf("disconnected by the server with the reason code %d", d.ReasonCode))
+		},
+	})
 
 	if router != nil {
 		client.Router = router
@@ -81,9 +86,11 @@ func ConnectToMQTTBroker(ctx context.Context, brokerInfo *MQTTBrokerInfo, router
 
 	ca, err := client.Connect(ctx, cp)
 	if err != nil {
+		_ = conn.Close()
 		return nil, fmt.Errorf("failed to connect to MQTT broker %s, %v", brokerInfo.Host, err)
 	}
 	if ca.ReasonCode != 0 {
+		_ = conn.Close()
 		return nil, fmt.Errorf("failed to connect to MQTT broker %s, %d - %s",
 			brokerInfo.Host, ca.ReasonCode, ca.Properties.ReasonString)
 	}
@@ -92,6 +99,108 @@ func ConnectToMQTTBroker(ctx context.Context, brokerInfo *MQTTBrokerInfo, router
 	return client, nil
 }
 
-func getUser() (username, password string, err error) {
+// dial dials the broker with TLS if the tls config is set, the conn is written by the paho client goroutines
+// concurrently, so it is wrapped to be thread safe for writing
+func dial(ctx context.Context, host string, tlsConfig *tls.Config) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+
+	var conn net.Conn
+	var err error
+	if tlsConfig == nil {
+		conn, err = dialer.DialContext(ctx, "tcp", host)
+	} else {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: tlsConfig}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", host)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return packets.NewThreadSafeConn(conn), nil
+}
+
+// getUser reads the username and password from the credential dir with the basic or certificates auth mode
+func getUser(brokerInfo *MQTTBrokerInfo) (username, password string, err error) {
+	switch brokerInfo.AuthMode {
+	case AuthModeBasic:
+		if len(brokerInfo.Username) != 0 && len(brokerInfo.Password) != 0 {
+			return brokerInfo.Username, brokerInfo.Password, nil
+		}
+
+		username, err = readCredential(brokerInfo.CredentialDir, UsernameFile, true)
+		if err != nil {
+			return "", "", err
+		}
+
+		password, err = readCredential(brokerInfo.CredentialDir, PasswordFile, true)
+		if err != nil {
+			return "", "", err
+		}
+
+		return username, password, nil
+	case AuthModeCertificates:
+		username, err = readCredential(brokerInfo.CredentialDir, UsernameFile, false)
+		if err != nil {
+			return "", "", err
+		}
+
+		password, err = readCredential(brokerInfo.CredentialDir, PasswordFile, false)
+		if err != nil {
+			return "", "", err
+		}
+
+		return username, password, nil
+	}
+
 	return "", "", nil
 }
+
+// getTLSConfig returns the tls config with the credential dir, nil is returned if TLS is not required
+func getTLSConfig(brokerInfo *MQTTBrokerInfo) (*tls.Config, error) {
+	if len(brokerInfo.CredentialDir) == 0 {
+		if brokerInfo.AuthMode == AuthModeCertificates {
+			return nil, fmt.Errorf("the credential dir is required by the auth mode %s", AuthModeCertificates)
+		}
+		return nil, nil
+	}
+
+	caFile := path.Join(brokerInfo.CredentialDir, CAFile)
+	certFile := path.Join(brokerInfo.CredentialDir, CertFile)
+	keyFile := path.Join(brokerInfo.CredentialDir, KeyFile)
+
+	if brokerInfo.AuthMode == AuthModeCertificates {
+		for _, file := range []string{caFile, certFile, keyFile} {
+			if _, err := os.Stat(file); err != nil {
+				return nil, fmt.Errorf("the %s is required by the auth mode %s, %v", file, AuthModeCertificates, err)
+			}
+		}
+
+		return util.NewTLSConfig(caFile, certFile, keyFile, false)
+	}
+
+	if _, err := os.Stat(caFile); err != nil {
+		// the broker is not served with TLS
+		return nil, nil
+	}
+
+	return util.NewTLSConfig(caFile, "", "", false)
+}
+
+func readCredential(dir, name string, required bool) (string, error) {
+	if len(dir) == 0 {
+		if required {
+			return "", fmt.Errorf("the credential dir is required to read the %s", name)
+		}
+		return "", nil
+	}
+
+	data, err := os.ReadFile(path.Join(dir, name))
+	if os.IsNotExist(err) && !required {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read the %s from %s, %v", name, dir, err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
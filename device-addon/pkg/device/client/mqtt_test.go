@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"sync"
+	"testing"
+
+	certutil "k8s.io/client-go/util/cert"
+)
+
+func TestDial(t *testing.T) {
+	certData, keyData, err := certutil.GenerateSelfSignedCertKey("127.0.0.1", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := tls.X509KeyPair(certData, keyData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(certData)
+
+	cases := []struct {
+		name            string
+		serverTLSConfig *tls.Config
+		clientTLSConfig *tls.Config
+	}{
+		{
+			name: "plain tcp",
+		},
+		{
+			name:            "tls",
+			serverTLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12},
+			clientTLSConfig: &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer listener.Close()
+
+			go func() {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+
+				if c.serverTLSConfig != nil {
+					_ = tls.Server(conn, c.serverTLSConfig).Handshake()
+				}
+			}()
+
+			conn, err := dial(context.TODO(), listener.Addr().String(), c.clientTLSConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+
+			// the packets are written with the lock of the conn, since the conn is written by the client goroutines
+			// concurrently
+			if _, ok := conn.(sync.Locker); !ok {
+				t.Errorf("expected the conn is thread safe, but got %T", conn)
+			}
+		})
+	}
+}
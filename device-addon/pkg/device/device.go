@@ -3,11 +3,17 @@ package device
 import (
 	"context"
 	"path"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/pflag"
 
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/klog/v2"
+
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/equipment"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/metrics"
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
 )
 
@@ -17,12 +23,17 @@ const (
 	devicesConfigFileName = "devices.yaml"
 )
 
-type DriverAgentOptions struct {
-	ConfigDir string
-}
+// reloadDelay is used to merge the file events that are produced by one change
+const reloadDelay = 1 * time.Second
 
-type messageBusList struct {
-	MessageBuses []v1alpha1.MessageBusConfig `yaml:"messageBuses"`
+// retryInterval is the interval to reload the config files again if some changes are failed to apply, e.g. a driver
+// is failed to start because its server is not ready
+var retryInterval = 10 * time.Second
+
+type DriverAgentOptions struct {
+	ConfigDir      string
+	MetricsAddress string
+	ReadingMetrics bool
 }
 
 type driverList struct {
@@ -33,51 +44,248 @@ type deviceList struct {
 	Devices []v1alpha1.DeviceConfig `yaml:"devices"`
 }
 
+// configState is the configurations that are applied to the equipment
+type configState struct {
+	messageBuses []v1alpha1.MessageBusConfig
+	rules        []v1alpha1.ProcessingRule
+	drivers      map[string]v1alpha1.DriverConfig
+	devices      map[string]v1alpha1.DeviceConfig
+}
+
 func NewDriverAgentOptions() *DriverAgentOptions {
-	return &DriverAgentOptions{}
+	return &DriverAgentOptions{
+		MetricsAddress: "127.0.0.1:8080",
+	}
 }
 
 func (o *DriverAgentOptions) AddFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&o.ConfigDir, "config-dir", o.ConfigDir, "Directory of config files")
+	flags.StringVar(&o.MetricsAddress, "metrics-address", o.MetricsAddress,
+		"Address to serve the metrics, it is bound to the localhost by default, "+
+			"the metrics are not authenticated, so they should be exposed with an authenticating proxy, "+
+			"e.g. kube-rbac-proxy. The metrics are not served if it is empty.")
+	flags.BoolVar(&o.ReadingMetrics, "reading-metrics", o.ReadingMetrics,
+		"Expose the latest numeric readings of the devices as the metrics.")
 }
 
-// RunAgent starts the controllers on agent to process work from hub.
-// TODO watch the configuration files changes with "github.com/fsnotify/fsnotify"
+// RunDriverAgent starts the drivers with the config files and watches the config files changes.
 func (o *DriverAgentOptions) RunDriverAgent(ctx context.Context) error {
-	config := &messageBusList{}
-	if err := util.LoadConfig(path.Join(o.ConfigDir, configFileName), config); err != nil {
+	desired, err := o.loadConfigState()
+	if err != nil {
 		return err
 	}
 
-	driverList := &driverList{}
-	if err := util.LoadConfig(path.Join(o.ConfigDir, driversConfigFileName), driverList); err != nil {
+	e := equipment.NewEquipment()
+
+	if err := e.SetRules(desired.rules); err != nil {
 		return err
 	}
 
-	deviceList := &deviceList{}
-	if err := util.LoadConfig(path.Join(o.ConfigDir, devicesConfigFileName), deviceList); err != nil {
+	if err := e.Start(ctx, desired.messageBuses); err != nil {
 		return err
 	}
 
-	e := equipment.NewEquipment()
+	if len(o.MetricsAddress) != 0 {
+		if o.ReadingMetrics {
+			metrics.EnableReadingValues()
+		}
+
+		if err := e.RegisterMetrics(); err != nil {
+			return err
+		}
+
+		go metrics.Serve(ctx, o.MetricsAddress)
+	}
+
+	state := reload(ctx, e, &configState{
+		messageBuses: desired.messageBuses,
+		rules:        desired.rules,
+		drivers:      make(map[string]v1alpha1.DriverConfig),
+		devices:      make(map[string]v1alpha1.DeviceConfig),
+	}, desired)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
 
-	if err := e.Start(ctx, config.MessageBuses); err != nil {
+	if err := watcher.Add(o.ConfigDir); err != nil {
 		return err
 	}
 
+	klog.Infof("Watching the config files in %s", o.ConfigDir)
+
+	reloadTimer := time.NewTimer(reloadDelay)
+	reloadTimer.Stop()
+	defer reloadTimer.Stop()
+
+	if !state.equal(desired) {
+		reloadTimer.Reset(retryInterval)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			klog.V(4).Infof("Config file event %s", event)
+			reloadTimer.Reset(reloadDelay)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			klog.Errorf("failed to watch the config files, %v", err)
+		case <-reloadTimer.C:
+			newState, err := o.loadConfigState()
+			if err != nil {
+				klog.Errorf("failed to reload the config files, keep the current config, %v", err)
+				continue
+			}
+
+			state = reload(ctx, e, state, newState)
+			if !state.equal(newState) {
+				klog.Infof("Some config changes are failed to apply, retry in %s", retryInterval)
+				reloadTimer.Reset(retryInterval)
+			}
+		}
+	}
+}
+
+func (o *DriverAgentOptions) loadConfigState() (*configState, error) {
+	config := &v1alpha1.DeviceAddOnConfigSpec{}
+	if err := util.LoadConfig(path.Join(o.ConfigDir, configFileName), config); err != nil {
+		return nil, err
+	}
+
+	driverList := &driverList{}
+	if err := util.LoadConfig(path.Join(o.ConfigDir, driversConfigFileName), driverList); err != nil {
+		return nil, err
+	}
+
+	deviceList := &deviceList{}
+	if err := util.LoadConfig(path.Join(o.ConfigDir, devicesConfigFileName), deviceList); err != nil {
+		return nil, err
+	}
+
+	state := &configState{
+		messageBuses: config.MessageBuses,
+		rules:        config.Rules,
+		drivers:      make(map[string]v1alpha1.DriverConfig),
+		devices:      make(map[string]v1alpha1.DeviceConfig),
+	}
+
 	for _, driver := range driverList.Drivers {
+		state.drivers[driver.DriverType] = driver
+	}
+
+	for _, device := range deviceList.Devices {
+		state.devices[device.Name] = device
+	}
+
+	return state, nil
+}
+
+// equal returns true if the configurations of the states are the same
+func (s *configState) equal(other *configState) bool {
+	return equality.Semantic.DeepEqual(s.messageBuses, other.messageBuses) &&
+		equality.Semantic.DeepEqual(s.rules, other.rules) &&
+		equality.Semantic.DeepEqual(s.drivers, other.drivers) &&
+		equality.Semantic.DeepEqual(s.devices, other.devices)
+}
+
+// reload applies the differences between the current and new configurations to the equipment, the drivers
+// and devices that are not changed are kept running. The returned state is the configurations that are
+// applied, a failed change is retried with the next reload.
+func reload(ctx context.Context, e *equipment.Equipment, current, desired *configState) *configState {
+	applied := &configState{
+		messageBuses: current.messageBuses,
+		rules:        current.rules,
+		drivers:      make(map[string]v1alpha1.DriverConfig),
+		devices:      make(map[string]v1alpha1.DeviceConfig),
+	}
+
+	if !equality.Semantic.DeepEqual(current.messageBuses, desired.messageBuses) {
+		klog.Infof("Update the message buses")
+		if err := e.SetMessageBuses(ctx, desired.messageBuses); err != nil {
+			klog.Errorf("failed to update the message buses, %v", err)
+		} else {
+			applied.messageBuses = desired.messageBuses
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(current.rules, desired.rules) {
+		klog.Infof("Update the processing rules")
+		if err := e.SetRules(desired.rules); err != nil {
+			klog.Errorf("failed to update the processing rules, %v", err)
+		} else {
+			applied.rules = desired.rules
+		}
+	}
+
+	for name, device := range current.devices {
+		if _, ok := desired.devices[name]; ok {
+			continue
+		}
+
+		klog.Infof("Remove the device %s", name)
+		if err := e.RemoveDevice(name); err != nil {
+			klog.Errorf("failed to remove device %s, %v", name, err)
+			applied.devices[name] = device
+		}
+	}
+
+	for driverType, driver := range current.drivers {
+		if _, ok := desired.drivers[driverType]; ok {
+			continue
+		}
+
+		klog.Infof("Uninstall the driver %s", driverType)
+		if err := e.UnInstallDriver(driver); err != nil {
+			klog.Errorf("failed to uninstall driver %s, %v", driverType, err)
+			applied.drivers[driverType] = driver
+		}
+	}
+
+	for driverType, driver := range desired.drivers {
+		if last, ok := current.drivers[driverType]; ok && equality.Semantic.DeepEqual(last, driver) {
+			applied.drivers[driverType] = driver
+			continue
+		}
+
+		klog.Infof("Install the driver %s", driverType)
 		if err := e.InstallDriver(driver); err != nil {
-			return err
+			klog.Errorf("failed to install driver %s, %v", driverType, err)
+			// the installed driver is kept running, it is uninstalled if the driver is removed before the retry
+			if last, ok := current.drivers[driverType]; ok {
+				applied.drivers[driverType] = last
+			}
+			continue
 		}
+		applied.drivers[driverType] = driver
 	}
 
-	for _, device := range deviceList.Devices {
-		d := e.GetDriver(device.DriverType)
-		if d != nil {
-			d.AddDevice(device)
+	for name, device := range desired.devices {
+		if last, ok := current.devices[name]; ok && equality.Semantic.DeepEqual(last, device) {
+			applied.devices[name] = device
+			continue
+		}
+
+		klog.Infof("Add the device %s", name)
+		if err := e.AddDevice(device); err != nil {
+			klog.Errorf("failed to add device %s, %v", name, err)
+			if last, ok := current.devices[name]; ok {
+				applied.devices[name] = last
+			}
+			continue
 		}
+		applied.devices[name] = device
 	}
 
-	<-ctx.Done()
-	return nil
+	return applied
 }
@@ -0,0 +1,119 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+func freeAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	return listener.Addr().String()
+}
+
+func isListening(address string) bool {
+	conn, err := net.DialTimeout("tcp", address, time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+func waitFor(t *testing.T, message string, condition func() bool) {
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("timeout to wait for %s", message)
+}
+
+func writeConfig(t *testing.T, dir, fileName, content string) {
+	if err := os.WriteFile(path.Join(dir, fileName), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeDriversConfig writes the http driver whose webhook listens on the address, no driver is written if the
+// address is empty
+func writeDriversConfig(t *testing.T, dir, address string) {
+	if len(address) == 0 {
+		writeConfig(t, dir, driversConfigFileName, "drivers: []\n")
+		return
+	}
+
+	writeConfig(t, dir, driversConfigFileName, fmt.Sprintf(`drivers:
+- type: "http"
+  properties:
+    webhook:
+      address: %q
+      insecure: true
+`, address))
+}
+
+func TestReloadConfig(t *testing.T) {
+	defer func(interval time.Duration) { retryInterval = interval }(retryInterval)
+	retryInterval = 100 * time.Millisecond
+
+	dir := t.TempDir()
+	writeConfig(t, dir, configFileName, "{}\n")
+	writeConfig(t, dir, devicesConfigFileName, "devices: []\n")
+	writeDriversConfig(t, dir, "")
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	stopped := make(chan struct{})
+	defer func() {
+		cancel()
+		<-stopped
+	}()
+
+	o := &DriverAgentOptions{ConfigDir: dir}
+	go func() {
+		defer close(stopped)
+		if err := o.RunDriverAgent(ctx); err != nil {
+			t.Errorf("failed to run the driver agent, %v", err)
+		}
+	}()
+
+	// wait for the config dir is watched
+	time.Sleep(200 * time.Millisecond)
+
+	// add the driver
+	address := freeAddress(t)
+	writeDriversConfig(t, dir, address)
+	waitFor(t, "the driver is installed", func() bool { return isListening(address) })
+
+	// change the driver, the new driver is failed to start, so the installed one is kept
+	occupied, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changedAddress := occupied.Addr().String()
+	writeDriversConfig(t, dir, changedAddress)
+	time.Sleep(2 * reloadDelay)
+	if !isListening(address) {
+		t.Errorf("expected the installed driver is kept")
+	}
+
+	// the failed change is retried without a config file event
+	occupied.Close()
+	waitFor(t, "the driver is reinstalled", func() bool {
+		return !isListening(address) && isListening(changedAddress)
+	})
+
+	// remove the driver
+	writeDriversConfig(t, dir, "")
+	waitFor(t, "the driver is uninstalled", func() bool { return !isListening(changedAddress) })
+}
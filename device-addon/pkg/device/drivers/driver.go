@@ -2,14 +2,16 @@ package drivers
 
 import (
 	"context"
+	"fmt"
 
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers/http"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers/modbus"
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers/mqtt"
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers/opcua"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers/remote"
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/messagebuses"
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
-
-	"k8s.io/klog/v2"
 )
 
 type Driver interface {
@@ -23,18 +25,61 @@ type Driver interface {
 
 	RunCommand(command util.Command) error
 
+	// GetDeviceState returns the live state of a device, nil is returned if the device state is unknown
+	GetDeviceState(deviceName string) *util.DeviceState
+
 	GetType() string
 }
 
-func Get(driverType string, driverConfig map[string]interface{}, msgBuses []messagebuses.MessageBus) Driver {
+// Discoverer is implemented by the drivers that can discover the devices
+type Discoverer interface {
+	// Discover returns the devices that are found by the driver
+	Discover(ctx context.Context) ([]v1alpha1.DeviceConfig, error)
+}
+
+// ConnectionReporter is implemented by the drivers that have their own connection, e.g. the connection to the
+// MQTT broker or to the remote driver
+type ConnectionReporter interface {
+	// IsConnected returns true if the driver is connected
+	IsConnected() bool
+}
+
+// Get returns the driver of the driver type with the driver config, an error is returned if the driver type is
+// unsupported or the driver config is invalid
+func Get(driverType string, driverConfig map[string]interface{}, msgBuses []messagebuses.MessageBus) (Driver, error) {
+	// a driver with the remote property runs out of the agent process, its type is defined by its vendor
+	if remote.IsRemote(driverConfig) {
+		d, err := remote.NewRemoteDriver(driverType, driverConfig, msgBuses)
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
+
 	switch driverType {
 	case "mqtt":
-		return mqtt.NewMQTTDriver(driverConfig, msgBuses)
+		if d := mqtt.NewMQTTDriver(driverConfig, msgBuses); d != nil {
+			return d, nil
+		}
 	case "opcua":
-		return opcua.NewOPCUADriver(driverConfig, msgBuses)
+		if d := opcua.NewOPCUADriver(driverConfig, msgBuses); d != nil {
+			return d, nil
+		}
+	case "modbus":
+		d, err := modbus.NewModbusDriver(driverConfig, msgBuses)
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
+	case "http":
+		d, err := http.NewHTTPDriver(driverConfig, msgBuses)
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
 	default:
-		klog.Warningf("unsupported driver type %s", driverType)
+		return nil, fmt.Errorf("unsupported driver type %s", driverType)
 	}
 
-	return nil
+	return nil, fmt.Errorf("invalid config of driver %s", driverType)
 }
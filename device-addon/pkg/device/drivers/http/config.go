@@ -0,0 +1,89 @@
+package http
+
+const (
+	defaultPollInterval  = "5s"
+	defaultTimeout       = "5s"
+	defaultMethod        = "GET"
+	defaultCommandMethod = "POST"
+	defaultWebhookPath   = "/webhook"
+
+	// the token, certificate and key files in the credential dir
+	tokenFile   = "token"
+	tlsCertFile = "tls.crt"
+	tlsKeyFile  = "tls.key"
+
+	// credentialSecretProperty is the driver property that references the credential Secret of the driver
+	credentialSecretProperty = "credentialSecret"
+)
+
+// The named placeholders of the command url
+const (
+	DevicePlaceholder  = "{device}"
+	CommandPlaceholder = "{command}"
+)
+
+// JSONPathAttribute selects the value of the resource from the json response or webhook payload, e.g.
+// sensors.temperature or values[0], if it is not set, the value is the field that is named with the resource name
+const JSONPathAttribute = "jsonPath"
+
+// Config is the http driver configuration, it provides the default values for the devices
+type Config struct {
+	PollInterval string  `json:"pollInterval"` // Interval to poll the device url, e.g. 5s
+	Timeout      string  `json:"timeout"`      // Timeout of one http request, e.g. 5s
+	Jitter       float64 `json:"jitter"`       // Jitter: the factor of the poll interval that is randomly added to each poll, e.g. 0.1
+	MaxBackoff   string  `json:"maxBackoff"`   // MaxBackoff: the maximum delay of the next poll if the device is unreachable, e.g. 5m
+
+	// Webhook is the inbound webhook endpoint that the devices push their payloads to, it is not started if it
+	// is not set
+	Webhook *WebhookConfig `json:"webhook,omitempty"`
+
+	// CredentialDir is the directory of the credential files
+	CredentialDir string `json:"credentialDir"`
+}
+
+// WebhookConfig is the inbound webhook endpoint, a device posts its json payload to <path>/<device-name>
+type WebhookConfig struct {
+	// Address is the listen address of the webhook, e.g. :8090
+	Address string `json:"address"`
+	// Path is the path prefix of the webhook, default is /webhook
+	Path string `json:"path"`
+	// Token is the bearer token that the devices should send in the Authorization header, it is read from the
+	// token file in the credential dir if it is not set. The webhook is not started if there is no token, unless
+	// the Insecure is true
+	Token string `json:"token"`
+	// Insecure accepts the unauthenticated requests if there is no token
+	Insecure bool `json:"insecure"`
+	// CertFile and KeyFile are the serving certificate and key of the webhook, they are the tls.crt and tls.key
+	// files in the credential dir if they are not set, the webhook is served with TLS if there are the files
+	CertFile string `json:"certFile"`
+	KeyFile  string `json:"keyFile"`
+}
+
+// ProtocolConfig is the http device protocol properties
+type ProtocolConfig struct {
+	// URL is the url to poll the device resources, the response is a json object. The device is not polled if
+	// it is not set, e.g. the device pushes its data to the webhook
+	URL string `json:"url"`
+	// Method is the method of the poll requests, default is GET
+	Method string `json:"method"`
+	// Headers are the headers of the poll requests, e.g. Authorization
+	Headers map[string]string `json:"headers"`
+
+	PollInterval string `json:"pollInterval"`
+	Timeout      string `json:"timeout"`
+
+	// Command is the request to run the device commands, the commands are not supported if it is not set
+	Command *RequestConfig `json:"command,omitempty"`
+}
+
+// RequestConfig is the http request of the device commands, the body of the request is a json object of the
+// command values that are keyed by the resource names
+type RequestConfig struct {
+	// URL is the url of the command requests, the {device} and {command} are replaced with the device name and
+	// the command name, e.g. http://127.0.0.1:8080/api/{command}
+	URL string `json:"url"`
+	// Method is the method of the command requests, default is POST
+	Method string `json:"method"`
+	// Headers are the headers of the command requests
+	Headers map[string]string `json:"headers"`
+}
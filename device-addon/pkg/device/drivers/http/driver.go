@@ -0,0 +1,446 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/messagebuses"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/poller"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// maxBodySize is the maximum size of the response and webhook payloads
+const maxBodySize = 1 << 20
+
+type httpDevice struct {
+	deviceConfig v1alpha1.DeviceConfig
+	protocol     *ProtocolConfig
+	client       *http.Client
+}
+
+// HTTPDriver polls the devices that expose a REST API and receives the payloads that are pushed by the devices
+// to its webhook, the commands are sent to the devices as http requests
+type HTTPDriver struct {
+	sync.Mutex
+	config   *Config
+	msgBuses []messagebuses.MessageBus
+	devices  map[string]*httpDevice
+	states   *util.DeviceStates
+	poller   *poller.Poller
+	server   *http.Server
+}
+
+func NewHTTPDriver(driverConfig util.ConfigProperties, msgBuses []messagebuses.MessageBus) (*HTTPDriver, error) {
+	var config = &Config{}
+	if err := util.ToConfigObj(driverConfig, config); err != nil {
+		return nil, fmt.Errorf("failed to parse http driver config, %v", err)
+	}
+
+	if len(config.PollInterval) == 0 {
+		config.PollInterval = defaultPollInterval
+	}
+
+	if len(config.Timeout) == 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	pollerConfig := poller.Config{Jitter: config.Jitter}
+	if len(config.MaxBackoff) != 0 {
+		maxBackoff, err := time.ParseDuration(config.MaxBackoff)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max backoff %s of http driver, %v", config.MaxBackoff, err)
+		}
+		pollerConfig.MaxBackoff = maxBackoff
+	}
+
+	d := &HTTPDriver{
+		config:   config,
+		msgBuses: msgBuses,
+		devices:  make(map[string]*httpDevice),
+		states:   util.NewDeviceStates(),
+	}
+	d.poller = poller.NewPoller(pollerConfig, d.read, msgBuses, d.states)
+	return d, nil
+}
+
+func (d *HTTPDriver) GetType() string {
+	return "http"
+}
+
+func (d *HTTPDriver) Start(ctx context.Context) error {
+	if d.config.Webhook == nil {
+		return nil
+	}
+
+	webhookPath := d.config.Webhook.Path
+	if len(webhookPath) == 0 {
+		webhookPath = defaultWebhookPath
+	}
+	webhookPath = strings.TrimSuffix(webhookPath, "/") + "/"
+
+	token, err := d.getToken()
+	if err != nil {
+		return err
+	}
+
+	if len(token) == 0 {
+		if !d.config.Webhook.Insecure {
+			return fmt.Errorf("the token of the http driver webhook is required, " +
+				"set the webhook insecure to accept the unauthenticated requests")
+		}
+		klog.Warningf("The http driver webhook accepts the unauthenticated requests")
+	}
+
+	certFile := d.credentialFile(d.config.Webhook.CertFile, tlsCertFile)
+	keyFile := d.credentialFile(d.config.Webhook.KeyFile, tlsKeyFile)
+
+	listener, err := net.Listen("tcp", d.config.Webhook.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s, %v", d.config.Webhook.Address, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(webhookPath, &webhookHandler{driver: d, prefix: webhookPath, token: token})
+	server := &http.Server{
+		Addr:              d.config.Webhook.Address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	d.server = server
+
+	go func() {
+		var err error
+		if len(certFile) != 0 && len(keyFile) != 0 {
+			klog.Infof("Start the http driver webhook on %s%s with TLS", d.config.Webhook.Address, webhookPath)
+			err = server.ServeTLS(listener, certFile, keyFile)
+		} else {
+			klog.Infof("Start the http driver webhook on %s%s", d.config.Webhook.Address, webhookPath)
+			err = server.Serve(listener)
+		}
+		if err != nil && err != http.ErrServerClosed {
+			klog.Errorf("failed to serve the http driver webhook, %v", err)
+		}
+	}()
+
+	return nil
+}
+
+func (d *HTTPDriver) Stop(ctx context.Context) {
+	d.poller.Stop()
+
+	if d.server != nil {
+		if err := d.server.Shutdown(ctx); err != nil {
+			klog.Errorf("failed to stop the http driver webhook, %v", err)
+		}
+		d.server = nil
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	for name, device := range d.devices {
+		device.client.CloseIdleConnections()
+		d.states.Remove(name)
+	}
+	d.devices = make(map[string]*httpDevice)
+}
+
+func (d *HTTPDriver) AddDevice(config v1alpha1.DeviceConfig) error {
+	d.Lock()
+	last, ok := d.devices[config.Name]
+	d.Unlock()
+	if ok && equality.Semantic.DeepEqual(last.deviceConfig, config) {
+		klog.Infof("The device %s already exists", config.Name)
+		return nil
+	}
+
+	protocolConfig, err := d.toProtocolConfig(config)
+	if err != nil {
+		return err
+	}
+
+	interval, err := time.ParseDuration(protocolConfig.PollInterval)
+	if err != nil {
+		return fmt.Errorf("invalid poll interval %s of device %s, %v", protocolConfig.PollInterval, config.Name, err)
+	}
+
+	timeout, err := time.ParseDuration(protocolConfig.Timeout)
+	if err != nil {
+		return fmt.Errorf("invalid timeout %s of device %s, %v", protocolConfig.Timeout, config.Name, err)
+	}
+
+	// stop polling the last device before it is replaced
+	d.poller.RemoveDevice(config.Name)
+
+	d.Lock()
+	d.devices[config.Name] = &httpDevice{
+		deviceConfig: config,
+		protocol:     protocolConfig,
+		client:       &http.Client{Timeout: timeout},
+	}
+	d.Unlock()
+
+	if len(protocolConfig.URL) == 0 {
+		klog.Infof("The device %s has no url to poll, receive its data from the webhook", config.Name)
+		return nil
+	}
+
+	if err := d.poller.AddDevice(config, interval); err != nil {
+		_ = d.RemoveDevice(config.Name)
+		return err
+	}
+
+	return nil
+}
+
+func (d *HTTPDriver) RemoveDevice(deviceName string) error {
+	d.Lock()
+	_, ok := d.devices[deviceName]
+	delete(d.devices, deviceName)
+	d.Unlock()
+	if !ok {
+		klog.Infof("The device %s is removed", deviceName)
+		return nil
+	}
+
+	klog.Infof("Remove the device %s", deviceName)
+	d.poller.RemoveDevice(deviceName)
+	d.states.Remove(deviceName)
+	return nil
+}
+
+func (d *HTTPDriver) GetDeviceState(deviceName string) *util.DeviceState {
+	return d.states.Get(deviceName)
+}
+
+// RunCommand sends the command values to the device with the command request of the device
+func (d *HTTPDriver) RunCommand(command util.Command) error {
+	device := d.getDevice(command.DeviceName)
+	if device == nil {
+		return fmt.Errorf("the device %s does not exist", command.DeviceName)
+	}
+
+	if device.protocol.Command == nil {
+		return fmt.Errorf("the command request of device %s is not set", command.DeviceName)
+	}
+
+	requests, err := util.ToWriteRequests(device.deviceConfig, command)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]interface{}{}
+	for _, writeRequest := range requests {
+		values[writeRequest.Resource.Name] = writeRequest.Value
+	}
+
+	body, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	url := strings.NewReplacer(
+		DevicePlaceholder, command.DeviceName,
+		CommandPlaceholder, command.DeviceCommand,
+	).Replace(device.protocol.Command.URL)
+
+	req, err := http.NewRequest(device.protocol.Command.Method, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create the command request of device %s, %v", command.DeviceName, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, val := range device.protocol.Command.Headers {
+		req.Header.Set(key, val)
+	}
+
+	if _, err := do(device.client, req); err != nil {
+		return fmt.Errorf("failed to run the command %s of device %s, %v", command.DeviceCommand, command.DeviceName, err)
+	}
+
+	return nil
+}
+
+// read polls the device url and extracts the readings of the resources from the json response
+func (d *HTTPDriver) read(ctx context.Context, device v1alpha1.DeviceConfig,
+	resources []v1alpha1.DeviceResource) (map[string]interface{}, error) {
+	current := d.getDevice(device.Name)
+	if current == nil {
+		return nil, fmt.Errorf("the device %s does not exist", device.Name)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, current.protocol.Method, current.protocol.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for key, val := range current.protocol.Headers {
+		req.Header.Set(key, val)
+	}
+
+	body, err := do(current.client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(resources, body)
+}
+
+func (d *HTTPDriver) publish(deviceName string, res v1alpha1.DeviceResource, data interface{}) {
+	result, err := util.NewResult(res, data)
+	if err != nil {
+		klog.Errorf("The device %s attribute %s  is unsupported, %v", deviceName, res.Name, err)
+		d.states.RecordError(deviceName, err)
+		return
+	}
+
+	d.states.RecordReading(deviceName)
+
+	for _, msgBus := range d.msgBuses {
+		if err := msgBus.ReceiveData(deviceName, *result); err != nil {
+			klog.Errorf("failed to publish the data of device %s, %v", deviceName, err)
+		}
+	}
+}
+
+func (d *HTTPDriver) getDevice(deviceName string) *httpDevice {
+	d.Lock()
+	defer d.Unlock()
+
+	return d.devices[deviceName]
+}
+
+func (d *HTTPDriver) toProtocolConfig(config v1alpha1.DeviceConfig) (*ProtocolConfig, error) {
+	protocolConfig := &ProtocolConfig{}
+	if err := util.ToConfigObj(config.ProtocolProperties.Data, protocolConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse the http protocol properties of device %s, %v", config.Name, err)
+	}
+
+	if len(protocolConfig.Method) == 0 {
+		protocolConfig.Method = defaultMethod
+	}
+
+	if len(protocolConfig.PollInterval) == 0 {
+		protocolConfig.PollInterval = d.config.PollInterval
+	}
+
+	if len(protocolConfig.Timeout) == 0 {
+		protocolConfig.Timeout = d.config.Timeout
+	}
+
+	if protocolConfig.Command != nil {
+		if len(protocolConfig.Command.URL) == 0 {
+			return nil, fmt.Errorf("the command url of device %s is not set", config.Name)
+		}
+
+		if len(protocolConfig.Command.Method) == 0 {
+			protocolConfig.Command.Method = defaultCommandMethod
+		}
+	}
+
+	return protocolConfig, nil
+}
+
+// getToken returns the webhook token, it is read from the token file in the credential dir if it is not set
+func (d *HTTPDriver) getToken() (string, error) {
+	if len(d.config.Webhook.Token) != 0 {
+		return d.config.Webhook.Token, nil
+	}
+
+	if len(d.config.CredentialDir) == 0 {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(path.Join(d.config.CredentialDir, tokenFile))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read the %s from %s, %v", tokenFile, d.config.CredentialDir, err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// credentialFile returns the file if it is set, otherwise the file in the credential dir is returned if it exists
+func (d *HTTPDriver) credentialFile(file, name string) string {
+	if len(file) != 0 || len(d.config.CredentialDir) == 0 {
+		return file
+	}
+
+	if _, err := os.Stat(path.Join(d.config.CredentialDir, name)); err != nil {
+		return ""
+	}
+
+	return path.Join(d.config.CredentialDir, name)
+}
+
+// do sends the request and returns the response body, an error is returned if the status is not 2xx
+func do(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %s, %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return body, nil
+}
+
+// decode extracts the readings of the resources from the json payload, the resources that are not found in the
+// payload are skipped and their errors are returned together
+func decode(resources []v1alpha1.DeviceResource, payload []byte) (map[string]interface{}, error) {
+	var data interface{}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, fmt.Errorf("the payload is not a valid json, %v", err)
+	}
+
+	readings := map[string]interface{}{}
+	missing := []string{}
+	for _, res := range resources {
+		var val interface{}
+		if jsonPath, ok := res.Attributes.Data[JSONPathAttribute]; ok {
+			found, err := util.LookupJSONPath(data, fmt.Sprintf("%v", jsonPath))
+			if err != nil {
+				return nil, fmt.Errorf("failed to find the value of resource %s, %v", res.Name, err)
+			}
+			val = found
+		} else if obj, ok := data.(map[string]interface{}); ok {
+			val = obj[res.Name]
+		}
+
+		if val == nil {
+			missing = append(missing, res.Name)
+			continue
+		}
+
+		readings[res.Name] = val
+	}
+
+	if len(missing) != 0 {
+		return readings, fmt.Errorf("the values of resources %s are not found", strings.Join(missing, ", "))
+	}
+
+	return readings, nil
+}
@@ -0,0 +1,367 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	certutil "k8s.io/client-go/util/cert"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/messagebuses"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+	testingutil "open-cluster-management-io/addon-contrib/device-addon/pkg/device/util/testing"
+)
+
+// newTestDevice returns a sensor whose temperature is in the nested object of the payload
+func newTestDevice(protocolProperties map[string]interface{}) v1alpha1.DeviceConfig {
+	return testingutil.NewDevice("sensor", "http", protocolProperties,
+		testingutil.NewResource("temperature", "r", util.ValueTypeFloat64,
+			map[string]interface{}{JSONPathAttribute: "sensors.temperature"}),
+		testingutil.NewResource("setpoint", "rw", util.ValueTypeFloat64, nil),
+		testingutil.NewResource("reset", "w", util.ValueTypeBool, nil),
+	)
+}
+
+func TestPollAndRunCommand(t *testing.T) {
+	var lock sync.Mutex
+	commands := []map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/status":
+			if r.Header.Get("Authorization") != "Bearer device-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"sensors": {"temperature": 21.5}, "setpoint": 18, "reset": false}`)
+		case r.Method == http.MethodPut && r.URL.Path == "/api/sensor/setpoint":
+			command := map[string]interface{}{}
+			if err := json.NewDecoder(r.Body).Decode(&command); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			lock.Lock()
+			commands = append(commands, command)
+			lock.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	msgBus := testingutil.NewFakeMsgBus()
+	driver, err := NewHTTPDriver(map[string]interface{}{"pollInterval": "20ms"}, []messagebuses.MessageBus{msgBus})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := driver.Start(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	defer driver.Stop(context.TODO())
+
+	if err := driver.AddDevice(newTestDevice(map[string]interface{}{
+		"url":     server.URL + "/status",
+		"headers": map[string]interface{}{"Authorization": "Bearer device-token"},
+		"command": map[string]interface{}{"url": server.URL + "/api/{device}/{command}", "method": "PUT"},
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	readings := testingutil.WaitForReadings(t, msgBus, "temperature", "setpoint")
+	if readings["temperature"] != 21.5 || readings["setpoint"] != float64(18) {
+		t.Errorf("unexpected readings %v", readings)
+	}
+
+	if err := driver.RunCommand(util.Command{
+		DeviceName:    "sensor",
+		DeviceCommand: "setpoint",
+		Attributes:    util.Attributes{"setpoint": 20},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if len(commands) != 1 || commands[0]["setpoint"] != float64(20) {
+		t.Errorf("unexpected commands %v", commands)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	msgBus := testingutil.NewFakeMsgBus()
+	driver, err := NewHTTPDriver(map[string]interface{}{}, []messagebuses.MessageBus{msgBus})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := driver.AddDevice(newTestDevice(map[string]interface{}{})); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(&webhookHandler{driver: driver, prefix: "/webhook/", token: "token"})
+	defer server.Close()
+
+	cases := []struct {
+		name           string
+		method         string
+		path           string
+		token          string
+		payload        string
+		expectedStatus int
+	}{
+		{
+			name:           "not allowed method",
+			method:         http.MethodGet,
+			path:           "/webhook/sensor",
+			token:          "token",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "unauthorized",
+			method:         http.MethodPost,
+			path:           "/webhook/sensor",
+			token:          "wrong",
+			payload:        `{"setpoint": 18}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "unknown device",
+			method:         http.MethodPost,
+			path:           "/webhook/unknown",
+			token:          "token",
+			payload:        `{"setpoint": 18}`,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid payload",
+			method:         http.MethodPost,
+			path:           "/webhook/sensor",
+			token:          "token",
+			payload:        `setpoint=18`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "partial payload",
+			method:         http.MethodPost,
+			path:           "/webhook/sensor",
+			token:          "token",
+			payload:        `{"setpoint": 18, "reset": true}`,
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req, err := http.NewRequest(c.method, server.URL+c.path, strings.NewReader(c.payload))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Authorization", "Bearer "+c.token)
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != c.expectedStatus {
+				t.Errorf("expected status %d, but got %d", c.expectedStatus, resp.StatusCode)
+			}
+		})
+	}
+
+	// only the readable resources of the partial payload are published
+	readings := testingutil.WaitForReadings(t, msgBus, "setpoint")
+	if readings["setpoint"] != float64(18) {
+		t.Errorf("unexpected readings %v", readings)
+	}
+	select {
+	case result := <-msgBus.Results:
+		t.Errorf("unexpected reading %v", result)
+	default:
+	}
+}
+
+func TestStartWebhook(t *testing.T) {
+	certData, keyData, err := certutil.GenerateSelfSignedCertKey("localhost", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credentialDir := t.TempDir()
+	for name, data := range map[string][]byte{tokenFile: []byte("token\n"), tlsCertFile: certData, tlsKeyFile: keyData} {
+		if err := os.WriteFile(path.Join(credentialDir, name), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		name        string
+		config      map[string]interface{}
+		expectedErr bool
+		tls         bool
+	}{
+		{
+			name:        "no token",
+			config:      map[string]interface{}{"webhook": map[string]interface{}{}},
+			expectedErr: true,
+		},
+		{
+			name:   "insecure",
+			config: map[string]interface{}{"webhook": map[string]interface{}{"insecure": true}},
+		},
+		{
+			name: "token and certificate from the credential dir",
+			config: map[string]interface{}{
+				"webhook":       map[string]interface{}{},
+				"credentialDir": credentialDir,
+			},
+			tls: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			address := listener.Addr().String()
+			listener.Close()
+			c.config["webhook"].(map[string]interface{})["address"] = address
+
+			driver, err := NewHTTPDriver(c.config, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = driver.Start(context.TODO())
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected an error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer driver.Stop(context.TODO())
+
+			if err := driver.AddDevice(newTestDevice(map[string]interface{}{})); err != nil {
+				t.Fatal(err)
+			}
+
+			scheme := "http"
+			client := http.DefaultClient
+			if c.tls {
+				scheme = "https"
+				tlsConfig, err := util.NewTLSConfig(path.Join(credentialDir, tlsCertFile), "", "", false)
+				if err != nil {
+					t.Fatal(err)
+				}
+				tlsConfig.ServerName = "localhost"
+				client = &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+			}
+
+			req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s://%s/webhook/sensor", scheme, address),
+				strings.NewReader(`{"setpoint": 18}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Authorization", "Bearer token")
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != http.StatusNoContent {
+				t.Errorf("expected status %d, but got %d, %s", http.StatusNoContent, resp.StatusCode, string(body))
+			}
+		})
+	}
+}
+
+func TestNewHTTPDriver(t *testing.T) {
+	cases := []struct {
+		name        string
+		config      map[string]interface{}
+		expectedErr bool
+	}{
+		{
+			name:   "the default config",
+			config: map[string]interface{}{},
+		},
+		{
+			name:        "the config is invalid",
+			config:      map[string]interface{}{"pollInterval": []string{"1s"}},
+			expectedErr: true,
+		},
+		{
+			name:        "the max backoff is invalid",
+			config:      map[string]interface{}{"maxBackoff": "1min"},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			driver, err := NewHTTPDriver(c.config, nil)
+			if c.expectedErr {
+				if err == nil || driver != nil {
+					t.Errorf("expected an error, but got the driver %v", driver)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if driver.config.PollInterval != defaultPollInterval || driver.config.Timeout != defaultTimeout {
+				t.Errorf("expected the default config, but got %v", driver.config)
+			}
+		})
+	}
+}
+
+func TestStop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"temperature": 21.5, "setpoint": 18}`))
+	}))
+	defer server.Close()
+
+	msgBus := testingutil.NewFakeMsgBus()
+	driver, err := NewHTTPDriver(map[string]interface{}{"pollInterval": "20ms"}, []messagebuses.MessageBus{msgBus})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := driver.AddDevice(newTestDevice(map[string]interface{}{"url": server.URL})); err != nil {
+		t.Fatal(err)
+	}
+	testingutil.WaitForReadings(t, msgBus, "temperature")
+
+	driver.Stop(context.TODO())
+
+	if len(driver.devices) != 0 {
+		t.Errorf("expected the devices to be cleared, but got %d", len(driver.devices))
+	}
+
+	if state := driver.GetDeviceState("sensor"); state != nil {
+		t.Errorf("expected the device state to be removed, but got %v", state)
+	}
+
+	// the device can be added again after the driver is stopped
+	if err := driver.AddDevice(newTestDevice(map[string]interface{}{"url": server.URL})); err != nil {
+		t.Fatal(err)
+	}
+	driver.Stop(context.TODO())
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// ValidateConfig validates the http driver properties
+func ValidateConfig(properties map[string]interface{}, fldPath *field.Path) field.ErrorList {
+	config := &Config{}
+	if err := util.ToConfigObj(properties, config); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, field.OmitValueType{},
+			fmt.Sprintf("failed to parse the http driver config, %v", err))}
+	}
+
+	errs := field.ErrorList{}
+	errs = append(errs, validateDuration(config.PollInterval, fldPath.Child("pollInterval"))...)
+	errs = append(errs, validateDuration(config.Timeout, fldPath.Child("timeout"))...)
+	errs = append(errs, validateDuration(config.MaxBackoff, fldPath.Child("maxBackoff"))...)
+	if config.Jitter < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("jitter"), config.Jitter, "the jitter must not be negative"))
+	}
+
+	if config.Webhook != nil {
+		webhookPath := fldPath.Child("webhook")
+		if len(config.Webhook.Address) == 0 {
+			errs = append(errs, field.Required(webhookPath.Child("address"), "the listen address of the webhook is required"))
+		}
+
+		// the token may be read from the credential Secret or the credential dir
+		_, hasSecret := properties[credentialSecretProperty]
+		hasCredentials := hasSecret || len(config.CredentialDir) != 0
+		if !hasCredentials && len(config.Webhook.Token) == 0 && !config.Webhook.Insecure {
+			errs = append(errs, field.Required(webhookPath.Child("token"),
+				"the token of the webhook is required, set the insecure to accept the unauthenticated requests"))
+		}
+
+		if (len(config.Webhook.CertFile) == 0) != (len(config.Webhook.KeyFile) == 0) {
+			errs = append(errs, field.Invalid(webhookPath, field.OmitValueType{}, "the certFile and keyFile must be set together"))
+		}
+	}
+
+	return errs
+}
+
+// ValidateDevice validates the http protocol properties of a device
+func ValidateDevice(device v1alpha1.DeviceConfig, fldPath *field.Path) field.ErrorList {
+	propsPath := fldPath.Child("protocolProperties")
+	protocolConfig := &ProtocolConfig{}
+	if err := util.ToConfigObj(device.ProtocolProperties.Data, protocolConfig); err != nil {
+		return field.ErrorList{field.Invalid(propsPath, field.OmitValueType{},
+			fmt.Sprintf("failed to parse the http protocol properties, %v", err))}
+	}
+
+	errs := field.ErrorList{}
+	errs = append(errs, validateURL(protocolConfig.URL, propsPath.Child("url"))...)
+	errs = append(errs, validateDuration(protocolConfig.PollInterval, propsPath.Child("pollInterval"))...)
+	errs = append(errs, validateDuration(protocolConfig.Timeout, propsPath.Child("timeout"))...)
+
+	if protocolConfig.Command != nil {
+		commandPath := propsPath.Child("command")
+		if len(protocolConfig.Command.URL) == 0 {
+			errs = append(errs, field.Required(commandPath.Child("url"), "the url of the command requests is required"))
+		}
+		errs = append(errs, validateURL(protocolConfig.Command.URL, commandPath.Child("url"))...)
+	}
+
+	return errs
+}
+
+func validateURL(rawURL string, fldPath *field.Path) field.ErrorList {
+	if len(rawURL) == 0 {
+		return nil
+	}
+
+	if _, err := url.Parse(rawURL); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, rawURL, err.Error())}
+	}
+
+	return nil
+}
+
+func validateDuration(duration string, fldPath *field.Path) field.ErrorList {
+	if len(duration) == 0 {
+		return nil
+	}
+
+	if _, err := time.ParseDuration(duration); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, duration, err.Error())}
+	}
+
+	return nil
+}
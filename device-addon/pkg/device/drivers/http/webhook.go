@@ -0,0 +1,76 @@
+package http
+
+import (
+	"crypto/subtle"
+	"io"
+	"net/http"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// webhookHandler receives the json payloads that are posted to <prefix><device-name>, the readings of the device
+// resources are extracted from the payload with the same rules of the poll responses, the requests are not
+// authenticated if the token is empty
+type webhookHandler struct {
+	driver *HTTPDriver
+	prefix string
+	token  string
+}
+
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if len(h.token) != 0 {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	deviceName := strings.Trim(strings.TrimPrefix(r.URL.Path, h.prefix), "/")
+	device := h.driver.getDevice(deviceName)
+	if device == nil {
+		http.Error(w, "the device does not exist", http.StatusNotFound)
+		return
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resources := []v1alpha1.DeviceResource{}
+	for _, res := range device.deviceConfig.Profile.DeviceResources {
+		if !util.IsReadable(res.Properties.ReadWrite) {
+			continue
+		}
+		resources = append(resources, res)
+	}
+
+	// a device may push a part of its resources, so the missing resources are ignored
+	readings, err := decode(resources, payload)
+	if len(readings) == 0 && err != nil {
+		klog.Errorf("failed to decode the webhook payload of device %s, %v", deviceName, err)
+		h.driver.states.RecordError(deviceName, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.driver.states.SetConnected(deviceName, true)
+	for _, res := range resources {
+		if reading, ok := readings[res.Name]; ok {
+			h.driver.publish(deviceName, res, reading)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,151 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/spf13/cast"
+)
+
+// toQuantity returns the number of the coils or registers that need to be read for the attributes
+func toQuantity(attrs ResourceAttributes) (uint16, error) {
+	switch attrs.DataType {
+	case DataTypeBool:
+		return 1, nil
+	case DataTypeInt16, DataTypeUint16:
+		return 1, nil
+	case DataTypeInt32, DataTypeUint32, DataTypeFloat32:
+		return 2, nil
+	case DataTypeInt64, DataTypeUint64, DataTypeFloat64:
+		return 4, nil
+	default:
+		return 0, fmt.Errorf("unsupported data type %s", attrs.DataType)
+	}
+}
+
+// decode converts the raw data that is read from the device to a reading with the data type
+func decode(attrs ResourceAttributes, data []byte) (interface{}, error) {
+	if attrs.FunctionCode == ReadCoils || attrs.FunctionCode == ReadDiscreteInputs {
+		if len(data) < 1 {
+			return nil, fmt.Errorf("no data is read from address %d", attrs.Address)
+		}
+		return data[0]&0x01 == 0x01, nil
+	}
+
+	quantity, err := toQuantity(attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < int(quantity)*2 {
+		return nil, fmt.Errorf("expected %d bytes from address %d, but got %d", quantity*2, attrs.Address, len(data))
+	}
+
+	raw := swap(attrs, data[:quantity*2])
+
+	switch attrs.DataType {
+	case DataTypeBool:
+		return binary.BigEndian.Uint16(raw) != 0, nil
+	case DataTypeInt16:
+		return int16(binary.BigEndian.Uint16(raw)), nil
+	case DataTypeUint16:
+		return binary.BigEndian.Uint16(raw), nil
+	case DataTypeInt32:
+		return int32(binary.BigEndian.Uint32(raw)), nil
+	case DataTypeUint32:
+		return binary.BigEndian.Uint32(raw), nil
+	case DataTypeInt64:
+		return int64(binary.BigEndian.Uint64(raw)), nil
+	case DataTypeUint64:
+		return binary.BigEndian.Uint64(raw), nil
+	case DataTypeFloat32:
+		return math.Float32frombits(binary.BigEndian.Uint32(raw)), nil
+	case DataTypeFloat64:
+		return math.Float64frombits(binary.BigEndian.Uint64(raw)), nil
+	}
+
+	return nil, fmt.Errorf("unsupported data type %s", attrs.DataType)
+}
+
+// encode converts a value to the raw registers data with the data type
+func encode(attrs ResourceAttributes, value interface{}) ([]byte, error) {
+	quantity, err := toQuantity(attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, quantity*2)
+	switch attrs.DataType {
+	case DataTypeBool:
+		val, err := cast.ToBoolE(value)
+		if err != nil {
+			return nil, err
+		}
+		if val {
+			binary.BigEndian.PutUint16(raw, 1)
+		}
+	case DataTypeInt16, DataTypeUint16:
+		val, err := cast.ToInt64E(value)
+		if err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint16(raw, uint16(val))
+	case DataTypeInt32, DataTypeUint32:
+		val, err := cast.ToInt64E(value)
+		if err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint32(raw, uint32(val))
+	case DataTypeInt64:
+		val, err := cast.ToInt64E(value)
+		if err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint64(raw, uint64(val))
+	case DataTypeUint64:
+		val, err := cast.ToUint64E(value)
+		if err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint64(raw, val)
+	case DataTypeFloat32:
+		val, err := cast.ToFloat32E(value)
+		if err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint32(raw, math.Float32bits(val))
+	case DataTypeFloat64:
+		val, err := cast.ToFloat64E(value)
+		if err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint64(raw, math.Float64bits(val))
+	}
+
+	// the swap is symmetric, so it also converts the big-endian data to the device layout
+	return swap(attrs, raw), nil
+}
+
+// swap reorders the registers data to big-endian with the byte swap and word swap attributes
+func swap(attrs ResourceAttributes, data []byte) []byte {
+	raw := make([]byte, len(data))
+	copy(raw, data)
+
+	if attrs.ByteSwap {
+		for i := 0; i+1 < len(raw); i += 2 {
+			raw[i], raw[i+1] = raw[i+1], raw[i]
+		}
+	}
+
+	if attrs.WordSwap {
+		words := len(raw) / 2
+		for i := 0; i < words/2; i++ {
+			j := words - 1 - i
+			raw[i*2], raw[j*2] = raw[j*2], raw[i*2]
+			raw[i*2+1], raw[j*2+1] = raw[j*2+1], raw[i*2+1]
+		}
+	}
+
+	return raw
+}
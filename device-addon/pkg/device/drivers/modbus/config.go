@@ -0,0 +1,65 @@
+package modbus
+
+const (
+	ProtocolTCP = "tcp"
+	ProtocolRTU = "rtu"
+)
+
+// Modbus function codes that are supported to read the data from devices
+const (
+	ReadCoils            uint8 = 1
+	ReadDiscreteInputs   uint8 = 2
+	ReadHoldingRegisters uint8 = 3
+	ReadInputRegisters   uint8 = 4
+)
+
+// Data layouts of the device resource raw data
+const (
+	DataTypeBool    = "Bool"
+	DataTypeInt16   = "Int16"
+	DataTypeUint16  = "Uint16"
+	DataTypeInt32   = "Int32"
+	DataTypeUint32  = "Uint32"
+	DataTypeInt64   = "Int64"
+	DataTypeUint64  = "Uint64"
+	DataTypeFloat32 = "Float32"
+	DataTypeFloat64 = "Float64"
+)
+
+const (
+	defaultPollInterval = "5s"
+	defaultTimeout      = "5s"
+)
+
+// Config is the modbus driver configuration, it provides the default values for the devices
+type Config struct {
+	PollInterval string  `json:"pollInterval"` // Interval to poll the device resources, e.g. 1s
+	Timeout      string  `json:"timeout"`      // Timeout of one modbus request, e.g. 5s
+	Jitter       float64 `json:"jitter"`       // Jitter: the factor of the poll interval that is randomly added to each poll, e.g. 0.1
+	MaxBackoff   string  `json:"maxBackoff"`   // MaxBackoff: the maximum delay of the next poll if the device is unreachable, e.g. 5m
+}
+
+// ProtocolConfig is the modbus device protocol properties
+type ProtocolConfig struct {
+	Protocol string `json:"protocol"` // Protocol: tcp or rtu
+	Address  string `json:"address"`  // Address: host:port for tcp, serial device path for rtu, e.g. /dev/ttyS0
+	UnitID   uint8  `json:"unitId"`   // UnitID: the slave id of the device
+
+	// The serial port properties, they are only used by rtu
+	BaudRate int    `json:"baudRate"`
+	DataBits int    `json:"dataBits"`
+	StopBits int    `json:"stopBits"`
+	Parity   string `json:"parity"` // Parity: N - None, E - Even, O - Odd
+
+	PollInterval string `json:"pollInterval"`
+	Timeout      string `json:"timeout"`
+}
+
+// ResourceAttributes is the modbus device resource attributes
+type ResourceAttributes struct {
+	FunctionCode uint8  `json:"functionCode"` // FunctionCode: 1 coils, 2 discrete inputs, 3 holding registers, 4 input registers
+	Address      uint16 `json:"address"`      // Address: the starting address of the coils or registers
+	DataType     string `json:"dataType"`     // DataType: the layout of the raw data, e.g. Int16, Uint32, Float32
+	ByteSwap     bool   `json:"byteSwap"`     // ByteSwap: swap the bytes in each register
+	WordSwap     bool   `json:"wordSwap"`     // WordSwap: swap the registers order
+}
@@ -0,0 +1,386 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goburrow/modbus"
+	"github.com/spf13/cast"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/messagebuses"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/poller"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+type clientHandler interface {
+	modbus.ClientHandler
+	io.Closer
+}
+
+type request struct {
+	res      v1alpha1.DeviceResource
+	attrs    ResourceAttributes
+	quantity uint16
+}
+
+type modbusDevice struct {
+	deviceConfig v1alpha1.DeviceConfig
+	handler      clientHandler
+	client       modbus.Client
+}
+
+type ModbusDriver struct {
+	sync.Mutex
+	config  *Config
+	devices map[string]modbusDevice
+	states  *util.DeviceStates
+	poller  *poller.Poller
+}
+
+func NewModbusDriver(driverConfig util.ConfigProperties, msgBuses []messagebuses.MessageBus) (*ModbusDriver, error) {
+	var config = &Config{}
+	if err := util.ToConfigObj(driverConfig, config); err != nil {
+		return nil, fmt.Errorf("failed to parse modbus driver config, %v", err)
+	}
+
+	if len(config.PollInterval) == 0 {
+		config.PollInterval = defaultPollInterval
+	}
+
+	if len(config.Timeout) == 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	pollerConfig := poller.Config{Jitter: config.Jitter}
+	if len(config.MaxBackoff) != 0 {
+		maxBackoff, err := time.ParseDuration(config.MaxBackoff)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max backoff %s of modbus driver, %v", config.MaxBackoff, err)
+		}
+		pollerConfig.MaxBackoff = maxBackoff
+	}
+
+	d := &ModbusDriver{
+		devices: make(map[string]modbusDevice),
+		config:  config,
+		states:  util.NewDeviceStates(),
+	}
+	d.poller = poller.NewPoller(pollerConfig, d.read, msgBuses, d.states)
+	return d, nil
+}
+
+func (d *ModbusDriver) GetType() string {
+	return "modbus"
+}
+
+func (d *ModbusDriver) Start(ctx context.Context) error {
+	//do nothing
+	return nil
+}
+
+func (d *ModbusDriver) Stop(ctx context.Context) {
+	d.poller.Stop()
+
+	d.Lock()
+	defer d.Unlock()
+
+	for name, device := range d.devices {
+		device.handler.Close()
+		d.states.Remove(name)
+	}
+	d.devices = make(map[string]modbusDevice)
+}
+
+func (d *ModbusDriver) AddDevice(config v1alpha1.DeviceConfig) error {
+	d.Lock()
+	last, ok := d.devices[config.Name]
+	d.Unlock()
+	if ok {
+		if equality.Semantic.DeepEqual(last.deviceConfig, config) {
+			klog.Infof("The device %s already exists", config.Name)
+			return nil
+		}
+
+		klog.Infof("Restart the device %s", config.Name)
+		if err := d.RemoveDevice(config.Name); err != nil {
+			return err
+		}
+	}
+
+	protocolConfig, err := d.toProtocolConfig(config)
+	if err != nil {
+		return err
+	}
+
+	interval, err := time.ParseDuration(protocolConfig.PollInterval)
+	if err != nil {
+		return fmt.Errorf("invalid poll interval %s of device %s, %v", protocolConfig.PollInterval, config.Name, err)
+	}
+
+	for _, res := range config.Profile.DeviceResources {
+		if _, err := toRequest(res); err != nil {
+			return fmt.Errorf("invalid resource %s of device %s, %v", res.Name, config.Name, err)
+		}
+	}
+
+	handler, err := newClientHandler(protocolConfig)
+	if err != nil {
+		return err
+	}
+
+	d.Lock()
+	d.devices[config.Name] = modbusDevice{
+		deviceConfig: config,
+		handler:      handler,
+		client:       modbus.NewClient(handler),
+	}
+	d.Unlock()
+
+	if err := d.poller.AddDevice(config, interval); err != nil {
+		_ = d.RemoveDevice(config.Name)
+		return err
+	}
+
+	return nil
+}
+
+func (d *ModbusDriver) RemoveDevice(deviceName string) error {
+	d.Lock()
+	current, ok := d.devices[deviceName]
+	delete(d.devices, deviceName)
+	d.Unlock()
+	if !ok {
+		klog.Infof("The device %s is removed", deviceName)
+		return nil
+	}
+
+	klog.Infof("Remove the device %s", deviceName)
+	// the device is not read once the poller is stopped, so its handler can be closed safely
+	d.poller.RemoveDevice(deviceName)
+	current.handler.Close()
+	d.states.Remove(deviceName)
+	return nil
+}
+
+func (d *ModbusDriver) GetDeviceState(deviceName string) *util.DeviceState {
+	return d.states.Get(deviceName)
+}
+
+// RunCommand writes the command values to the coils or holding registers of the device
+func (d *ModbusDriver) RunCommand(command util.Command) error {
+	d.Lock()
+	device, ok := d.devices[command.DeviceName]
+	d.Unlock()
+	if !ok {
+		return fmt.Errorf("the device %s does not exist", command.DeviceName)
+	}
+
+	requests, err := util.ToWriteRequests(device.deviceConfig, command)
+	if err != nil {
+		return err
+	}
+
+	for _, writeRequest := range requests {
+		req, err := toRequest(writeRequest.Resource)
+		if err != nil {
+			return fmt.Errorf("invalid resource %s of device %s, %v", writeRequest.Resource.Name, command.DeviceName, err)
+		}
+
+		if err := write(device.client, *req, writeRequest.Value); err != nil {
+			return fmt.Errorf("failed to write the resource %s of device %s, %v",
+				writeRequest.Resource.Name, command.DeviceName, err)
+		}
+	}
+
+	return nil
+}
+
+// read reads the resources of the device one by one, the resources that fail to be read are skipped
+func (d *ModbusDriver) read(ctx context.Context, device v1alpha1.DeviceConfig,
+	resources []v1alpha1.DeviceResource) (map[string]interface{}, error) {
+	d.Lock()
+	current, ok := d.devices[device.Name]
+	d.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("the device %s does not exist", device.Name)
+	}
+
+	readings := map[string]interface{}{}
+	errs := []string{}
+	for _, res := range resources {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		req, err := toRequest(res)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid resource %s, %v", res.Name, err))
+			continue
+		}
+
+		data, err := read(current.client, *req)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("failed to read the attribute %s, %v", res.Name, err))
+			continue
+		}
+
+		reading, err := decode(req.attrs, data)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("failed to decode the attribute %s, %v", res.Name, err))
+			continue
+		}
+
+		readings[res.Name] = reading
+	}
+
+	if len(errs) != 0 {
+		return readings, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	return readings, nil
+}
+
+func (d *ModbusDriver) toProtocolConfig(config v1alpha1.DeviceConfig) (*ProtocolConfig, error) {
+	protocolConfig := &ProtocolConfig{}
+	if err := util.ToConfigObj(config.ProtocolProperties.Data, protocolConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse the modbus protocol properties of device %s, %v", config.Name, err)
+	}
+
+	if len(protocolConfig.Address) == 0 {
+		return nil, fmt.Errorf("address not found in the modbus protocol properties, %v", config.ProtocolProperties.Data)
+	}
+
+	if len(protocolConfig.Protocol) == 0 {
+		protocolConfig.Protocol = ProtocolTCP
+	}
+
+	if len(protocolConfig.PollInterval) == 0 {
+		protocolConfig.PollInterval = d.config.PollInterval
+	}
+
+	if len(protocolConfig.Timeout) == 0 {
+		protocolConfig.Timeout = d.config.Timeout
+	}
+
+	return protocolConfig, nil
+}
+
+func newClientHandler(config *ProtocolConfig) (clientHandler, error) {
+	timeout, err := time.ParseDuration(config.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeout %s, %v", config.Timeout, err)
+	}
+
+	switch config.Protocol {
+	case ProtocolTCP:
+		handler := modbus.NewTCPClientHandler(config.Address)
+		handler.SlaveId = config.UnitID
+		handler.Timeout = timeout
+		return handler, nil
+	case ProtocolRTU:
+		handler := modbus.NewRTUClientHandler(config.Address)
+		handler.SlaveId = config.UnitID
+		handler.Timeout = timeout
+		if config.BaudRate != 0 {
+			handler.BaudRate = config.BaudRate
+		}
+		if config.DataBits != 0 {
+			handler.DataBits = config.DataBits
+		}
+		if config.StopBits != 0 {
+			handler.StopBits = config.StopBits
+		}
+		if len(config.Parity) != 0 {
+			handler.Parity = config.Parity
+		}
+		return handler, nil
+	default:
+		return nil, fmt.Errorf("unsupported modbus protocol %s", config.Protocol)
+	}
+}
+
+func toRequest(res v1alpha1.DeviceResource) (*request, error) {
+	attrs := ResourceAttributes{}
+	if err := util.ToConfigObj(res.Attributes.Data, &attrs); err != nil {
+		return nil, err
+	}
+
+	switch attrs.FunctionCode {
+	case ReadCoils, ReadDiscreteInputs:
+		attrs.DataType = DataTypeBool
+	case ReadHoldingRegisters, ReadInputRegisters:
+		if len(attrs.DataType) == 0 {
+			attrs.DataType = DataTypeUint16
+		}
+	default:
+		return nil, fmt.Errorf("unsupported function code %d", attrs.FunctionCode)
+	}
+
+	quantity, err := toQuantity(attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &request{
+		res:      res,
+		attrs:    attrs,
+		quantity: quantity,
+	}, nil
+}
+
+func read(client modbus.Client, req request) ([]byte, error) {
+	switch req.attrs.FunctionCode {
+	case ReadCoils:
+		return client.ReadCoils(req.attrs.Address, req.quantity)
+	case ReadDiscreteInputs:
+		return client.ReadDiscreteInputs(req.attrs.Address, req.quantity)
+	case ReadHoldingRegisters:
+		return client.ReadHoldingRegisters(req.attrs.Address, req.quantity)
+	case ReadInputRegisters:
+		return client.ReadInputRegisters(req.attrs.Address, req.quantity)
+	}
+
+	return nil, fmt.Errorf("unsupported function code %d", req.attrs.FunctionCode)
+}
+
+func write(client modbus.Client, req request, value interface{}) error {
+	switch req.attrs.FunctionCode {
+	case ReadCoils:
+		val, err := cast.ToBoolE(value)
+		if err != nil {
+			return err
+		}
+
+		coil := uint16(0x0000)
+		if val {
+			coil = 0xFF00
+		}
+
+		_, err = client.WriteSingleCoil(req.attrs.Address, coil)
+		return err
+	case ReadHoldingRegisters:
+		data, err := encode(req.attrs, value)
+		if err != nil {
+			return err
+		}
+
+		if req.quantity == 1 {
+			_, err = client.WriteSingleRegister(req.attrs.Address, binary.BigEndian.Uint16(data))
+			return err
+		}
+
+		_, err = client.WriteMultipleRegisters(req.attrs.Address, req.quantity, data)
+		return err
+	}
+
+	return fmt.Errorf("the function code %d is read only", req.attrs.FunctionCode)
+}
@@ -0,0 +1,297 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"net"
+	"sync"
+	"testing"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/messagebuses"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+	testingutil "open-cluster-management-io/addon-contrib/device-addon/pkg/device/util/testing"
+)
+
+// simulator is a Modbus TCP server that serves the coils and holding registers from memory
+type simulator struct {
+	sync.Mutex
+	listener  net.Listener
+	coils     map[uint16]bool
+	registers map[uint16]uint16
+}
+
+func newSimulator(t *testing.T) *simulator {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &simulator{
+		listener:  listener,
+		coils:     map[uint16]bool{},
+		registers: map[uint16]uint16{},
+	}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *simulator) address() string {
+	return s.listener.Addr().String()
+}
+
+func (s *simulator) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *simulator) handle(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		// the MBAP header: transaction id, protocol id, length and unit id
+		header := make([]byte, 7)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+
+		pdu := make([]byte, binary.BigEndian.Uint16(header[4:6])-1)
+		if _, err := io.ReadFull(conn, pdu); err != nil {
+			return
+		}
+
+		resp := s.process(pdu)
+		frame := make([]byte, 7, 7+len(resp))
+		copy(frame, header)
+		binary.BigEndian.PutUint16(frame[4:6], uint16(len(resp)+1))
+		if _, err := conn.Write(append(frame, resp...)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *simulator) process(pdu []byte) []byte {
+	s.Lock()
+	defer s.Unlock()
+
+	functionCode := pdu[0]
+	address := binary.BigEndian.Uint16(pdu[1:3])
+	switch functionCode {
+	case 1, 2:
+		quantity := binary.BigEndian.Uint16(pdu[3:5])
+		data := make([]byte, (quantity+7)/8)
+		for i := uint16(0); i < quantity; i++ {
+			if s.coils[address+i] {
+				data[i/8] |= 1 << (i % 8)
+			}
+		}
+		return append([]byte{functionCode, byte(len(data))}, data...)
+	case 3, 4:
+		quantity := binary.BigEndian.Uint16(pdu[3:5])
+		data := make([]byte, quantity*2)
+		for i := uint16(0); i < quantity; i++ {
+			binary.BigEndian.PutUint16(data[i*2:], s.registers[address+i])
+		}
+		return append([]byte{functionCode, byte(len(data))}, data...)
+	case 5:
+		s.coils[address] = binary.BigEndian.Uint16(pdu[3:5]) == 0xFF00
+		return pdu[:5]
+	case 6:
+		s.registers[address] = binary.BigEndian.Uint16(pdu[3:5])
+		return pdu[:5]
+	case 16:
+		quantity := binary.BigEndian.Uint16(pdu[3:5])
+		for i := uint16(0); i < quantity; i++ {
+			s.registers[address+i] = binary.BigEndian.Uint16(pdu[6+i*2:])
+		}
+		return pdu[:5]
+	}
+
+	// illegal function
+	return []byte{functionCode | 0x80, 0x01}
+}
+
+func (s *simulator) coil(address uint16) bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.coils[address]
+}
+
+func (s *simulator) register(address uint16) uint16 {
+	s.Lock()
+	defer s.Unlock()
+	return s.registers[address]
+}
+
+// newTestDevice returns a plc that has a coil and two holding registers on the address
+func newTestDevice(address string) v1alpha1.DeviceConfig {
+	return testingutil.NewDevice("plc", "modbus",
+		map[string]interface{}{"address": address, "unitId": 1, "pollInterval": "50ms"},
+		testingutil.NewResource("running", "RW", util.ValueTypeBool,
+			map[string]interface{}{"functionCode": 1, "address": 0}),
+		testingutil.NewResource("temperature", "R", util.ValueTypeInt16,
+			map[string]interface{}{"functionCode": 3, "address": 0, "dataType": DataTypeInt16}),
+		testingutil.NewResource("setpoint", "RW", util.ValueTypeFloat32,
+			map[string]interface{}{"functionCode": 3, "address": 1, "dataType": DataTypeFloat32}),
+	)
+}
+
+func TestReadDevice(t *testing.T) {
+	sim := newSimulator(t)
+	sim.coils[0] = true
+	sim.registers[0] = uint16(0xFFFF - 4) // -5
+	setpoint := math.Float32bits(21.5)
+	sim.registers[1] = uint16(setpoint >> 16)
+	sim.registers[2] = uint16(setpoint)
+
+	msgBus := testingutil.NewFakeMsgBus()
+	driver, err := NewModbusDriver(map[string]interface{}{}, []messagebuses.MessageBus{msgBus})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer driver.Stop(context.TODO())
+
+	if err := driver.AddDevice(newTestDevice(sim.address())); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"running":     true,
+		"temperature": int16(-5),
+		"setpoint":    float32(21.5),
+	}
+	actual := testingutil.WaitForReadings(t, msgBus, "running", "temperature", "setpoint")
+
+	for name, value := range expected {
+		if actual[name] != value {
+			t.Errorf("expected %s to be %v, but got %v", name, value, actual[name])
+		}
+	}
+
+	state := driver.GetDeviceState("plc")
+	if state == nil || !state.Connected {
+		t.Errorf("expected the device to be connected, but got %v", state)
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	sim := newSimulator(t)
+
+	driver, err := NewModbusDriver(map[string]interface{}{}, []messagebuses.MessageBus{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer driver.Stop(context.TODO())
+
+	if err := driver.AddDevice(newTestDevice(sim.address())); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := driver.RunCommand(util.Command{
+		DeviceName:    "plc",
+		DeviceCommand: "running",
+		Attributes:    util.Attributes{"running": true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !sim.coil(0) {
+		t.Errorf("expected the coil 0 to be set")
+	}
+
+	if err := driver.RunCommand(util.Command{
+		DeviceName:    "plc",
+		DeviceCommand: "setpoint",
+		Attributes:    util.Attributes{"setpoint": 18.25},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	written := math.Float32frombits(uint32(sim.register(1))<<16 | uint32(sim.register(2)))
+	if written != 18.25 {
+		t.Errorf("expected the setpoint 18.25 to be written, but got %v", written)
+	}
+
+	if err := driver.RunCommand(util.Command{
+		DeviceName:    "plc",
+		DeviceCommand: "temperature",
+		Attributes:    util.Attributes{"temperature": 1},
+	}); err == nil {
+		t.Errorf("expected the read-only resource to be rejected")
+	}
+}
+
+func TestStop(t *testing.T) {
+	sim := newSimulator(t)
+
+	driver, err := NewModbusDriver(map[string]interface{}{}, []messagebuses.MessageBus{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := driver.AddDevice(newTestDevice(sim.address())); err != nil {
+		t.Fatal(err)
+	}
+
+	driver.Stop(context.TODO())
+
+	if len(driver.devices) != 0 {
+		t.Errorf("expected the devices to be cleared, but got %d", len(driver.devices))
+	}
+
+	if state := driver.GetDeviceState("plc"); state != nil {
+		t.Errorf("expected the device state to be removed, but got %v", state)
+	}
+
+	// the device can be added again after the driver is stopped
+	if err := driver.AddDevice(newTestDevice(sim.address())); err != nil {
+		t.Fatal(err)
+	}
+	driver.Stop(context.TODO())
+}
+
+func TestNewModbusDriver(t *testing.T) {
+	cases := []struct {
+		name        string
+		config      map[string]interface{}
+		expectedErr bool
+	}{
+		{
+			name:   "the default config",
+			config: map[string]interface{}{},
+		},
+		{
+			name:        "the config is invalid",
+			config:      map[string]interface{}{"pollInterval": []string{"1s"}},
+			expectedErr: true,
+		},
+		{
+			name:        "the max backoff is invalid",
+			config:      map[string]interface{}{"maxBackoff": "1min"},
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			driver, err := NewModbusDriver(c.config, []messagebuses.MessageBus{})
+			if c.expectedErr {
+				if err == nil || driver != nil {
+					t.Errorf("expected an error, but got the driver %v", driver)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if driver.config.PollInterval != defaultPollInterval || driver.config.Timeout != defaultTimeout {
+				t.Errorf("expected the default config, but got %v", driver.config)
+			}
+		})
+	}
+}
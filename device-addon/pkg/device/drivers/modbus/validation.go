@@ -0,0 +1,126 @@
+package modbus
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+var protocols = []string{ProtocolTCP, ProtocolRTU}
+
+var parities = []string{"N", "E", "O"}
+
+var functionCodes = []string{"1", "2", "3", "4"}
+
+var dataTypes = []string{
+	DataTypeBool,
+	DataTypeInt16, DataTypeUint16,
+	DataTypeInt32, DataTypeUint32, DataTypeFloat32,
+	DataTypeInt64, DataTypeUint64, DataTypeFloat64,
+}
+
+// ValidateConfig validates the modbus driver properties
+func ValidateConfig(properties map[string]interface{}, fldPath *field.Path) field.ErrorList {
+	config := &Config{}
+	if err := util.ToConfigObj(properties, config); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, field.OmitValueType{},
+			fmt.Sprintf("failed to parse the modbus driver config, %v", err))}
+	}
+
+	errs := field.ErrorList{}
+	errs = append(errs, validateDuration(config.PollInterval, fldPath.Child("pollInterval"))...)
+	errs = append(errs, validateDuration(config.Timeout, fldPath.Child("timeout"))...)
+	errs = append(errs, validateDuration(config.MaxBackoff, fldPath.Child("maxBackoff"))...)
+	if config.Jitter < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("jitter"), config.Jitter, "the jitter must not be negative"))
+	}
+
+	return errs
+}
+
+// ValidateDevice validates the modbus protocol properties and resource attributes of a device, the address is
+// required by the device and the function code is required by each device resource
+func ValidateDevice(device v1alpha1.DeviceConfig, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	propsPath := fldPath.Child("protocolProperties")
+	protocolConfig := &ProtocolConfig{}
+	if err := util.ToConfigObj(device.ProtocolProperties.Data, protocolConfig); err != nil {
+		errs = append(errs, field.Invalid(propsPath, field.OmitValueType{},
+			fmt.Sprintf("failed to parse the modbus protocol properties, %v", err)))
+	} else {
+		if len(protocolConfig.Address) == 0 {
+			errs = append(errs, field.Required(propsPath.Child("address"), "the address of the device is required"))
+		}
+
+		switch protocolConfig.Protocol {
+		case "", ProtocolTCP, ProtocolRTU:
+		default:
+			errs = append(errs, field.NotSupported(propsPath.Child("protocol"), protocolConfig.Protocol, protocols))
+		}
+
+		switch protocolConfig.Parity {
+		case "", "N", "E", "O":
+		default:
+			errs = append(errs, field.NotSupported(propsPath.Child("parity"), protocolConfig.Parity, parities))
+		}
+
+		errs = append(errs, validateDuration(protocolConfig.PollInterval, propsPath.Child("pollInterval"))...)
+		errs = append(errs, validateDuration(protocolConfig.Timeout, propsPath.Child("timeout"))...)
+	}
+
+	for i, res := range device.Profile.DeviceResources {
+		resPath := fldPath.Child("profile", "deviceResources").Index(i)
+		attrsPath := resPath.Child("attributes")
+
+		attrs := ResourceAttributes{}
+		if err := util.ToConfigObj(res.Attributes.Data, &attrs); err != nil {
+			errs = append(errs, field.Invalid(attrsPath, field.OmitValueType{},
+				fmt.Sprintf("failed to parse the modbus resource attributes, %v", err)))
+			continue
+		}
+
+		switch attrs.FunctionCode {
+		case ReadCoils, ReadHoldingRegisters:
+		case ReadDiscreteInputs, ReadInputRegisters:
+			// the discrete inputs and input registers are read only
+			if util.IsWritable(res.Properties.ReadWrite) {
+				errs = append(errs, field.Invalid(resPath.Child("properties", "readWrite"), res.Properties.ReadWrite,
+					fmt.Sprintf("the function code %d is read only", attrs.FunctionCode)))
+			}
+		default:
+			errs = append(errs, field.NotSupported(attrsPath.Child("functionCode"), attrs.FunctionCode, functionCodes))
+		}
+
+		// the data type of the coils and discrete inputs is always Bool
+		if attrs.FunctionCode != ReadHoldingRegisters && attrs.FunctionCode != ReadInputRegisters {
+			continue
+		}
+
+		if len(attrs.DataType) == 0 {
+			continue
+		}
+
+		if _, err := toQuantity(attrs); err != nil {
+			errs = append(errs, field.NotSupported(attrsPath.Child("dataType"), attrs.DataType, dataTypes))
+		}
+	}
+
+	return errs
+}
+
+func validateDuration(duration string, fldPath *field.Path) field.ErrorList {
+	if len(duration) == 0 {
+		return nil
+	}
+
+	if _, err := time.ParseDuration(duration); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, duration, err.Error())}
+	}
+
+	return nil
+}
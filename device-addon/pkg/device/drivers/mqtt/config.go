@@ -2,9 +2,65 @@ package mqtt
 
 import "open-cluster-management-io/addon-contrib/device-addon/pkg/device/client"
 
+const (
+	PayloadFormatJSON = "json"
+	PayloadFormatCSV  = "csv"
+	PayloadFormatRaw  = "raw"
+)
+
+// The named placeholders of the topic templates
+const (
+	DevicePlaceholder   = "device"
+	ResourcePlaceholder = "resource"
+)
+
+// The device resource attributes that are used by the payload decoders
+const (
+	// JSONPathAttribute selects the value of the resource from a json payload, e.g. sensors.temperature or values[0]
+	JSONPathAttribute = "jsonPath"
+	// ColumnAttribute is the index of the column of the resource in a csv payload
+	ColumnAttribute = "column"
+)
+
 type Config struct {
-	client.MQTTBrokerInfo `json:"inline"`
+	client.MQTTBrokerInfo
 
+	// SubTopic is the topic filter to subscribe the device data, it is generated from the topic template if it
+	// is not set
 	SubTopic string `json:"subTopic"`
+	// TopicTemplate maps the data topics to the devices with the named placeholders, e.g.
+	// sub/data/{device}/{resource}, the {device} is the device name, the {resource} is the device resource name
+	// and the other placeholders match any topic level. If it is not set, the topic level that matches the "#"
+	// of the sub topic is the device name.
+	TopicTemplate string `json:"topicTemplate"`
+	// PubTopic is the device command topic, the "+" or the {device} in the topic is replaced with the device name
 	PubTopic string `json:"pubTopic"`
 }
+
+// DeviceConfig is the mqtt protocol properties of a device
+type DeviceConfig struct {
+	// Topic is the data topic template of the device, it can have the {resource} placeholder. If it is not set,
+	// the device data is received from the topics that match the topic template of the driver.
+	Topic string `json:"topic"`
+	// Payload is the payload decoder of the device
+	Payload PayloadConfig `json:"payload"`
+}
+
+// PayloadConfig is the payload decoder of a device
+//   - json: the payload is a json object, the value of a device resource is the field that is named with the
+//     resource name or the field that is selected by the jsonPath attribute of the resource. If the topic has
+//     the resource, the whole payload is the value of the resource.
+//   - csv: the payload is a csv record, the value of a device resource is the column that is set by the column
+//     attribute of the resource or the column that is named with the resource in the columns.
+//   - raw: the whole payload is the value of the resource in the topic or the resource that is set by the
+//     resource field.
+type PayloadConfig struct {
+	// Format is the payload format: json (default), csv or raw
+	Format string `json:"format"`
+	// Separator is the separator of the csv payload, default is ","
+	Separator string `json:"separator"`
+	// Columns are the resource names of the csv columns
+	Columns []string `json:"columns"`
+	// Resource is the resource of the raw payload if the topic does not have the resource
+	Resource string `json:"resource"`
+}
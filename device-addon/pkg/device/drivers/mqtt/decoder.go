@@ -0,0 +1,184 @@
+package mqtt
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cast"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// mqttDevice is a device with its data topic and payload decoder
+type mqttDevice struct {
+	config  v1alpha1.DeviceConfig
+	topic   *topicTemplate
+	payload PayloadConfig
+}
+
+func newMQTTDevice(config v1alpha1.DeviceConfig) (*mqttDevice, error) {
+	deviceConfig := &DeviceConfig{}
+	if err := util.ToConfigObj(config.ProtocolProperties.Data, deviceConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse the mqtt protocol properties of device %s, %v", config.Name, err)
+	}
+
+	switch deviceConfig.Payload.Format {
+	case "":
+		deviceConfig.Payload.Format = PayloadFormatJSON
+	case PayloadFormatJSON, PayloadFormatCSV, PayloadFormatRaw:
+	default:
+		return nil, fmt.Errorf("unsupported payload format %s of device %s", deviceConfig.Payload.Format, config.Name)
+	}
+
+	if len(deviceConfig.Payload.Separator) == 0 {
+		deviceConfig.Payload.Separator = ","
+	}
+
+	device := &mqttDevice{
+		config:  config,
+		payload: deviceConfig.Payload,
+	}
+
+	if len(deviceConfig.Topic) != 0 {
+		topic, err := parseTopicTemplate(deviceConfig.Topic)
+		if err != nil {
+			return nil, err
+		}
+		device.topic = topic
+	}
+
+	return device, nil
+}
+
+// decode returns the readings of the device resources from the payload, the readings are keyed by the resource
+// names. If the resource name is set, only the reading of the resource is returned.
+func (d *mqttDevice) decode(resourceName string, payload []byte) (map[string]interface{}, error) {
+	resources := d.config.Profile.DeviceResources
+	if len(resourceName) != 0 {
+		res := util.FindDeviceResource(resourceName, resources)
+		if res == nil {
+			return nil, fmt.Errorf("the resource %s does not exist", resourceName)
+		}
+		resources = []v1alpha1.DeviceResource{*res}
+	}
+
+	switch d.payload.Format {
+	case PayloadFormatCSV:
+		return d.decodeCSV(resources, payload)
+	case PayloadFormatRaw:
+		return d.decodeRaw(resourceName, resources, payload)
+	default:
+		return d.decodeJSON(resourceName, resources, payload)
+	}
+}
+
+func (d *mqttDevice) decodeJSON(resourceName string, resources []v1alpha1.DeviceResource,
+	payload []byte) (map[string]interface{}, error) {
+	var data interface{}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, fmt.Errorf("the payload is not a valid json, %v", err)
+	}
+
+	readings := map[string]interface{}{}
+	for _, res := range resources {
+		if path, ok := res.Attributes.Data[JSONPathAttribute]; ok {
+			val, err := util.LookupJSONPath(data, fmt.Sprintf("%v", path))
+			if err != nil {
+				return nil, fmt.Errorf("failed to find the value of resource %s, %v", res.Name, err)
+			}
+			if val != nil {
+				readings[res.Name] = val
+			}
+			continue
+		}
+
+		obj, isObj := data.(map[string]interface{})
+		if isObj {
+			if val, ok := obj[res.Name]; ok {
+				readings[res.Name] = val
+				continue
+			}
+		}
+
+		// the whole payload is the value of the resource in the topic
+		if len(resourceName) != 0 {
+			readings[res.Name] = data
+			continue
+		}
+
+		if !isObj {
+			return nil, fmt.Errorf("the payload is not a json object")
+		}
+	}
+
+	return readings, nil
+}
+
+func (d *mqttDevice) decodeCSV(resources []v1alpha1.DeviceResource, payload []byte) (map[string]interface{}, error) {
+	reader := csv.NewReader(bytes.NewReader(payload))
+	reader.Comma = []rune(d.payload.Separator)[0]
+	reader.TrimLeadingSpace = true
+	record, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("the payload is not a valid csv record, %v", err)
+	}
+
+	readings := map[string]interface{}{}
+	for _, res := range resources {
+		index := -1
+		if column, ok := res.Attributes.Data[ColumnAttribute]; ok {
+			index, err = cast.ToIntE(column)
+			if err != nil {
+				return nil, fmt.Errorf("the column of resource %s is invalid, %v", res.Name, err)
+			}
+		} else {
+			for i, name := range d.payload.Columns {
+				if name == res.Name {
+					index = i
+					break
+				}
+			}
+		}
+
+		if index < 0 && len(resources) == 1 && len(record) == 1 {
+			index = 0
+		}
+
+		if index < 0 || index >= len(record) {
+			continue
+		}
+
+		readings[res.Name] = strings.TrimSpace(record[index])
+	}
+
+	return readings, nil
+}
+
+func (d *mqttDevice) decodeRaw(resourceName string, resources []v1alpha1.DeviceResource,
+	payload []byte) (map[string]interface{}, error) {
+	if len(resourceName) == 0 {
+		resourceName = d.payload.Resource
+	}
+
+	var res *v1alpha1.DeviceResource
+	switch {
+	case len(resourceName) != 0:
+		res = util.FindDeviceResource(resourceName, resources)
+	case len(resources) == 1:
+		res = &resources[0]
+	}
+	if res == nil {
+		return nil, fmt.Errorf("the resource of the raw payload is unknown")
+	}
+
+	// the binary value is kept as it is
+	if res.Properties.ValueType == util.ValueTypeBinary {
+		return map[string]interface{}{res.Name: payload}, nil
+	}
+
+	return map[string]interface{}{res.Name: strings.TrimSpace(string(payload))}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/eclipse/paho.golang/paho"
 
@@ -17,11 +18,20 @@ import (
 )
 
 type MQTTDriver struct {
-	config   *Config
-	client   *paho.Client
-	devices  map[string]v1alpha1.DeviceConfig
-	msgBuses []messagebuses.MessageBus
-	msgChan  chan *paho.Publish
+	sync.Mutex
+	// subscriptionLock serializes the subscriptions of the devices, it is held until the subscribe or unsubscribe
+	// is done, so a topic filter that is used by a new device is not unsubscribed by a removed device
+	subscriptionLock sync.Mutex
+	config           *Config
+	conn             *client.MQTTConnection
+	topic            *topicTemplate
+	devices          map[string]*mqttDevice
+	msgBuses         []messagebuses.MessageBus
+	msgChan          chan *paho.Publish
+	states           *util.DeviceStates
+
+	ctx        context.Context
+	cancelFunc context.CancelFunc
 }
 
 func NewMQTTDriver(driverConfig util.ConfigProperties, msgBuses []messagebuses.MessageBus) *MQTTDriver {
@@ -31,10 +41,32 @@ func NewMQTTDriver(driverConfig util.ConfigProperties, msgBuses []messagebuses.M
 		return nil
 	}
 
+	// the topic level that matches the "#" of the sub topic is the device name by default
+	if len(mqttBrokerInfo.TopicTemplate) == 0 && strings.HasSuffix(mqttBrokerInfo.SubTopic, "#") {
+		mqttBrokerInfo.TopicTemplate = strings.TrimSuffix(mqttBrokerInfo.SubTopic, "#") + "{" + DevicePlaceholder + "}/#"
+	}
+
+	var topic *topicTemplate
+	if len(mqttBrokerInfo.TopicTemplate) != 0 {
+		var err error
+		topic, err = parseTopicTemplate(mqttBrokerInfo.TopicTemplate)
+		if err != nil {
+			klog.Errorf("failed to parse mqtt driver config, %v", err)
+			return nil
+		}
+
+		if len(mqttBrokerInfo.SubTopic) == 0 {
+			mqttBrokerInfo.SubTopic = topic.filter()
+		}
+	}
+
 	return &MQTTDriver{
-		devices:  make(map[string]v1alpha1.DeviceConfig),
+		devices:  make(map[string]*mqttDevice),
 		msgBuses: msgBuses,
 		config:   mqttBrokerInfo,
+		topic:    topic,
+		msgChan:  make(chan *paho.Publish, 100),
+		states:   util.NewDeviceStates(),
 	}
 }
 
@@ -43,88 +75,306 @@ func (d *MQTTDriver) GetType() string {
 }
 
 func (d *MQTTDriver) Start(ctx context.Context) error {
-	client, err := client.ConnectToMQTTBroker(
-		ctx,
+	d.ctx, d.cancelFunc = context.WithCancel(ctx)
+
+	conn := client.NewMQTTConnection(
 		&d.config.MQTTBrokerInfo,
-		paho.NewSingleHandlerRouter(func(m *paho.Publish) { d.msgChan <- m }),
+		paho.NewSingleHandlerRouter(func(m *paho.Publish) {
+			select {
+			case d.msgChan <- m:
+			case <-d.ctx.Done():
+			}
+		}),
 	)
-	if err != nil {
-		return err
+	conn.OnConnectionChange(func(connected bool) {
+		klog.Infof("The MQTT driver connection is changed, connected=%v", connected)
+	})
+
+	d.Lock()
+	d.conn = conn
+	d.Unlock()
+
+	// the sub topic is resubscribed automatically after the connection is reconnected
+	if len(d.config.SubTopic) != 0 {
+		if err := conn.Subscribe(ctx, d.config.SubTopic, paho.SubscribeOptions{QoS: byte(d.config.Qos)}); err != nil {
+			return fmt.Errorf("failed to subscribe to %s, %v", d.config.SubTopic, err)
+		}
+		klog.Infof("Subscribing to %s", d.config.SubTopic)
 	}
 
-	_, err = client.Subscribe(ctx, &paho.Subscribe{
-		Subscriptions: map[string]paho.SubscribeOptions{d.config.SubTopic: {QoS: byte(d.config.Qos)}},
-	})
-	if err != nil {
-		return fmt.Errorf("failed to subscribe to %s, %v", d.config.SubTopic, err)
+	if err := conn.Start(d.ctx); err != nil {
+		return err
 	}
 
-	klog.Infof("Subscribing to %s", d.config.SubTopic)
 	go func() {
-		for m := range d.msgChan {
-			klog.Infof("Receive message [%s], payload=%s", m.Topic, string(m.Payload))
-			incomingTopic := m.Topic
-			subscribedTopic := d.config.SubTopic
-			subscribedTopic = strings.Replace(subscribedTopic, "#", "", -1)
-			deviceName := strings.Replace(incomingTopic, subscribedTopic, "", -1)
-
-			device, ok := d.devices[deviceName]
-			if !ok {
-				klog.Infof("Ignore the unknown device %s", deviceName)
-				return
-			}
-
-			data := make(util.Attributes)
-			if err := json.Unmarshal(m.Payload, &data); err != nil {
-				klog.Errorf("failed to unmarshal incoming data for device %s, %v", deviceName, err)
+		for {
+			select {
+			case <-d.ctx.Done():
 				return
-			}
-
-			for key, val := range data {
-				res := util.FindDeviceResource(key, device.Profile.DeviceResources)
-				if res == nil {
-					klog.Warningf("The device  %s attribute %s  is unsupported", deviceName, key)
-					continue
-				}
-
-				result, err := util.NewResult(*res, val)
-				if err != nil {
-					klog.Errorf("The device %s attribute %s  is unsupported, %v", deviceName, key, err)
-					continue
-				}
-
-				// publish the message to message bus
-				for _, msgBus := range d.msgBuses {
-					msgBus.ReceiveData(deviceName, *result)
+			case m := <-d.msgChan:
+				// the message errors are isolated, so one bad message does not stop the others
+				if err := d.handleMessage(m); err != nil {
+					klog.Errorf("failed to handle the message of topic %s, %v", m.Topic, err)
 				}
 			}
 		}
 	}()
 
-	d.client = client
-	return err
+	return nil
 }
 
 func (d *MQTTDriver) Stop(ctx context.Context) {
 	klog.Info("driver is stopping, disconnect the MQTT conn")
-	d.client.Disconnect(&paho.Disconnect{ReasonCode: 0})
+	if d.cancelFunc != nil {
+		d.cancelFunc()
+	}
+
+	if conn := d.getConn(); conn != nil {
+		conn.Disconnect()
+	}
 }
 
-func (d *MQTTDriver) AddDevice(device v1alpha1.DeviceConfig) error {
-	_, ok := d.devices[device.Name]
-	if !ok {
-		d.devices[device.Name] = device
+// GetDeviceState returns the device state, the device connection state is the MQTT connection state of the driver
+func (d *MQTTDriver) GetDeviceState(deviceName string) *util.DeviceState {
+	if d.getDevice(deviceName) == nil {
+		return nil
+	}
+
+	state := d.states.Get(deviceName)
+	if state == nil {
+		state = &util.DeviceState{}
+	}
+
+	connected := d.IsConnected()
+	state.Connected = connected
+	state.Subscribed = connected
+	return state
+}
+
+// IsConnected returns true if the driver is connected to the MQTT broker
+func (d *MQTTDriver) IsConnected() bool {
+	conn := d.getConn()
+	return conn != nil && conn.IsConnected()
+}
+
+func (d *MQTTDriver) AddDevice(config v1alpha1.DeviceConfig) error {
+	device, err := newMQTTDevice(config)
+	if err != nil {
+		return err
+	}
+
+	d.subscriptionLock.Lock()
+	defer d.subscriptionLock.Unlock()
+
+	d.Lock()
+	last := d.devices[config.Name]
+	d.devices[config.Name] = device
+	d.Unlock()
+
+	if last != nil && last.topic != nil && (device.topic == nil || last.topic.filter() != device.topic.filter()) {
+		d.unsubscribe(last.topic.filter())
+	}
+
+	conn := d.getConn()
+	if device.topic != nil && conn != nil {
+		filter := device.topic.filter()
+		if err := conn.Subscribe(context.TODO(), filter, paho.SubscribeOptions{QoS: byte(d.config.Qos)}); err != nil {
+			return fmt.Errorf("failed to subscribe to %s for device %s, %v", filter, config.Name, err)
+		}
 	}
 
 	return nil
 }
 
 func (d *MQTTDriver) RemoveDevice(deviceName string) error {
-	//TODO
+	d.subscriptionLock.Lock()
+	defer d.subscriptionLock.Unlock()
+
+	d.Lock()
+	device, ok := d.devices[deviceName]
+	delete(d.devices, deviceName)
+	d.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	klog.Infof("Remove the device %s", deviceName)
+	d.states.Remove(deviceName)
+	if device.topic != nil {
+		d.unsubscribe(device.topic.filter())
+	}
+
 	return nil
 }
 
+// unsubscribe unsubscribes the topic filter if it is not used by the driver and the other devices, the caller
+// must hold the subscription lock
+func (d *MQTTDriver) unsubscribe(filter string) {
+	conn := d.getConn()
+	if conn == nil || filter == d.config.SubTopic {
+		return
+	}
+
+	d.Lock()
+	for _, device := range d.devices {
+		if device.topic != nil && device.topic.filter() == filter {
+			d.Unlock()
+			return
+		}
+	}
+	d.Unlock()
+
+	if err := conn.Unsubscribe(context.TODO(), filter); err != nil {
+		klog.Errorf("failed to unsubscribe from %s, %v", filter, err)
+	}
+}
+
+func (d *MQTTDriver) getConn() *client.MQTTConnection {
+	d.Lock()
+	defer d.Unlock()
+
+	return d.conn
+}
+
+func (d *MQTTDriver) getDevice(deviceName string) *mqttDevice {
+	d.Lock()
+	defer d.Unlock()
+
+	return d.devices[deviceName]
+}
+
+// findDevice returns the device and the resource name of a topic, the topics of the devices are matched firstly,
+// if the topic matches the topics of several devices, the most specific one is used, the device name breaks the tie.
+// Then the topic template of the driver is matched
+func (d *MQTTDriver) findDevice(topic string) (*mqttDevice, string) {
+	d.Lock()
+	defer d.Unlock()
+
+	var found *mqttDevice
+	var resourceName string
+	for _, device := range d.devices {
+		if device.topic == nil {
+			continue
+		}
+
+		values, ok := device.topic.match(topic)
+		if !ok {
+			continue
+		}
+
+		if found == nil || device.topic.moreSpecific(found.topic) ||
+			(!found.topic.moreSpecific(device.topic) && device.config.Name < found.config.Name) {
+			found, resourceName = device, values[ResourcePlaceholder]
+		}
+	}
+
+	if found != nil {
+		return found, resourceName
+	}
+
+	if d.topic == nil {
+		return nil, ""
+	}
+
+	values, ok := d.topic.match(topic)
+	if !ok {
+		return nil, ""
+	}
+
+	device, ok := d.devices[values[DevicePlaceholder]]
+	if !ok {
+		return nil, ""
+	}
+
+	return device, values[ResourcePlaceholder]
+}
+
+func (d *MQTTDriver) handleMessage(m *paho.Publish) error {
+	klog.V(4).Infof("Receive message [%s], payload=%s", m.Topic, string(m.Payload))
+
+	device, resourceName := d.findDevice(m.Topic)
+	if device == nil {
+		klog.V(4).Infof("Ignore the message of topic %s, it does not belong to any device", m.Topic)
+		return nil
+	}
+
+	deviceName := device.config.Name
+	readings, err := device.decode(resourceName, m.Payload)
+	if err != nil {
+		d.states.RecordError(deviceName, err)
+		return fmt.Errorf("failed to decode the payload of device %s, %v", deviceName, err)
+	}
+
+	for name, val := range readings {
+		res := util.FindDeviceResource(name, device.config.Profile.DeviceResources)
+		if res == nil {
+			continue
+		}
+
+		result, err := util.NewResult(*res, val)
+		if err != nil {
+			klog.Errorf("The device %s attribute %s  is unsupported, %v", deviceName, name, err)
+			d.states.RecordError(deviceName, err)
+			continue
+		}
+
+		d.states.RecordReading(deviceName)
+
+		// publish the message to message bus
+		for _, msgBus := range d.msgBuses {
+			if err := msgBus.ReceiveData(deviceName, *result); err != nil {
+				klog.Errorf("failed to publish the data of device %s, %v", deviceName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// RunCommand publishes the command values to the device command topic, the "+" or the {device} in the pub topic
+// is replaced with the device name, the payload is a json map, its keys are the device resource names.
 func (d *MQTTDriver) RunCommand(command util.Command) error {
-	// TODO
+	device := d.getDevice(command.DeviceName)
+	if device == nil {
+		return fmt.Errorf("the device %s does not exist", command.DeviceName)
+	}
+
+	if len(d.config.PubTopic) == 0 {
+		return fmt.Errorf("the pub topic is not configured for the device %s", command.DeviceName)
+	}
+
+	requests, err := util.ToWriteRequests(device.config, command)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]any{}
+	for _, req := range requests {
+		values[req.Resource.Name] = req.Value
+	}
+
+	payload, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	topic := strings.Replace(d.config.PubTopic, "+", command.DeviceName, 1)
+	topic = strings.ReplaceAll(topic, "{"+DevicePlaceholder+"}", command.DeviceName)
+
+	conn := d.getConn()
+	if conn == nil {
+		return fmt.Errorf("the driver is not started, failed to send command to device %s", command.DeviceName)
+	}
+
+	klog.Infof("Send command to device [%s] [%s] %s", topic, command.DeviceName, string(payload))
+	if _, err := conn.Publish(context.TODO(), &paho.Publish{
+		Topic:   topic,
+		QoS:     byte(d.config.Qos),
+		Payload: payload,
+	}); err != nil {
+		return fmt.Errorf("failed to send command to device %s, %v", command.DeviceName, err)
+	}
+
 	return nil
 }
@@ -0,0 +1,75 @@
+package mqtt
+
+import (
+	"testing"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+)
+
+func TestFindDevice(t *testing.T) {
+	newDevice := func(name, topic string) *mqttDevice {
+		device, err := newMQTTDevice(v1alpha1.DeviceConfig{
+			Name:               name,
+			ProtocolProperties: v1alpha1.Values{Data: map[string]interface{}{"topic": topic}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return device
+	}
+
+	driver := NewMQTTDriver(map[string]interface{}{"subTopic": "devices/#"}, nil)
+	for _, device := range []*mqttDevice{
+		newDevice("all", "devices/#"),
+		newDevice("sensors", "devices/sensors/{resource}"),
+		newDevice("temperature", "devices/sensors/temperature"),
+		newDevice("b", "devices/lights/+"),
+		newDevice("a", "devices/lights/+"),
+	} {
+		driver.devices[device.config.Name] = device
+	}
+
+	cases := []struct {
+		name             string
+		topic            string
+		expectedDevice   string
+		expectedResource string
+	}{
+		{
+			name:           "exact topic",
+			topic:          "devices/sensors/temperature",
+			expectedDevice: "temperature",
+		},
+		{
+			name:             "placeholder",
+			topic:            "devices/sensors/humidity",
+			expectedDevice:   "sensors",
+			expectedResource: "humidity",
+		},
+		{
+			name:           "same specificity",
+			topic:          "devices/lights/kitchen",
+			expectedDevice: "a",
+		},
+		{
+			name:           "multi level",
+			topic:          "devices/doors/front/state",
+			expectedDevice: "all",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// the result is stable across the random map iterations
+			for i := 0; i < 10; i++ {
+				device, resource := driver.findDevice(c.topic)
+				if device == nil || device.config.Name != c.expectedDevice {
+					t.Fatalf("expected device %s, but got %v", c.expectedDevice, device)
+				}
+				if resource != c.expectedResource {
+					t.Errorf("expected resource %q, but got %q", c.expectedResource, resource)
+				}
+			}
+		})
+	}
+}
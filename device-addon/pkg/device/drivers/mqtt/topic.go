@@ -0,0 +1,105 @@
+package mqtt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// topicTemplate maps the topics to the devices, each named placeholder of the template matches one topic level
+// and the "#" at the end of the template matches the remaining levels
+type topicTemplate struct {
+	template string
+	levels   []string
+}
+
+func parseTopicTemplate(template string) (*topicTemplate, error) {
+	levels := strings.Split(template, "/")
+	for i, level := range levels {
+		if level == "#" && i != len(levels)-1 {
+			return nil, fmt.Errorf("the # must be the last level of the topic template %s", template)
+		}
+
+		if strings.ContainsAny(level, "{}") && placeholder(level) == "" {
+			return nil, fmt.Errorf("the placeholder %s must be a whole level of the topic template %s", level, template)
+		}
+	}
+
+	return &topicTemplate{template: template, levels: levels}, nil
+}
+
+// match returns the values of the named placeholders if the topic matches the template
+func (t *topicTemplate) match(topic string) (map[string]string, bool) {
+	values := map[string]string{}
+	levels := strings.Split(topic, "/")
+	for i, level := range t.levels {
+		if level == "#" {
+			return values, true
+		}
+
+		if i >= len(levels) {
+			return nil, false
+		}
+
+		if name := placeholder(level); name != "" {
+			values[name] = levels[i]
+			continue
+		}
+
+		if level != "+" && level != levels[i] {
+			return nil, false
+		}
+	}
+
+	return values, len(levels) == len(t.levels)
+}
+
+// filter returns the topic filter to subscribe the topics of the template
+func (t *topicTemplate) filter() string {
+	levels := make([]string, len(t.levels))
+	for i, level := range t.levels {
+		if placeholder(level) != "" {
+			levels[i] = "+"
+			continue
+		}
+		levels[i] = level
+	}
+
+	return strings.Join(levels, "/")
+}
+
+// placeholder returns the name of the placeholder level, e.g. device for {device}
+func placeholder(level string) string {
+	if len(level) > 2 && strings.HasPrefix(level, "{") && strings.HasSuffix(level, "}") {
+		return level[1 : len(level)-1]
+	}
+	return ""
+}
+
+// moreSpecific returns true if the template is more specific than the other template, a template with more literal
+// levels is more specific, and a template without the "#" is more specific than a template with the "#"
+func (t *topicTemplate) moreSpecific(other *topicTemplate) bool {
+	if t.literals() != other.literals() {
+		return t.literals() > other.literals()
+	}
+
+	if t.multiLevel() != other.multiLevel() {
+		return !t.multiLevel()
+	}
+
+	return len(t.levels) > len(other.levels)
+}
+
+// literals returns the number of the levels that are not a wildcard or a placeholder
+func (t *topicTemplate) literals() int {
+	count := 0
+	for _, level := range t.levels {
+		if level != "#" && level != "+" && placeholder(level) == "" {
+			count++
+		}
+	}
+	return count
+}
+
+func (t *topicTemplate) multiLevel() bool {
+	return len(t.levels) != 0 && t.levels[len(t.levels)-1] == "#"
+}
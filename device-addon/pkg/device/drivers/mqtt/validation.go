@@ -0,0 +1,74 @@
+package mqtt
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/client"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+var authModes = []string{client.AuthModeAnonymous, client.AuthModeBasic, client.AuthModeCertificates}
+
+var payloadFormats = []string{PayloadFormatJSON, PayloadFormatCSV, PayloadFormatRaw}
+
+// ValidateConfig validates the mqtt driver properties, the broker host is required
+func ValidateConfig(properties map[string]interface{}, fldPath *field.Path) field.ErrorList {
+	config := &Config{}
+	if err := util.ToConfigObj(properties, config); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, field.OmitValueType{},
+			fmt.Sprintf("failed to parse the mqtt driver config, %v", err))}
+	}
+
+	errs := field.ErrorList{}
+	if len(config.Host) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("host"), "the host of the mqtt broker is required"))
+	}
+
+	switch config.AuthMode {
+	case "", client.AuthModeAnonymous, client.AuthModeBasic, client.AuthModeCertificates:
+	default:
+		errs = append(errs, field.NotSupported(fldPath.Child("authMode"), config.AuthMode, authModes))
+	}
+
+	if len(config.TopicTemplate) != 0 {
+		if _, err := parseTopicTemplate(config.TopicTemplate); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("topicTemplate"), config.TopicTemplate, err.Error()))
+		}
+	}
+
+	return errs
+}
+
+// ValidateDevice validates the mqtt protocol properties of a device
+func ValidateDevice(device v1alpha1.DeviceConfig, fldPath *field.Path) field.ErrorList {
+	propsPath := fldPath.Child("protocolProperties")
+	deviceConfig := &DeviceConfig{}
+	if err := util.ToConfigObj(device.ProtocolProperties.Data, deviceConfig); err != nil {
+		return field.ErrorList{field.Invalid(propsPath, field.OmitValueType{},
+			fmt.Sprintf("failed to parse the mqtt protocol properties, %v", err))}
+	}
+
+	errs := field.ErrorList{}
+	if len(deviceConfig.Topic) != 0 {
+		if _, err := parseTopicTemplate(deviceConfig.Topic); err != nil {
+			errs = append(errs, field.Invalid(propsPath.Child("topic"), deviceConfig.Topic, err.Error()))
+		}
+	}
+
+	payloadPath := propsPath.Child("payload")
+	switch deviceConfig.Payload.Format {
+	case "", PayloadFormatJSON, PayloadFormatCSV, PayloadFormatRaw:
+	default:
+		errs = append(errs, field.NotSupported(payloadPath.Child("format"), deviceConfig.Payload.Format, payloadFormats))
+	}
+
+	if len(deviceConfig.Payload.Resource) != 0 &&
+		util.FindDeviceResource(deviceConfig.Payload.Resource, device.Profile.DeviceResources) == nil {
+		errs = append(errs, field.NotFound(payloadPath.Child("resource"), deviceConfig.Payload.Resource))
+	}
+
+	return errs
+}
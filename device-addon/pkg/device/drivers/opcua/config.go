@@ -6,9 +6,116 @@ const (
 
 const NODE = "nodeId"
 
+const (
+	AuthModeAnonymous   = "anonymous"
+	AuthModeUsername    = "username"
+	AuthModeCertificate = "certificate"
+)
+
+// The credential files in the credential dir
+const (
+	UsernameFile = "username"
+	PasswordFile = "password"
+	UserCertFile = "tls.crt"
+	UserKeyFile  = "tls.key"
+)
+
+const (
+	DeadbandTypeNone     = "None"
+	DeadbandTypeAbsolute = "Absolute"
+	DeadbandTypePercent  = "Percent"
+)
+
+const defaultPublishingInterval = "500ms"
+
+const (
+	// defaultRootNodeID is the Objects folder of the address space
+	defaultRootNodeID = "i=85"
+	defaultMaxDepth   = 10
+)
+
 type Config struct {
 	SecurityPolicy string `json:"securityPolicy"` // Security policy: None, Basic128Rsa15, Basic256, Basic256Sha256
 	SecurityMode   string `json:"securityMode"`   // Security mode: None, Sign, SignAndEncrypt
 	CertFile       string `json:"certFile"`
 	KeyFile        string `json:"keyFile"`
+
+	// AuthMode is the user authentication mode: anonymous, username or certificate
+	AuthMode string `json:"authMode"`
+	// Username and Password are used by the username auth mode, they are read from the username and password
+	// files in the credential dir if they are not set
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// UserCertFile and UserKeyFile are used by the certificate auth mode, they are the tls.crt and tls.key files
+	// in the credential dir if they are not set. The user token is signed with the client private key, so if the
+	// UserKeyFile is set, it is also used as the client private key.
+	UserCertFile string `json:"userCertFile"`
+	UserKeyFile  string `json:"userKeyFile"`
+	// CredentialDir is the directory of the credential files
+	CredentialDir string `json:"credentialDir"`
+
+	// PKIDir is the directory to trust the server certificates, the trusted certificates are in the trusted sub
+	// directory, the rejected and unknown certificates are saved in the rejected sub directory. The server
+	// certificates are not verified if it is not set.
+	PKIDir string `json:"pkiDir"`
+	// AutoAcceptServerCertificate saves the unknown server certificates to the trusted directory
+	AutoAcceptServerCertificate bool `json:"autoAcceptServerCertificate"`
+
+	// SubscriptionConfig is the default subscription parameters of the devices
+	SubscriptionConfig
+
+	// Discovery is the servers to discover the devices, the devices are not discovered if it is not set
+	Discovery *DiscoveryConfig `json:"discovery,omitempty"`
+}
+
+// DiscoveryConfig is the configuration of the device discovery, each server is browsed from its root node and
+// the found variables are proposed as the resources of one device
+type DiscoveryConfig struct {
+	Servers []DiscoveryServer `json:"servers"`
+}
+
+type DiscoveryServer struct {
+	// DeviceName is the name of the discovered device, it must be a valid kubernetes object name
+	DeviceName string `json:"deviceName"`
+	// Endpoint is the endpoint of the OPC UA server
+	Endpoint string `json:"endpoint"`
+	// RootNodeID is the node to start browsing, default is the Objects folder (i=85)
+	RootNodeID string `json:"rootNodeId,omitempty"`
+	// MaxDepth is the max depth to browse from the root node, default is 10
+	MaxDepth int `json:"maxDepth,omitempty"`
+}
+
+// SubscriptionConfig is the subscription parameters, it can be set in the driver config, the device protocol
+// properties and the device resource attributes, the latter overrides the former. The publishing interval is
+// only used by the driver config and the device protocol properties.
+type SubscriptionConfig struct {
+	// PublishingInterval is the interval of the subscription publishing, e.g. 500ms
+	PublishingInterval string `json:"publishingInterval,omitempty"`
+	// SamplingInterval is the interval of the monitored items sampling, e.g. 100ms, the publishing interval is
+	// used if it is not set
+	SamplingInterval string `json:"samplingInterval,omitempty"`
+	// QueueSize is the queue size of the monitored items, default is 10
+	QueueSize uint32 `json:"queueSize,omitempty"`
+	// DeadbandType is the deadband type of the data change filter: None, Absolute or Percent
+	DeadbandType string `json:"deadbandType,omitempty"`
+	// DeadbandValue is the deadband value of the data change filter
+	DeadbandValue float64 `json:"deadbandValue,omitempty"`
+}
+
+// override returns the subscription config with the fields that are set in the given config
+func (c SubscriptionConfig) override(o SubscriptionConfig) SubscriptionConfig {
+	if len(o.PublishingInterval) != 0 {
+		c.PublishingInterval = o.PublishingInterval
+	}
+	if len(o.SamplingInterval) != 0 {
+		c.SamplingInterval = o.SamplingInterval
+	}
+	if o.QueueSize != 0 {
+		c.QueueSize = o.QueueSize
+	}
+	if len(o.DeadbandType) != 0 {
+		c.DeadbandType = o.DeadbandType
+		c.DeadbandValue = o.DeadbandValue
+	}
+	return c
 }
@@ -0,0 +1,264 @@
+package opcua
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// the value types of the OPC UA built-in data types, the variables of other data types are not discovered
+var dataTypeValueTypes = map[uint32]string{
+	id.Boolean:    util.ValueTypeBool,
+	id.SByte:      util.ValueTypeInt8,
+	id.Byte:       util.ValueTypeUint8,
+	id.Int16:      util.ValueTypeInt16,
+	id.UInt16:     util.ValueTypeUint16,
+	id.Int32:      util.ValueTypeInt32,
+	id.UInt32:     util.ValueTypeUint32,
+	id.Int64:      util.ValueTypeInt64,
+	id.UInt64:     util.ValueTypeUint64,
+	id.Float:      util.ValueTypeFloat32,
+	id.Double:     util.ValueTypeFloat64,
+	id.String:     util.ValueTypeString,
+	id.DateTime:   util.ValueTypeString,
+	id.UtcTime:    util.ValueTypeString,
+	id.ByteString: util.ValueTypeBinary,
+}
+
+// the value types of the one dimension arrays
+var arrayValueTypes = map[string]string{
+	util.ValueTypeBool:    util.ValueTypeBoolArray,
+	util.ValueTypeString:  util.ValueTypeStringArray,
+	util.ValueTypeUint8:   util.ValueTypeUint8Array,
+	util.ValueTypeUint16:  util.ValueTypeUint16Array,
+	util.ValueTypeUint32:  util.ValueTypeUint32Array,
+	util.ValueTypeUint64:  util.ValueTypeUint64Array,
+	util.ValueTypeInt8:    util.ValueTypeInt8Array,
+	util.ValueTypeInt16:   util.ValueTypeInt16Array,
+	util.ValueTypeInt32:   util.ValueTypeInt32Array,
+	util.ValueTypeInt64:   util.ValueTypeInt64Array,
+	util.ValueTypeFloat32: util.ValueTypeFloat32Array,
+	util.ValueTypeFloat64: util.ValueTypeFloat64Array,
+}
+
+// the references that are followed when browsing the address space
+var browseReferences = []uint32{id.Organizes, id.HasComponent, id.HasProperty}
+
+// Discover browses the address space of the discovery servers and returns one device for each server, the
+// variables under the root node are the resources of the device
+func (d *OPCUADriver) Discover(ctx context.Context) ([]v1alpha1.DeviceConfig, error) {
+	if d.config.Discovery == nil {
+		return nil, nil
+	}
+
+	devices := []v1alpha1.DeviceConfig{}
+	for _, server := range d.config.Discovery.Servers {
+		device, err := d.discover(ctx, server)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover the opcua server %s, %v", server.Endpoint, err)
+		}
+
+		klog.Infof("Discovered %d resources from the opcua server %s", len(device.Profile.DeviceResources), server.Endpoint)
+		devices = append(devices, *device)
+	}
+
+	return devices, nil
+}
+
+func (d *OPCUADriver) discover(ctx context.Context, server DiscoveryServer) (*v1alpha1.DeviceConfig, error) {
+	if len(server.DeviceName) == 0 || len(server.Endpoint) == 0 {
+		return nil, fmt.Errorf("the device name and endpoint are required by the discovery server")
+	}
+
+	rootNodeID := server.RootNodeID
+	if len(rootNodeID) == 0 {
+		rootNodeID = defaultRootNodeID
+	}
+
+	root, err := ua.ParseNodeID(rootNodeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root node %s, %v", rootNodeID, err)
+	}
+
+	maxDepth := server.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = defaultMaxDepth
+	}
+
+	client, err := d.connect(ctx, server.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseWithContext(ctx)
+
+	b := &browser{
+		maxDepth: maxDepth,
+		visited:  map[string]bool{root.String(): true},
+		names:    map[string]int{},
+	}
+	if err := b.browseChildren(ctx, client.Node(root), "", 1); err != nil {
+		return nil, err
+	}
+
+	return &v1alpha1.DeviceConfig{
+		Name:        server.DeviceName,
+		DriverType:  d.GetType(),
+		Description: fmt.Sprintf("Discovered from the opcua server %s", server.Endpoint),
+		ProtocolProperties: v1alpha1.Values{
+			Data: map[string]interface{}{Endpoint: server.Endpoint},
+		},
+		Profile: v1alpha1.DeviceProfileSpec{
+			DeviceResources: b.resources,
+		},
+	}, nil
+}
+
+// browser walks the address space and collects the variables as the device resources
+type browser struct {
+	maxDepth  int
+	visited   map[string]bool
+	names     map[string]int
+	resources []v1alpha1.DeviceResource
+}
+
+func (b *browser) browseChildren(ctx context.Context, n *opcua.Node, path string, depth int) error {
+	if depth > b.maxDepth {
+		return nil
+	}
+
+	for _, ref := range browseReferences {
+		children, err := n.ReferencedNodesWithContext(ctx, ref, ua.BrowseDirectionForward, ua.NodeClassAll, true)
+		if err != nil {
+			return fmt.Errorf("failed to browse the references of node %s, %v", n.ID, err)
+		}
+
+		for _, child := range children {
+			// the address space is a graph, a node may be referenced by several nodes
+			if b.visited[child.ID.String()] {
+				continue
+			}
+			b.visited[child.ID.String()] = true
+
+			if err := b.browse(ctx, child, path, depth); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *browser) browse(ctx context.Context, n *opcua.Node, path string, depth int) error {
+	attrs, err := n.AttributesWithContext(ctx,
+		ua.AttributeIDNodeClass,
+		ua.AttributeIDBrowseName,
+		ua.AttributeIDDescription,
+		ua.AttributeIDAccessLevel,
+		ua.AttributeIDDataType,
+		ua.AttributeIDValueRank,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to read the attributes of node %s, %v", n.ID, err)
+	}
+
+	if attrs[0].Status != ua.StatusOK || attrs[1].Status != ua.StatusOK {
+		klog.V(4).Infof("Skip the node %s, its node class or browse name is unreadable", n.ID)
+		return nil
+	}
+
+	browseName := attrs[1].Value.String()
+	if qn, ok := attrs[1].Value.Value().(*ua.QualifiedName); ok {
+		browseName = qn.Name
+	}
+	if len(path) != 0 {
+		browseName = path + "." + browseName
+	}
+
+	if ua.NodeClass(attrs[0].Value.Int()) == ua.NodeClassVariable {
+		if res := b.toResource(n, browseName, attrs); res != nil {
+			b.resources = append(b.resources, *res)
+		}
+	}
+
+	return b.browseChildren(ctx, n, browseName, depth+1)
+}
+
+// toResource returns the device resource of a variable, nil is returned if the data type of the variable
+// is unsupported
+func (b *browser) toResource(n *opcua.Node, name string, attrs []*ua.DataValue) *v1alpha1.DeviceResource {
+	if attrs[4].Status != ua.StatusOK {
+		return nil
+	}
+
+	dataType := attrs[4].Value.NodeID()
+	if dataType == nil || dataType.Namespace() != 0 {
+		klog.V(4).Infof("Skip the variable %s, its data type is not a built-in type", n.ID)
+		return nil
+	}
+
+	valueType, ok := dataTypeValueTypes[dataType.IntID()]
+	if !ok {
+		klog.V(4).Infof("Skip the variable %s, its data type %s is unsupported", n.ID, dataType)
+		return nil
+	}
+
+	// the value rank is 1 if the value is a one dimension array
+	if attrs[5].Status == ua.StatusOK && attrs[5].Value.Int() == 1 {
+		valueType, ok = arrayValueTypes[valueType]
+		if !ok {
+			klog.V(4).Infof("Skip the variable %s, its array data type %s is unsupported", n.ID, dataType)
+			return nil
+		}
+	}
+
+	readWrite := ""
+	if attrs[3].Status == ua.StatusOK {
+		accessLevel := ua.AccessLevelType(attrs[3].Value.Uint())
+		if accessLevel&ua.AccessLevelTypeCurrentRead != 0 {
+			readWrite += "R"
+		}
+		if accessLevel&ua.AccessLevelTypeCurrentWrite != 0 {
+			readWrite += "W"
+		}
+	}
+	if len(readWrite) == 0 {
+		readWrite = "R"
+	}
+
+	description := ""
+	if attrs[2].Status == ua.StatusOK {
+		if text, ok := attrs[2].Value.Value().(*ua.LocalizedText); ok {
+			description = text.Text
+		}
+	}
+
+	// the browse names may be duplicated with different namespaces
+	resourceName := strings.ReplaceAll(name, " ", "_")
+	if count := b.names[resourceName]; count > 0 {
+		b.names[resourceName] = count + 1
+		resourceName = fmt.Sprintf("%s_%d", resourceName, count)
+	} else {
+		b.names[resourceName] = 1
+	}
+
+	return &v1alpha1.DeviceResource{
+		Name:        resourceName,
+		Description: description,
+		Properties: v1alpha1.ResourceProperties{
+			ReadWrite: v1alpha1.ReadWrite(readWrite),
+			ValueType: valueType,
+		},
+		Attributes: v1alpha1.Values{
+			Data: map[string]interface{}{NODE: n.ID.String()},
+		},
+	}
+}
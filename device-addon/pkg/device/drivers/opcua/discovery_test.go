@@ -0,0 +1,154 @@
+package opcua
+
+import (
+	"testing"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/id"
+	"github.com/gopcua/opcua/ua"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+func newDataValue(v interface{}) *ua.DataValue {
+	return &ua.DataValue{Status: ua.StatusOK, Value: ua.MustVariant(v)}
+}
+
+// newAttributes returns the attributes of a variable in the order that they are read by the browser
+func newAttributes(description string, accessLevel ua.AccessLevelType, dataType *ua.NodeID, valueRank int32) []*ua.DataValue {
+	attrs := []*ua.DataValue{
+		newDataValue(uint32(ua.NodeClassVariable)),
+		newDataValue(&ua.QualifiedName{Name: "var"}),
+		newDataValue(ua.NewLocalizedText(description)),
+		newDataValue(byte(accessLevel)),
+		newDataValue(dataType),
+		newDataValue(valueRank),
+	}
+	if accessLevel == 0 {
+		attrs[3] = &ua.DataValue{Status: ua.StatusBadAttributeIDInvalid}
+	}
+	return attrs
+}
+
+func TestToResource(t *testing.T) {
+	readWrite := ua.AccessLevelTypeCurrentRead | ua.AccessLevelTypeCurrentWrite
+
+	cases := []struct {
+		name                string
+		browseName          string
+		attrs               []*ua.DataValue
+		existingNames       map[string]int
+		expectedName        string
+		expectedDescription string
+		expectedReadWrite   v1alpha1.ReadWrite
+		expectedValueType   string
+		expectedNil         bool
+	}{
+		{
+			name:                "readable and writable double",
+			browseName:          "temperature",
+			attrs:               newAttributes("the temperature", readWrite, ua.NewNumericNodeID(0, id.Double), -1),
+			expectedName:        "temperature",
+			expectedDescription: "the temperature",
+			expectedReadWrite:   v1alpha1.ReadWrite("RW"),
+			expectedValueType:   util.ValueTypeFloat64,
+		},
+		{
+			name:              "write only",
+			browseName:        "switch",
+			attrs:             newAttributes("", ua.AccessLevelTypeCurrentWrite, ua.NewNumericNodeID(0, id.Boolean), -1),
+			expectedName:      "switch",
+			expectedReadWrite: v1alpha1.ReadWrite("W"),
+			expectedValueType: util.ValueTypeBool,
+		},
+		{
+			name:              "readable by default",
+			browseName:        "counter",
+			attrs:             newAttributes("", 0, ua.NewNumericNodeID(0, id.UInt32), -1),
+			expectedName:      "counter",
+			expectedReadWrite: v1alpha1.ReadWrite("R"),
+			expectedValueType: util.ValueTypeUint32,
+		},
+		{
+			name:              "one dimension array",
+			browseName:        "samples",
+			attrs:             newAttributes("", ua.AccessLevelTypeCurrentRead, ua.NewNumericNodeID(0, id.Int16), 1),
+			expectedName:      "samples",
+			expectedReadWrite: v1alpha1.ReadWrite("R"),
+			expectedValueType: util.ValueTypeInt16Array,
+		},
+		{
+			name:        "array of unsupported type",
+			browseName:  "images",
+			attrs:       newAttributes("", ua.AccessLevelTypeCurrentRead, ua.NewNumericNodeID(0, id.ByteString), 1),
+			expectedNil: true,
+		},
+		{
+			name:        "not a built-in data type",
+			browseName:  "status",
+			attrs:       newAttributes("", ua.AccessLevelTypeCurrentRead, ua.NewNumericNodeID(2, id.Double), -1),
+			expectedNil: true,
+		},
+		{
+			name:        "unsupported data type",
+			browseName:  "location",
+			attrs:       newAttributes("", ua.AccessLevelTypeCurrentRead, ua.NewNumericNodeID(0, id.GUID), -1),
+			expectedNil: true,
+		},
+		{
+			name:              "spaces in the browse name",
+			browseName:        "motor speed",
+			attrs:             newAttributes("", ua.AccessLevelTypeCurrentRead, ua.NewNumericNodeID(0, id.Float), -1),
+			expectedName:      "motor_speed",
+			expectedReadWrite: v1alpha1.ReadWrite("R"),
+			expectedValueType: util.ValueTypeFloat32,
+		},
+		{
+			name:              "duplicated browse name",
+			browseName:        "temperature",
+			attrs:             newAttributes("", ua.AccessLevelTypeCurrentRead, ua.NewNumericNodeID(0, id.Double), -1),
+			existingNames:     map[string]int{"temperature": 2},
+			expectedName:      "temperature_2",
+			expectedReadWrite: v1alpha1.ReadWrite("R"),
+			expectedValueType: util.ValueTypeFloat64,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := &browser{names: map[string]int{}}
+			for name, count := range c.existingNames {
+				b.names[name] = count
+			}
+
+			n := &opcua.Node{ID: ua.NewStringNodeID(2, c.browseName)}
+			res := b.toResource(n, c.browseName, c.attrs)
+			if c.expectedNil {
+				if res != nil {
+					t.Errorf("expected the variable is skipped, but got %v", res)
+				}
+				return
+			}
+			if res == nil {
+				t.Fatalf("expected the resource %s, but got nil", c.expectedName)
+			}
+
+			if res.Name != c.expectedName {
+				t.Errorf("expected the name %s, but got %s", c.expectedName, res.Name)
+			}
+			if res.Properties.ReadWrite != c.expectedReadWrite {
+				t.Errorf("expected the permission %s, but got %s", c.expectedReadWrite, res.Properties.ReadWrite)
+			}
+			if res.Properties.ValueType != c.expectedValueType {
+				t.Errorf("expected the value type %s, but got %s", c.expectedValueType, res.Properties.ValueType)
+			}
+			if res.Description != c.expectedDescription {
+				t.Errorf("expected the description %q, but got %q", c.expectedDescription, res.Description)
+			}
+			if res.Attributes.Data[NODE] != n.ID.String() {
+				t.Errorf("expected the node %s, but got %v", n.ID, res.Attributes.Data[NODE])
+			}
+		})
+	}
+}
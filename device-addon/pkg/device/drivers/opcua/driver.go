@@ -2,7 +2,11 @@ package opcua
 
 import (
 	"context"
+	"encoding/pem"
 	"fmt"
+	"os"
+	"path"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,15 +22,16 @@ import (
 )
 
 type request struct {
-	nodeId *ua.NodeID
-	handle uint32
-	res    v1alpha1.DeviceResource
+	nodeId     *ua.NodeID
+	handle     uint32
+	deviceName string
+	res        v1alpha1.DeviceResource
+	params     SubscriptionConfig
 }
 
 type opcuaDevice struct {
 	deviceConfig v1alpha1.DeviceConfig
-	requests     []request
-	cancelFunc   context.CancelFunc
+	endpoint     string
 }
 
 type OPCUADriver struct {
@@ -34,6 +39,10 @@ type OPCUADriver struct {
 	config   *Config
 	msgBuses []messagebuses.MessageBus
 	devices  map[string]opcuaDevice
+	// sessions are the connections to the opcua servers, they are keyed by the endpoint and shared by the
+	// devices on the same endpoint
+	sessions map[string]*session
+	states   *util.DeviceStates
 }
 
 func NewOPCUADriver(driverConfig util.ConfigProperties, msgBuses []messagebuses.MessageBus) *OPCUADriver {
@@ -43,10 +52,16 @@ func NewOPCUADriver(driverConfig util.ConfigProperties, msgBuses []messagebuses.
 		return nil
 	}
 
+	if len(config.PublishingInterval) == 0 {
+		config.PublishingInterval = defaultPublishingInterval
+	}
+
 	return &OPCUADriver{
 		devices:  make(map[string]opcuaDevice),
+		sessions: make(map[string]*session),
 		msgBuses: msgBuses,
 		config:   config,
+		states:   util.NewDeviceStates(),
 	}
 }
 
@@ -63,8 +78,9 @@ func (d *OPCUADriver) Stop(ctx context.Context) {
 	d.Lock()
 	defer d.Unlock()
 
-	for _, device := range d.devices {
-		device.cancelFunc()
+	for endpoint, s := range d.sessions {
+		s.stop(true)
+		delete(d.sessions, endpoint)
 	}
 }
 
@@ -72,36 +88,44 @@ func (d *OPCUADriver) AddDevice(config v1alpha1.DeviceConfig) error {
 	d.Lock()
 	defer d.Unlock()
 
-	requests := []request{}
-
 	last, ok := d.devices[config.Name]
-	if ok {
-		if equality.Semantic.DeepEqual(last.deviceConfig, config) {
-			klog.Infof("The device %s already exists", config.Name)
-			return nil
-		}
+	if ok && equality.Semantic.DeepEqual(last.deviceConfig, config) {
+		klog.Infof("The device %s already exists", config.Name)
+		return nil
+	}
 
-		klog.Infof("Restart the device %s", config.Name)
-		requests = append(requests, last.requests...)
-		last.cancelFunc()
+	endpoint, err := d.findEndpoint(config)
+	if err != nil {
+		return err
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	subConfig, err := d.toSubscriptionConfig(config)
+	if err != nil {
+		return err
+	}
 
-	go func() {
-		klog.Infof("Start device %s", config.Name)
-		if err := d.startSubscription(ctx, config); err != nil {
-			klog.Errorf("failed to start device %s, %v", config.Name, err)
-			cancel()
-		}
-		klog.Infof("The device %s is done", config.Name)
-	}()
+	interval, err := time.ParseDuration(subConfig.PublishingInterval)
+	if err != nil {
+		return fmt.Errorf("invalid publishing interval %s of device %s, %v", subConfig.PublishingInterval, config.Name, err)
+	}
+
+	if ok && last.endpoint != endpoint {
+		d.removeFromSession(config.Name, last.endpoint)
+	}
+
+	s, ok := d.sessions[endpoint]
+	if !ok {
+		klog.Infof("Open the session to opcua server %s", endpoint)
+		s = newSession(d, endpoint)
+		s.start()
+		d.sessions[endpoint] = s
+	}
 
 	klog.Infof("The device %s is starting", config.Name)
+	s.addDevice(config, subConfig, interval)
 	d.devices[config.Name] = opcuaDevice{
 		deviceConfig: config,
-		requests:     requests,
-		cancelFunc:   cancel,
+		endpoint:     endpoint,
 	}
 	return nil
 }
@@ -117,25 +141,118 @@ func (d *OPCUADriver) RemoveDevice(deviceName string) error {
 	}
 
 	klog.Infof("Remove the device %s", deviceName)
-	current.cancelFunc()
+	d.removeFromSession(deviceName, current.endpoint)
 	delete(d.devices, deviceName)
+	d.states.Remove(deviceName)
 	return nil
 }
 
+func (d *OPCUADriver) GetDeviceState(deviceName string) *util.DeviceState {
+	return d.states.Get(deviceName)
+}
+
+// removeFromSession removes the device from the session of the endpoint, the session is closed if there is
+// no device on it, the caller must hold the lock
+func (d *OPCUADriver) removeFromSession(deviceName, endpoint string) {
+	s, ok := d.sessions[endpoint]
+	if !ok {
+		return
+	}
+
+	if s.removeDevice(deviceName) == 0 {
+		klog.Infof("Close the session to opcua server %s", endpoint)
+		s.stop(false)
+		delete(d.sessions, endpoint)
+	}
+}
+
+// RunCommand writes the command values to the device nodes with the OPC UA Write service
 func (d *OPCUADriver) RunCommand(command util.Command) error {
-	//TODO
+	d.Lock()
+	device, ok := d.devices[command.DeviceName]
+	var client *opcua.Client
+	if s, found := d.sessions[device.endpoint]; ok && found {
+		client = s.getClient()
+	}
+	d.Unlock()
+	if !ok {
+		return fmt.Errorf("the device %s does not exist", command.DeviceName)
+	}
+
+	if client == nil {
+		return fmt.Errorf("the device %s is not connected", command.DeviceName)
+	}
+
+	requests, err := util.ToWriteRequests(device.deviceConfig, command)
+	if err != nil {
+		return err
+	}
+
+	nodesToWrite := []*ua.WriteValue{}
+	for _, req := range requests {
+		nodeId, err := getNodeID(req.Resource.Attributes, NODE)
+		if err != nil {
+			return err
+		}
+
+		id, err := ua.ParseNodeID(nodeId)
+		if err != nil {
+			return err
+		}
+
+		value, err := ua.NewVariant(req.Value)
+		if err != nil {
+			return fmt.Errorf("failed to convert the value of resource %s, %v", req.Resource.Name, err)
+		}
+
+		nodesToWrite = append(nodesToWrite, &ua.WriteValue{
+			NodeID:      id,
+			AttributeID: ua.AttributeIDValue,
+			Value: &ua.DataValue{
+				EncodingMask: ua.DataValueValue,
+				Value:        value,
+			},
+		})
+	}
+
+	resp, err := client.WriteWithContext(context.TODO(), &ua.WriteRequest{NodesToWrite: nodesToWrite})
+	if err != nil {
+		return fmt.Errorf("failed to write the device %s, %v", command.DeviceName, err)
+	}
+
+	for i, status := range resp.Results {
+		if status != ua.StatusOK {
+			return fmt.Errorf("failed to write the resource %s of device %s, %v",
+				requests[i].Resource.Name, command.DeviceName, status)
+		}
+	}
+
 	return nil
 }
 
-func (d *OPCUADriver) startSubscription(ctx context.Context, config v1alpha1.DeviceConfig) error {
-	endpoint, err := d.findEndpoint(config)
+// publish sends the reading of the device resource to the message buses
+func (d *OPCUADriver) publish(deviceName string, res v1alpha1.DeviceResource, data interface{}) {
+	result, err := util.NewResult(res, data)
 	if err != nil {
-		return err
+		klog.Errorf("The device %s attribute %s  is unsupported, %v", deviceName, res.Name, err)
+		d.states.RecordError(deviceName, err)
+		return
 	}
 
+	d.states.RecordReading(deviceName)
+
+	for _, msgBus := range d.msgBuses {
+		if err := msgBus.ReceiveData(deviceName, *result); err != nil {
+			klog.Errorf("failed to publish the data of device %s, %v", deviceName, err)
+		}
+	}
+}
+
+// connect connects to the opcua server with the security and the user authentication of the driver config
+func (d *OPCUADriver) connect(ctx context.Context, endpoint string) (*opcua.Client, error) {
 	endpoints, err := opcua.GetEndpoints(ctx, endpoint)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	ep := opcua.SelectEndpoint(
 		endpoints,
@@ -143,7 +260,18 @@ func (d *OPCUADriver) startSubscription(ctx context.Context, config v1alpha1.Dev
 		ua.MessageSecurityModeFromString(d.config.SecurityMode),
 	)
 	if ep == nil {
-		return fmt.Errorf("failed to find suitable endpoint")
+		return nil, fmt.Errorf("failed to find suitable endpoint")
+	}
+
+	if len(d.config.PKIDir) != 0 {
+		if err := verifyServerCertificate(d.config.PKIDir, d.config.AutoAcceptServerCertificate, ep.ServerCertificate); err != nil {
+			return nil, err
+		}
+	}
+
+	authOpts, authType, err := d.authOptions()
+	if err != nil {
+		return nil, err
 	}
 
 	opts := []opcua.Option{
@@ -151,80 +279,16 @@ func (d *OPCUADriver) startSubscription(ctx context.Context, config v1alpha1.Dev
 		opcua.SecurityModeString(d.config.SecurityMode),
 		opcua.CertificateFile(d.config.CertFile),
 		opcua.PrivateKeyFile(d.config.KeyFile),
-		opcua.AuthAnonymous(),
-		opcua.SecurityFromEndpoint(ep, ua.UserTokenTypeAnonymous),
 	}
+	opts = append(opts, authOpts...)
+	opts = append(opts, opcua.SecurityFromEndpoint(ep, authType))
 
 	client := opcua.NewClient(ep.EndpointURL, opts...)
 	if err := client.Connect(ctx); err != nil {
-		return err
-	}
-	defer client.CloseWithContext(ctx)
-
-	klog.Infof("Connected to opcua server %s", endpoint)
-
-	notifyCh := make(chan *opcua.PublishNotificationData)
-	interval := time.Duration(500) * time.Millisecond
-	sub, err := client.SubscribeWithContext(ctx, &opcua.SubscriptionParameters{Interval: interval}, notifyCh)
-	if err != nil {
-		return err
-	}
-	defer sub.Cancel(ctx)
-
-	klog.Infof("Created subscription with id %v", sub.SubscriptionID)
-
-	for index, deviceResource := range config.Profile.DeviceResources {
-		req, err := d.toRequest(config.Name, index, deviceResource)
-		if err != nil {
-			return err
-		}
-
-		resp, err := sub.Monitor(ua.TimestampsToReturnBoth, valueRequest(req))
-		if err != nil || resp.Results[0].StatusCode != ua.StatusOK {
-			return err
-		}
+		return nil, err
 	}
 
-	// read from subscription's notification channel until ctx is cancelled
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case res := <-notifyCh:
-			if res.Error != nil {
-				klog.Errorf("%v", res.Error)
-				continue
-			}
-
-			switch x := res.Value.(type) {
-			case *ua.DataChangeNotification:
-				for _, item := range x.MonitoredItems {
-					data := item.Value.Value.Value()
-					klog.Infof("MonitoredItem with client handle %v = %v", item.ClientHandle, data)
-
-					req := d.findRequest(config.Name, item.ClientHandle)
-					if req == nil {
-						continue
-					}
-
-					result, err := util.NewResult(req.res, data)
-					if err != nil {
-						klog.Errorf("The device %s attribute %s  is unsupported, %v", config.Name, req.res.Name, err)
-						continue
-					}
-
-					for _, msgBus := range d.msgBuses {
-						msgBus.ReceiveData(config.Name, *result)
-					}
-				}
-
-			case *ua.EventNotificationList:
-				// do nothing
-			default:
-				klog.Infof("unknown publish result: %T", res.Value)
-			}
-		}
-	}
+	return client, nil
 }
 
 func (d *OPCUADriver) findEndpoint(config v1alpha1.DeviceConfig) (string, error) {
@@ -236,7 +300,7 @@ func (d *OPCUADriver) findEndpoint(config v1alpha1.DeviceConfig) (string, error)
 	return fmt.Sprintf("%v", endpoint), nil
 }
 
-func (d *OPCUADriver) toRequest(deviceName string, index int, res v1alpha1.DeviceResource) (*request, error) {
+func toRequest(deviceName string, res v1alpha1.DeviceResource, subConfig SubscriptionConfig) (*request, error) {
 	nodeId, err := getNodeID(res.Attributes, NODE)
 	if err != nil {
 		return nil, err
@@ -247,39 +311,145 @@ func (d *OPCUADriver) toRequest(deviceName string, index int, res v1alpha1.Devic
 		return nil, err
 	}
 
-	req := request{
-		nodeId: id,
-		handle: uint32(index + 42),
-		res:    res,
+	resSubConfig := SubscriptionConfig{}
+	if err := util.ToConfigObj(res.Attributes.Data, &resSubConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse the subscription parameters of resource %s, %v", res.Name, err)
 	}
 
-	device, ok := d.devices[deviceName]
-	if !ok {
-		return nil, fmt.Errorf("the device %s is not added", deviceName)
+	return &request{
+		nodeId:     id,
+		deviceName: deviceName,
+		res:        res,
+		params:     subConfig.override(resSubConfig),
+	}, nil
+}
+
+func valueRequest(req *request) (*ua.MonitoredItemCreateRequest, error) {
+	monitorReq := opcua.NewMonitoredItemCreateRequestWithDefaults(req.nodeId, ua.AttributeIDValue, req.handle)
+
+	params := req.params
+	if len(params.SamplingInterval) != 0 {
+		samplingInterval, err := time.ParseDuration(params.SamplingInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sampling interval %s of resource %s, %v", params.SamplingInterval, req.res.Name, err)
+		}
+		// the sampling interval is in milliseconds
+		monitorReq.RequestedParameters.SamplingInterval = float64(samplingInterval) / float64(time.Millisecond)
+	}
+
+	if params.QueueSize != 0 {
+		monitorReq.RequestedParameters.QueueSize = params.QueueSize
+	}
+
+	var deadbandType ua.DeadbandType
+	switch params.DeadbandType {
+	case "", DeadbandTypeNone:
+		return monitorReq, nil
+	case DeadbandTypeAbsolute:
+		deadbandType = ua.DeadbandTypeAbsolute
+	case DeadbandTypePercent:
+		deadbandType = ua.DeadbandTypePercent
+	default:
+		return nil, fmt.Errorf("unsupported deadband type %s of resource %s", params.DeadbandType, req.res.Name)
 	}
 
-	device.requests = append(device.requests, req)
-	d.devices[deviceName] = device
-	return &req, nil
+	monitorReq.RequestedParameters.Filter = ua.NewExtensionObject(&ua.DataChangeFilter{
+		Trigger:       ua.DataChangeTriggerStatusValue,
+		DeadbandType:  uint32(deadbandType),
+		DeadbandValue: params.DeadbandValue,
+	})
+	return monitorReq, nil
 }
 
-func (d *OPCUADriver) findRequest(deviceName string, handle uint32) *request {
-	device, ok := d.devices[deviceName]
-	if !ok {
-		return nil
+// toSubscriptionConfig returns the subscription parameters of the device, the parameters in the device protocol
+// properties override the driver config
+func (d *OPCUADriver) toSubscriptionConfig(config v1alpha1.DeviceConfig) (SubscriptionConfig, error) {
+	deviceSubConfig := SubscriptionConfig{}
+	if err := util.ToConfigObj(config.ProtocolProperties.Data, &deviceSubConfig); err != nil {
+		return deviceSubConfig, fmt.Errorf("failed to parse the subscription parameters of device %s, %v", config.Name, err)
 	}
 
-	for _, req := range device.requests {
-		if req.handle == handle {
-			return &req
+	return d.config.SubscriptionConfig.override(deviceSubConfig), nil
+}
+
+// authOptions returns the user authentication options and the user token type of the auth mode
+func (d *OPCUADriver) authOptions() ([]opcua.Option, ua.UserTokenType, error) {
+	switch d.config.AuthMode {
+	case "", AuthModeAnonymous:
+		return []opcua.Option{opcua.AuthAnonymous()}, ua.UserTokenTypeAnonymous, nil
+	case AuthModeUsername:
+		username, err := d.getCredential(d.config.Username, UsernameFile)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		password, err := d.getCredential(d.config.Password, PasswordFile)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return []opcua.Option{opcua.AuthUsername(username, password)}, ua.UserTokenTypeUserName, nil
+	case AuthModeCertificate:
+		certFile := d.getCredentialFile(d.config.UserCertFile, UserCertFile)
+		if len(certFile) == 0 {
+			return nil, 0, fmt.Errorf("the user certificate is required by the auth mode %s", AuthModeCertificate)
+		}
+
+		data, err := os.ReadFile(certFile)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read the user certificate %s, %v", certFile, err)
+		}
+
+		// the user certificate is DER encoded in the user token
+		if block, _ := pem.Decode(data); block != nil {
+			data = block.Bytes
+		}
+
+		opts := []opcua.Option{opcua.AuthCertificate(data)}
+		if keyFile := d.getCredentialFile(d.config.UserKeyFile, UserKeyFile); len(keyFile) != 0 {
+			opts = append(opts, opcua.PrivateKeyFile(keyFile))
 		}
+
+		return opts, ua.UserTokenTypeCertificate, nil
 	}
 
-	return nil
+	return nil, 0, fmt.Errorf("unsupported auth mode %s", d.config.AuthMode)
 }
 
-func valueRequest(req *request) *ua.MonitoredItemCreateRequest {
-	return opcua.NewMonitoredItemCreateRequestWithDefaults(req.nodeId, ua.AttributeIDValue, req.handle)
+// getCredential returns the value if it is set, otherwise reads it from the file in the credential dir
+func (d *OPCUADriver) getCredential(value, file string) (string, error) {
+	if len(value) != 0 {
+		return value, nil
+	}
+
+	if len(d.config.CredentialDir) == 0 {
+		return "", fmt.Errorf("the %s is not set", file)
+	}
+
+	data, err := os.ReadFile(path.Join(d.config.CredentialDir, file))
+	if err != nil {
+		return "", fmt.Errorf("failed to read the %s from %s, %v", file, d.config.CredentialDir, err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// getCredentialFile returns the file if it is set, otherwise returns the file in the credential dir if it exists
+func (d *OPCUADriver) getCredentialFile(file, name string) string {
+	if len(file) != 0 {
+		return file
+	}
+
+	if len(d.config.CredentialDir) == 0 {
+		return ""
+	}
+
+	file = path.Join(d.config.CredentialDir, name)
+	if _, err := os.Stat(file); err != nil {
+		return ""
+	}
+
+	return file
 }
 
 func getNodeID(attrs v1alpha1.Values, id string) (string, error) {
@@ -0,0 +1,170 @@
+package opcua
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	testingutil "open-cluster-management-io/addon-contrib/device-addon/pkg/device/util/testing"
+)
+
+// the endpoints are unreachable, so the sessions keep retrying to connect until they are stopped
+const (
+	endpoint1 = "opc.tcp://127.0.0.1:1"
+	endpoint2 = "opc.tcp://127.0.0.1:2"
+)
+
+func newTestDevice(name, endpoint string) v1alpha1.DeviceConfig {
+	return testingutil.NewDevice(name, "opcua", map[string]interface{}{Endpoint: endpoint},
+		testingutil.NewResource("temperature", "R", "Float64", map[string]interface{}{NODE: "ns=2;s=temperature"}))
+}
+
+// assertSessions asserts the devices of the session on each endpoint
+func assertSessions(t *testing.T, d *OPCUADriver, expected map[string][]string) {
+	t.Helper()
+
+	if len(d.sessions) != len(expected) {
+		t.Fatalf("expected %d sessions, but got %d", len(expected), len(d.sessions))
+	}
+
+	for endpoint, deviceNames := range expected {
+		s, ok := d.sessions[endpoint]
+		if !ok {
+			t.Fatalf("expected the session of %s", endpoint)
+		}
+		s.Lock()
+		if len(s.devices) != len(deviceNames) {
+			t.Errorf("expected the devices %v on %s, but got %d devices", deviceNames, endpoint, len(s.devices))
+		}
+		for _, name := range deviceNames {
+			if _, ok := s.devices[name]; !ok {
+				t.Errorf("expected the device %s on %s", name, endpoint)
+			}
+		}
+		s.Unlock()
+	}
+}
+
+func TestSessionSharing(t *testing.T) {
+	d := NewOPCUADriver(map[string]interface{}{}, nil)
+	defer d.Stop(context.TODO())
+
+	// the devices on the same endpoint share one session
+	for _, config := range []v1alpha1.DeviceConfig{
+		newTestDevice("device1", endpoint1),
+		newTestDevice("device2", endpoint1),
+		newTestDevice("device3", endpoint2),
+	} {
+		if err := d.AddDevice(config); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertSessions(t, d, map[string][]string{
+		endpoint1: {"device1", "device2"},
+		endpoint2: {"device3"},
+	})
+	shared := d.sessions[endpoint1]
+
+	// the session is kept until its last device is removed
+	if err := d.RemoveDevice("device1"); err != nil {
+		t.Fatal(err)
+	}
+	assertSessions(t, d, map[string][]string{
+		endpoint1: {"device2"},
+		endpoint2: {"device3"},
+	})
+	if d.sessions[endpoint1] != shared {
+		t.Errorf("expected the session of %s is kept", endpoint1)
+	}
+
+	// the device is moved to the session of another endpoint, and its last session is closed
+	if err := d.AddDevice(newTestDevice("device2", endpoint2)); err != nil {
+		t.Fatal(err)
+	}
+	assertSessions(t, d, map[string][]string{
+		endpoint2: {"device2", "device3"},
+	})
+	select {
+	case <-shared.done:
+	case <-time.After(10 * time.Second):
+		t.Errorf("expected the session of %s is closed", endpoint1)
+	}
+
+	for _, name := range []string{"device2", "device3"} {
+		if err := d.RemoveDevice(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertSessions(t, d, map[string][]string{})
+	if len(d.devices) != 0 {
+		t.Errorf("expected no device, but got %d", len(d.devices))
+	}
+}
+
+func TestAddDevice(t *testing.T) {
+	cases := []struct {
+		name        string
+		config      v1alpha1.DeviceConfig
+		expectedErr bool
+	}{
+		{
+			name:   "valid device",
+			config: newTestDevice("device1", endpoint1),
+		},
+		{
+			name:        "no endpoint",
+			config:      testingutil.NewDevice("device1", "opcua", map[string]interface{}{}),
+			expectedErr: true,
+		},
+		{
+			name: "invalid publishing interval",
+			config: testingutil.NewDevice("device1", "opcua", map[string]interface{}{
+				Endpoint:             endpoint1,
+				"publishingInterval": "1min",
+			}),
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := NewOPCUADriver(map[string]interface{}{}, nil)
+			defer d.Stop(context.TODO())
+
+			err := d.AddDevice(c.config)
+			if c.expectedErr != (err != nil) {
+				t.Fatalf("expected error %v, but got %v", c.expectedErr, err)
+			}
+			if c.expectedErr && len(d.sessions) != 0 {
+				t.Errorf("expected no session is opened, but got %d", len(d.sessions))
+			}
+		})
+	}
+}
+
+func TestStop(t *testing.T) {
+	d := NewOPCUADriver(map[string]interface{}{}, nil)
+	for _, config := range []v1alpha1.DeviceConfig{
+		newTestDevice("device1", endpoint1),
+		newTestDevice("device2", endpoint2),
+	} {
+		if err := d.AddDevice(config); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sessions := []*session{d.sessions[endpoint1], d.sessions[endpoint2]}
+	d.Stop(context.TODO())
+
+	if len(d.sessions) != 0 {
+		t.Errorf("expected all sessions are closed, but got %d", len(d.sessions))
+	}
+	for _, s := range sessions {
+		select {
+		case <-s.done:
+		default:
+			t.Errorf("expected the session goroutine of %s exits", s.endpoint)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package opcua
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	trustedDir  = "trusted"
+	rejectedDir = "rejected"
+)
+
+// verifyServerCertificate verifies the server certificate with the PKI dir, the certificate is trusted if it is
+// in the trusted dir. The certificate that is in the rejected dir is refused, and an unknown certificate is saved
+// to the rejected dir, so the administrator can trust it by moving it to the trusted dir. If autoAccept is true,
+// an unknown certificate is saved to the trusted dir directly.
+func verifyServerCertificate(pkiDir string, autoAccept bool, cert []byte) error {
+	if len(cert) == 0 {
+		return nil
+	}
+
+	trusted, err := containsCertificate(path.Join(pkiDir, trustedDir), cert)
+	if err != nil {
+		return err
+	}
+	if trusted {
+		return nil
+	}
+
+	rejected, err := containsCertificate(path.Join(pkiDir, rejectedDir), cert)
+	if err != nil {
+		return err
+	}
+	if rejected {
+		return fmt.Errorf("the server certificate %s is rejected", thumbprint(cert))
+	}
+
+	if autoAccept {
+		klog.Infof("Trust the server certificate %s automatically", thumbprint(cert))
+		return saveCertificate(path.Join(pkiDir, trustedDir), cert)
+	}
+
+	if err := saveCertificate(path.Join(pkiDir, rejectedDir), cert); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("the server certificate %s is not trusted, move it from %s to %s to trust it",
+		thumbprint(cert), path.Join(pkiDir, rejectedDir), path.Join(pkiDir, trustedDir))
+}
+
+// containsCertificate returns true if the dir contains the certificate, the certificates in the dir can be
+// either DER or PEM encoded
+func containsCertificate(dir string, cert []byte) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read the PKI dir %s, %v", dir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return false, fmt.Errorf("failed to read the certificate %s, %v", entry.Name(), err)
+		}
+
+		if bytes.Equal(data, cert) {
+			return true, nil
+		}
+
+		for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+			if bytes.Equal(block.Bytes, cert) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func saveCertificate(dir string, cert []byte) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create the PKI dir %s, %v", dir, err)
+	}
+
+	file := path.Join(dir, fmt.Sprintf("%s.der", thumbprint(cert)))
+	if err := os.WriteFile(file, cert, 0o600); err != nil {
+		return fmt.Errorf("failed to save the certificate %s, %v", file, err)
+	}
+
+	return nil
+}
+
+func thumbprint(cert []byte) string {
+	sum := sha1.Sum(cert)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,461 @@
+package opcua
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gopcua/opcua"
+	"github.com/gopcua/opcua/ua"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+)
+
+const (
+	minReconnectInterval = 1 * time.Second
+	maxReconnectInterval = 2 * time.Minute
+
+	// resyncInterval is the interval to retry monitoring the devices that are failed to monitor
+	resyncInterval = 30 * time.Second
+)
+
+// session is the connection to an opcua server that is shared by the devices on the same endpoint, the devices
+// with the same publishing interval share one subscription. The client handles of the monitored items are
+// unique in the session, so the notifications are routed back to their devices with the client handles.
+type session struct {
+	sync.Mutex
+	driver   *OPCUADriver
+	endpoint string
+	client   *opcua.Client
+	devices  map[string]*sessionDevice
+	// stale is the monitored devices that are removed or changed, their monitored items are deleted by the
+	// session goroutine
+	stale      []*sessionDevice
+	handles    map[uint32]*request
+	nextHandle uint32
+
+	// subscriptions are keyed by the publishing interval, they are only used by the session goroutine
+	subscriptions map[time.Duration]*subscription
+
+	changed    chan struct{}
+	cancelFunc context.CancelFunc
+	done       chan struct{}
+}
+
+type sessionDevice struct {
+	config    v1alpha1.DeviceConfig
+	subConfig SubscriptionConfig
+	interval  time.Duration
+
+	// the subscription, monitored item ids and client handles of the device after it is monitored
+	monitored bool
+	sub       *subscription
+	itemIDs   []uint32
+	handles   []uint32
+}
+
+type subscription struct {
+	sub        *opcua.Subscription
+	items      int
+	cancelFunc context.CancelFunc
+}
+
+func newSession(driver *OPCUADriver, endpoint string) *session {
+	return &session{
+		driver:        driver,
+		endpoint:      endpoint,
+		devices:       make(map[string]*sessionDevice),
+		handles:       make(map[uint32]*request),
+		subscriptions: make(map[time.Duration]*subscription),
+		changed:       make(chan struct{}, 1),
+	}
+}
+
+func (s *session) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancelFunc = cancel
+	s.done = make(chan struct{})
+	go s.run(ctx)
+}
+
+// stop closes the session, it waits for the session goroutine to exit if wait is true
+func (s *session) stop(wait bool) {
+	s.cancelFunc()
+	if wait {
+		<-s.done
+	}
+}
+
+// addDevice adds or updates a device in the session, the device is monitored by the session goroutine
+func (s *session) addDevice(config v1alpha1.DeviceConfig, subConfig SubscriptionConfig, interval time.Duration) {
+	s.Lock()
+	if last, ok := s.devices[config.Name]; ok {
+		s.release(last)
+	}
+	s.devices[config.Name] = &sessionDevice{
+		config:    config,
+		subConfig: subConfig,
+		interval:  interval,
+	}
+	s.Unlock()
+
+	s.notify()
+}
+
+// removeDevice removes a device from the session and returns the number of the remaining devices
+func (s *session) removeDevice(deviceName string) int {
+	s.Lock()
+	defer s.Unlock()
+
+	if last, ok := s.devices[deviceName]; ok {
+		s.release(last)
+		delete(s.devices, deviceName)
+	}
+
+	s.notify()
+	return len(s.devices)
+}
+
+func (s *session) getClient() *opcua.Client {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.client
+}
+
+func (s *session) setClient(client *opcua.Client) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.client = client
+}
+
+func (s *session) notify() {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+// release stops routing the notifications to the device, the caller must hold the lock
+func (s *session) release(device *sessionDevice) {
+	for _, handle := range device.handles {
+		delete(s.handles, handle)
+	}
+
+	if device.monitored && device.sub != nil {
+		s.stale = append(s.stale, device)
+	}
+}
+
+func (s *session) findRequest(handle uint32) *request {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.handles[handle]
+}
+
+// isCurrent returns true if the device is not changed or removed
+func (s *session) isCurrent(device *sessionDevice) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.devices[device.config.Name] == device
+}
+
+func (s *session) deviceNames() []string {
+	s.Lock()
+	defer s.Unlock()
+
+	names := []string{}
+	for name := range s.devices {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (s *session) recordError(err error) {
+	for _, name := range s.deviceNames() {
+		s.driver.states.RecordError(name, err)
+	}
+}
+
+func (s *session) run(ctx context.Context) {
+	defer close(s.done)
+
+	var client *opcua.Client
+	interval := minReconnectInterval
+	for {
+		var err error
+		client, err = s.driver.connect(ctx, s.endpoint)
+		if err == nil {
+			break
+		}
+
+		klog.Errorf("failed to connect to opcua server %s, retry after %s, %v", s.endpoint, interval, err)
+		s.recordError(err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		interval = interval * 2
+		if interval > maxReconnectInterval {
+			interval = maxReconnectInterval
+		}
+	}
+
+	// the client reconnects and recreates the subscriptions automatically once it is connected
+	klog.Infof("Connected to opcua server %s", s.endpoint)
+	s.setClient(client)
+	defer func() {
+		s.setClient(nil)
+		if err := client.CloseWithContext(context.Background()); err != nil {
+			klog.Errorf("failed to close the connection to opcua server %s, %v", s.endpoint, err)
+		}
+
+		for _, name := range s.deviceNames() {
+			s.driver.states.SetConnected(name, false)
+			s.driver.states.SetSubscribed(name, false)
+		}
+		klog.Infof("The connection to opcua server %s is closed", s.endpoint)
+	}()
+
+	ticker := time.NewTicker(resyncInterval)
+	defer ticker.Stop()
+	for {
+		s.reconcile(ctx, client)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.changed:
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconcile deletes the monitored items of the stale devices and monitors the new devices
+func (s *session) reconcile(ctx context.Context, client *opcua.Client) {
+	s.Lock()
+	stale := s.stale
+	s.stale = nil
+	pending := map[string]*sessionDevice{}
+	for name, device := range s.devices {
+		if !device.monitored {
+			pending[name] = device
+		}
+	}
+	s.Unlock()
+
+	for _, device := range stale {
+		s.unmonitor(ctx, device)
+	}
+
+	for name, device := range pending {
+		if err := s.monitor(ctx, client, device); err != nil {
+			klog.Errorf("failed to monitor device %s, %v", name, err)
+			s.driver.states.RecordError(name, err)
+			continue
+		}
+
+		if !s.isCurrent(device) {
+			continue
+		}
+
+		klog.Infof("The device %s is monitored on opcua server %s", name, s.endpoint)
+		s.driver.states.SetConnected(name, true)
+		s.driver.states.SetSubscribed(name, true)
+	}
+}
+
+func (s *session) monitor(ctx context.Context, client *opcua.Client, device *sessionDevice) error {
+	deviceName := device.config.Name
+
+	requests := []*request{}
+	for _, res := range device.config.Profile.DeviceResources {
+		req, err := toRequest(deviceName, res, device.subConfig)
+		if err != nil {
+			return err
+		}
+		requests = append(requests, req)
+	}
+
+	// register the client handles before the items are monitored, so the initial values are not dropped
+	s.Lock()
+	if s.devices[deviceName] != device {
+		// the device is changed or removed
+		s.Unlock()
+		return nil
+	}
+	handles := []uint32{}
+	for _, req := range requests {
+		s.nextHandle++
+		req.handle = s.nextHandle
+		s.handles[req.handle] = req
+		handles = append(handles, req.handle)
+	}
+	device.handles = handles
+	s.Unlock()
+
+	sub, itemIDs, err := s.createItems(ctx, client, device.interval, requests)
+	if err != nil {
+		s.Lock()
+		for _, handle := range handles {
+			delete(s.handles, handle)
+		}
+		device.handles = nil
+		s.Unlock()
+		return err
+	}
+
+	s.Lock()
+	device.monitored = true
+	device.sub = sub
+	device.itemIDs = itemIDs
+	if device.sub != nil && s.devices[deviceName] != device {
+		// the device is changed or removed during monitoring, its items are deleted in the next reconcile
+		s.stale = append(s.stale, device)
+		s.Unlock()
+		s.notify()
+		return nil
+	}
+	s.Unlock()
+
+	return nil
+}
+
+// createItems creates the monitored items of the requests in the subscription of the publishing interval
+func (s *session) createItems(ctx context.Context, client *opcua.Client, interval time.Duration,
+	requests []*request) (*subscription, []uint32, error) {
+	if len(requests) == 0 {
+		return nil, nil, nil
+	}
+
+	sub, err := s.subscribe(ctx, client, interval)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := []*ua.MonitoredItemCreateRequest{}
+	for _, req := range requests {
+		monitorReq, err := valueRequest(req)
+		if err != nil {
+			s.releaseSubscription(ctx, interval, sub)
+			return nil, nil, err
+		}
+		items = append(items, monitorReq)
+	}
+
+	resp, err := sub.sub.MonitorWithContext(ctx, ua.TimestampsToReturnBoth, items...)
+	if err != nil {
+		s.releaseSubscription(ctx, interval, sub)
+		return nil, nil, err
+	}
+
+	itemIDs := []uint32{}
+	for i, result := range resp.Results {
+		if result.StatusCode == ua.StatusOK {
+			itemIDs = append(itemIDs, result.MonitoredItemID)
+			continue
+		}
+
+		err = fmt.Errorf("failed to monitor the resource %s, %v", requests[i].res.Name, result.StatusCode)
+	}
+	sub.items += len(itemIDs)
+
+	if err != nil {
+		s.deleteItems(ctx, interval, sub, itemIDs)
+		return nil, nil, err
+	}
+
+	return sub, itemIDs, nil
+}
+
+func (s *session) unmonitor(ctx context.Context, device *sessionDevice) {
+	s.deleteItems(ctx, device.interval, device.sub, device.itemIDs)
+}
+
+func (s *session) deleteItems(ctx context.Context, interval time.Duration, sub *subscription, itemIDs []uint32) {
+	if len(itemIDs) != 0 {
+		if _, err := sub.sub.UnmonitorWithContext(ctx, itemIDs...); err != nil {
+			klog.Errorf("failed to delete the monitored items on opcua server %s, %v", s.endpoint, err)
+		}
+		sub.items -= len(itemIDs)
+	}
+
+	s.releaseSubscription(ctx, interval, sub)
+}
+
+// subscribe returns the subscription of the publishing interval, the subscription is created if it does not exist
+func (s *session) subscribe(ctx context.Context, client *opcua.Client, interval time.Duration) (*subscription, error) {
+	if sub, ok := s.subscriptions[interval]; ok {
+		return sub, nil
+	}
+
+	notifyCh := make(chan *opcua.PublishNotificationData)
+	sub, err := client.SubscribeWithContext(ctx, &opcua.SubscriptionParameters{Interval: interval}, notifyCh)
+	if err != nil {
+		return nil, err
+	}
+
+	klog.Infof("Created subscription with id %v on opcua server %s", sub.SubscriptionID, s.endpoint)
+
+	subCtx, cancel := context.WithCancel(ctx)
+	go s.dispatch(subCtx, notifyCh)
+
+	s.subscriptions[interval] = &subscription{sub: sub, cancelFunc: cancel}
+	return s.subscriptions[interval], nil
+}
+
+// releaseSubscription cancels the subscription if there is no monitored item in it
+func (s *session) releaseSubscription(ctx context.Context, interval time.Duration, sub *subscription) {
+	if sub.items > 0 {
+		return
+	}
+
+	if err := sub.sub.Cancel(ctx); err != nil {
+		klog.Errorf("failed to cancel the subscription %v on opcua server %s, %v", sub.sub.SubscriptionID, s.endpoint, err)
+	}
+	sub.cancelFunc()
+	delete(s.subscriptions, interval)
+}
+
+// dispatch routes the notifications of a subscription to their devices with the client handles
+func (s *session) dispatch(ctx context.Context, notifyCh chan *opcua.PublishNotificationData) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case res := <-notifyCh:
+			if res.Error != nil {
+				klog.Errorf("failed to receive the notification from opcua server %s, %v", s.endpoint, res.Error)
+				s.recordError(res.Error)
+				continue
+			}
+
+			switch x := res.Value.(type) {
+			case *ua.DataChangeNotification:
+				for _, item := range x.MonitoredItems {
+					data := item.Value.Value.Value()
+					klog.V(4).Infof("MonitoredItem with client handle %v = %v", item.ClientHandle, data)
+
+					req := s.findRequest(item.ClientHandle)
+					if req == nil {
+						continue
+					}
+
+					s.driver.publish(req.deviceName, req.res, data)
+				}
+			case *ua.EventNotificationList:
+				// do nothing
+			default:
+				klog.Infof("unknown publish result: %T", res.Value)
+			}
+		}
+	}
+}
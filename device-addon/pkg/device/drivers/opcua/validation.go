@@ -0,0 +1,128 @@
+package opcua
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gopcua/opcua/ua"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+var authModes = []string{AuthModeAnonymous, AuthModeUsername, AuthModeCertificate}
+
+var deadbandTypes = []string{DeadbandTypeNone, DeadbandTypeAbsolute, DeadbandTypePercent}
+
+// ValidateConfig validates the opcua driver properties
+func ValidateConfig(properties map[string]interface{}, fldPath *field.Path) field.ErrorList {
+	config := &Config{}
+	if err := util.ToConfigObj(properties, config); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, field.OmitValueType{},
+			fmt.Sprintf("failed to parse the opcua driver config, %v", err))}
+	}
+
+	errs := field.ErrorList{}
+	switch config.AuthMode {
+	case "", AuthModeAnonymous, AuthModeUsername, AuthModeCertificate:
+	default:
+		errs = append(errs, field.NotSupported(fldPath.Child("authMode"), config.AuthMode, authModes))
+	}
+
+	errs = append(errs, validateSubscriptionConfig(config.SubscriptionConfig, fldPath)...)
+
+	if config.Discovery != nil {
+		for i, server := range config.Discovery.Servers {
+			serverPath := fldPath.Child("discovery", "servers").Index(i)
+			if len(server.DeviceName) == 0 {
+				errs = append(errs, field.Required(serverPath.Child("deviceName"), ""))
+			} else {
+				for _, msg := range validation.IsDNS1123Subdomain(server.DeviceName) {
+					errs = append(errs, field.Invalid(serverPath.Child("deviceName"), server.DeviceName, msg))
+				}
+			}
+
+			if len(server.Endpoint) == 0 {
+				errs = append(errs, field.Required(serverPath.Child(Endpoint), ""))
+			}
+
+			if len(server.RootNodeID) != 0 {
+				if _, err := ua.ParseNodeID(server.RootNodeID); err != nil {
+					errs = append(errs, field.Invalid(serverPath.Child("rootNodeId"), server.RootNodeID, err.Error()))
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// ValidateDevice validates the opcua protocol properties and resource attributes of a device, the endpoint is
+// required by the device and the nodeId is required by each device resource
+func ValidateDevice(device v1alpha1.DeviceConfig, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	propsPath := fldPath.Child("protocolProperties")
+	if endpoint, ok := device.ProtocolProperties.Data[Endpoint]; !ok || len(fmt.Sprintf("%v", endpoint)) == 0 {
+		errs = append(errs, field.Required(propsPath.Child(Endpoint), "the endpoint of the opcua server is required"))
+	}
+
+	subConfig := SubscriptionConfig{}
+	if err := util.ToConfigObj(device.ProtocolProperties.Data, &subConfig); err != nil {
+		errs = append(errs, field.Invalid(propsPath, field.OmitValueType{},
+			fmt.Sprintf("failed to parse the subscription parameters, %v", err)))
+	} else {
+		errs = append(errs, validateSubscriptionConfig(subConfig, propsPath)...)
+	}
+
+	for i, res := range device.Profile.DeviceResources {
+		attrsPath := fldPath.Child("profile", "deviceResources").Index(i).Child("attributes")
+
+		nodeID, ok := res.Attributes.Data[NODE].(string)
+		switch {
+		case !ok || len(nodeID) == 0:
+			errs = append(errs, field.Required(attrsPath.Child(NODE), "the node id of the resource is required"))
+		default:
+			if _, err := ua.ParseNodeID(nodeID); err != nil {
+				errs = append(errs, field.Invalid(attrsPath.Child(NODE), nodeID, err.Error()))
+			}
+		}
+
+		resSubConfig := SubscriptionConfig{}
+		if err := util.ToConfigObj(res.Attributes.Data, &resSubConfig); err != nil {
+			errs = append(errs, field.Invalid(attrsPath, field.OmitValueType{},
+				fmt.Sprintf("failed to parse the subscription parameters, %v", err)))
+			continue
+		}
+		errs = append(errs, validateSubscriptionConfig(resSubConfig, attrsPath)...)
+	}
+
+	return errs
+}
+
+func validateSubscriptionConfig(config SubscriptionConfig, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	if len(config.PublishingInterval) != 0 {
+		if _, err := time.ParseDuration(config.PublishingInterval); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("publishingInterval"), config.PublishingInterval, err.Error()))
+		}
+	}
+
+	if len(config.SamplingInterval) != 0 {
+		if _, err := time.ParseDuration(config.SamplingInterval); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("samplingInterval"), config.SamplingInterval, err.Error()))
+		}
+	}
+
+	switch config.DeadbandType {
+	case "", DeadbandTypeNone, DeadbandTypeAbsolute, DeadbandTypePercent:
+	default:
+		errs = append(errs, field.NotSupported(fldPath.Child("deadbandType"), config.DeadbandType, deadbandTypes))
+	}
+
+	return errs
+}
@@ -0,0 +1,50 @@
+package remote
+
+import (
+	"encoding/json"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// the grpc stubs of the remote driver service are generated from proto/driver.proto, see hack/proto_gen.sh
+
+// Reading is a device reading in the readings stream
+type Reading struct {
+	DeviceName   string      `json:"deviceName"`
+	ResourceName string      `json:"resourceName"`
+	Value        interface{} `json:"value"`
+}
+
+// DeviceState is the live state of a device that is returned by GetDeviceState
+type DeviceState struct {
+	Connected  bool   `json:"connected"`
+	Subscribed bool   `json:"subscribed"`
+	ErrorCount int64  `json:"errorCount"`
+	LastError  string `json:"lastError,omitempty"`
+}
+
+// ToStruct converts an object to a struct message with its json representation, the object should be a pointer
+// if it has the fields that are marshaled with the pointer receivers, e.g. the v1alpha1.Values
+func ToStruct(obj interface{}) (*structpb.Struct, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	return structpb.NewStruct(values)
+}
+
+// FromStruct converts a struct message to an object with its json representation
+func FromStruct(s *structpb.Struct, obj interface{}) error {
+	data, err := s.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, obj)
+}
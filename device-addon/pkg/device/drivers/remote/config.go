@@ -0,0 +1,33 @@
+package remote
+
+// RemoteProperty is the driver property of the remote driver config, a driver is a remote driver if it is set
+const RemoteProperty = "remote"
+
+const (
+	defaultTimeout = "10s"
+)
+
+// Config is the remote driver config, it is set with the "remote" property of the driver, the other properties
+// of the driver are passed to the remote driver when it is started
+type Config struct {
+	// Address is the address of the remote driver, it is unix:///<path> for a unix socket or <host>:<port> for
+	// a tcp address, e.g. a sidecar container
+	Address string `json:"address"`
+	// Timeout is the timeout of the calls to the remote driver, default is 10s
+	Timeout string `json:"timeout"`
+	// TLS is the TLS config of the connection to the remote driver, the connection is insecure if it is not set,
+	// e.g. the remote driver is served on a unix socket that is shared with a sidecar container
+	TLS *TLSConfig `json:"tls,omitempty"`
+}
+
+type TLSConfig struct {
+	// CAFile is used to verify the remote driver certificate, it is the ca.crt file in the credential dir of the
+	// driver if it is not set, the system CAs are used if there is no CA file
+	CAFile string `json:"caFile"`
+	// CertFile and KeyFile are the client certificate and key, they are the tls.crt and tls.key files in the
+	// credential dir of the driver if they are not set, the client certificate is not sent if there are no files
+	CertFile string `json:"certFile"`
+	KeyFile  string `json:"keyFile"`
+	// ServerName is used to verify the remote driver certificate, it is the host of the address if it is not set
+	ServerName string `json:"serverName"`
+}
@@ -0,0 +1,439 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/client"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/messagebuses"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+const (
+	minReconnectInterval = 1 * time.Second
+	maxReconnectInterval = 30 * time.Second
+)
+
+// credentialDirProperty is the driver property of the credential dir, it is set if the driver references a
+// credential Secret
+const credentialDirProperty = "credentialDir"
+
+// RemoteDriver is a device driver that runs out of the agent process, it calls the remote driver service to
+// manage the devices and publishes the readings from the readings stream to the message buses. If the connection
+// to the remote driver is lost, the driver is restarted and the devices are added again once it is reconnected.
+type RemoteDriver struct {
+	sync.Mutex
+	driverType    string
+	config        *Config
+	timeout       time.Duration
+	credentialDir string
+	properties    map[string]interface{}
+	msgBuses      []messagebuses.MessageBus
+	devices       map[string]v1alpha1.DeviceConfig
+	states        *util.DeviceStates
+
+	conn       *grpc.ClientConn
+	client     DriverClient
+	connected  bool
+	cancelFunc context.CancelFunc
+	done       chan struct{}
+}
+
+// IsRemote returns true if the driver config is a remote driver config
+func IsRemote(driverConfig util.ConfigProperties) bool {
+	_, ok := driverConfig[RemoteProperty]
+	return ok
+}
+
+func NewRemoteDriver(driverType string, driverConfig util.ConfigProperties,
+	msgBuses []messagebuses.MessageBus) (*RemoteDriver, error) {
+	config := &Config{}
+	if remoteConfig, ok := driverConfig[RemoteProperty].(map[string]interface{}); ok {
+		if err := util.ToConfigObj(remoteConfig, config); err != nil {
+			return nil, fmt.Errorf("failed to parse remote driver config, %v", err)
+		}
+	}
+
+	if len(config.Address) == 0 {
+		return nil, fmt.Errorf("the address is required by the remote driver %s", driverType)
+	}
+
+	if len(config.Timeout) == 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	timeout, err := time.ParseDuration(config.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeout %s, %v", config.Timeout, err)
+	}
+
+	properties := map[string]interface{}{}
+	for key, val := range driverConfig {
+		if key == RemoteProperty {
+			continue
+		}
+		properties[key] = val
+	}
+
+	credentialDir, _ := driverConfig[credentialDirProperty].(string)
+
+	return &RemoteDriver{
+		driverType:    driverType,
+		config:        config,
+		timeout:       timeout,
+		credentialDir: credentialDir,
+		properties:    properties,
+		msgBuses:      msgBuses,
+		devices:       make(map[string]v1alpha1.DeviceConfig),
+		states:        util.NewDeviceStates(),
+	}, nil
+}
+
+func (d *RemoteDriver) GetType() string {
+	return d.driverType
+}
+
+func (d *RemoteDriver) Start(ctx context.Context) error {
+	transportCredentials, err := d.transportCredentials()
+	if err != nil {
+		return err
+	}
+
+	// the connection is established lazily, so the agent is not blocked if the remote driver is not ready
+	conn, err := grpc.Dial(d.config.Address, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return fmt.Errorf("failed to connect to the remote driver %s, %v", d.config.Address, err)
+	}
+
+	d.conn = conn
+	d.client = NewDriverClient(conn)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	d.cancelFunc = cancel
+	d.done = make(chan struct{})
+	go func() {
+		defer close(d.done)
+		d.run(runCtx)
+	}()
+
+	return nil
+}
+
+func (d *RemoteDriver) Stop(ctx context.Context) {
+	if d.IsConnected() {
+		callCtx, cancel := context.WithTimeout(ctx, d.timeout)
+		if _, err := d.client.Stop(callCtx, &emptypb.Empty{}); err != nil {
+			klog.Errorf("failed to stop the remote driver %s, %v", d.driverType, err)
+		}
+		cancel()
+	}
+
+	if d.cancelFunc != nil {
+		d.cancelFunc()
+		<-d.done
+	}
+
+	if d.conn != nil {
+		if err := d.conn.Close(); err != nil {
+			klog.Errorf("failed to close the connection to the remote driver %s, %v", d.driverType, err)
+		}
+	}
+}
+
+// AddDevice adds the device to the remote driver, if the remote driver is not connected, the device is added
+// once it is connected. If the remote driver fails to add the device, the last device config is kept.
+func (d *RemoteDriver) AddDevice(device v1alpha1.DeviceConfig) error {
+	d.Lock()
+	last, ok := d.devices[device.Name]
+	if ok && equality.Semantic.DeepEqual(last, device) {
+		d.Unlock()
+		klog.Infof("The device %s already exists", device.Name)
+		return nil
+	}
+
+	if !d.connected {
+		d.devices[device.Name] = device
+		d.Unlock()
+		klog.Infof("The remote driver %s is not connected, add the device %s once it is connected", d.driverType, device.Name)
+		return nil
+	}
+	d.Unlock()
+
+	if err := d.addDevice(context.TODO(), device); err != nil {
+		return err
+	}
+
+	d.Lock()
+	d.devices[device.Name] = device
+	d.Unlock()
+	return nil
+}
+
+func (d *RemoteDriver) RemoveDevice(deviceName string) error {
+	d.Lock()
+	delete(d.devices, deviceName)
+	connected := d.connected
+	d.Unlock()
+
+	d.states.Remove(deviceName)
+
+	if !connected {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), d.timeout)
+	defer cancel()
+	if _, err := d.client.RemoveDevice(ctx, wrapperspb.String(deviceName)); err != nil {
+		return fmt.Errorf("failed to remove the device %s from the remote driver %s, %v", deviceName, d.driverType, err)
+	}
+
+	return nil
+}
+
+func (d *RemoteDriver) RunCommand(command util.Command) error {
+	if !d.IsConnected() {
+		return fmt.Errorf("the remote driver %s is not connected", d.driverType)
+	}
+
+	in, err := ToStruct(&command)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), d.timeout)
+	defer cancel()
+	if _, err := d.client.RunCommand(ctx, in); err != nil {
+		return fmt.Errorf("failed to run the command %s of device %s, %v", command.DeviceCommand, command.DeviceName, err)
+	}
+
+	return nil
+}
+
+// GetDeviceState returns the device state from the remote driver, the last seen time is the time of the last
+// reading that is received from the readings stream
+func (d *RemoteDriver) GetDeviceState(deviceName string) *util.DeviceState {
+	d.Lock()
+	_, ok := d.devices[deviceName]
+	d.Unlock()
+	if !ok {
+		return nil
+	}
+
+	state := d.states.Get(deviceName)
+	if state == nil {
+		state = &util.DeviceState{}
+	}
+
+	state.Connected = false
+	state.Subscribed = false
+	if !d.IsConnected() {
+		return state
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), d.timeout)
+	defer cancel()
+	out, err := d.client.GetDeviceState(ctx, wrapperspb.String(deviceName))
+	if err != nil {
+		klog.Errorf("failed to get the state of device %s from the remote driver %s, %v", deviceName, d.driverType, err)
+		return state
+	}
+
+	remoteState := &DeviceState{}
+	if err := FromStruct(out, remoteState); err != nil {
+		klog.Errorf("failed to parse the state of device %s, %v", deviceName, err)
+		return state
+	}
+
+	state.Connected = remoteState.Connected
+	state.Subscribed = remoteState.Subscribed
+	state.ErrorCount += remoteState.ErrorCount
+	if len(remoteState.LastError) != 0 {
+		state.LastError = remoteState.LastError
+	}
+	return state
+}
+
+// run starts the remote driver and receives the readings, it restarts the remote driver with a backoff if the
+// readings stream is broken
+func (d *RemoteDriver) run(ctx context.Context) {
+	interval := minReconnectInterval
+	for {
+		err := d.serve(ctx, func() { interval = minReconnectInterval })
+		if ctx.Err() != nil {
+			return
+		}
+
+		klog.Errorf("the remote driver %s is disconnected, reconnect after %s, %v", d.driverType, interval, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		interval = interval * 2
+		if interval > maxReconnectInterval {
+			interval = maxReconnectInterval
+		}
+	}
+}
+
+// serve starts the remote driver, adds the devices and receives the readings until the stream is broken, the
+// connected is called once the remote driver is started
+func (d *RemoteDriver) serve(ctx context.Context, connected func()) error {
+	properties, err := ToStruct(d.properties)
+	if err != nil {
+		return err
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	_, err = d.client.Start(callCtx, properties)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to start, %v", err)
+	}
+
+	stream, err := d.client.Readings(ctx, &emptypb.Empty{})
+	if err != nil {
+		return fmt.Errorf("failed to receive the readings, %v", err)
+	}
+
+	klog.Infof("The remote driver %s is connected", d.driverType)
+	connected()
+
+	// the devices that are added from now on are added by AddDevice
+	d.Lock()
+	d.connected = true
+	devices := make([]v1alpha1.DeviceConfig, 0, len(d.devices))
+	for _, device := range d.devices {
+		devices = append(devices, device)
+	}
+	d.Unlock()
+
+	defer func() {
+		d.Lock()
+		d.connected = false
+		d.Unlock()
+	}()
+
+	for _, device := range devices {
+		if err := d.addDevice(ctx, device); err != nil {
+			klog.Errorf("%v", err)
+			d.states.RecordError(device.Name, err)
+		}
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		reading := &Reading{}
+		if err := FromStruct(msg, reading); err != nil {
+			klog.Errorf("failed to parse the reading from the remote driver %s, %v", d.driverType, err)
+			continue
+		}
+
+		d.publish(reading)
+	}
+}
+
+func (d *RemoteDriver) addDevice(ctx context.Context, device v1alpha1.DeviceConfig) error {
+	in, err := ToStruct(&device)
+	if err != nil {
+		return err
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+	if _, err := d.client.AddDevice(callCtx, in); err != nil {
+		return fmt.Errorf("failed to add the device %s to the remote driver %s, %v", device.Name, d.driverType, err)
+	}
+
+	return nil
+}
+
+func (d *RemoteDriver) publish(reading *Reading) {
+	d.Lock()
+	device, ok := d.devices[reading.DeviceName]
+	d.Unlock()
+	if !ok {
+		klog.V(4).Infof("Ignore the reading of the unknown device %s", reading.DeviceName)
+		return
+	}
+
+	res := util.FindDeviceResource(reading.ResourceName, device.Profile.DeviceResources)
+	if res == nil {
+		klog.Warningf("The device %s attribute %s is unsupported", reading.DeviceName, reading.ResourceName)
+		return
+	}
+
+	result, err := util.NewResult(*res, reading.Value)
+	if err != nil {
+		klog.Errorf("The device %s attribute %s is unsupported, %v", reading.DeviceName, reading.ResourceName, err)
+		d.states.RecordError(reading.DeviceName, err)
+		return
+	}
+
+	d.states.RecordReading(reading.DeviceName)
+
+	for _, msgBus := range d.msgBuses {
+		if err := msgBus.ReceiveData(reading.DeviceName, *result); err != nil {
+			klog.Errorf("failed to publish the data of device %s, %v", reading.DeviceName, err)
+		}
+	}
+}
+
+// transportCredentials returns the TLS credentials if the TLS is configured, the credential files of the driver are
+// used if the files are not set
+func (d *RemoteDriver) transportCredentials() (credentials.TransportCredentials, error) {
+	if d.config.TLS == nil {
+		return insecure.NewCredentials(), nil
+	}
+
+	caFile := d.credentialFile(d.config.TLS.CAFile, client.CAFile)
+	certFile := d.credentialFile(d.config.TLS.CertFile, client.CertFile)
+	keyFile := d.credentialFile(d.config.TLS.KeyFile, client.KeyFile)
+	tlsConfig, err := util.NewTLSConfig(caFile, certFile, keyFile, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the TLS config of the remote driver %s, %v", d.driverType, err)
+	}
+	tlsConfig.ServerName = d.config.TLS.ServerName
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// credentialFile returns the file if it is set, otherwise the file in the credential dir is returned if it exists
+func (d *RemoteDriver) credentialFile(file, name string) string {
+	if len(file) != 0 || len(d.credentialDir) == 0 {
+		return file
+	}
+
+	if _, err := os.Stat(path.Join(d.credentialDir, name)); err != nil {
+		return ""
+	}
+
+	return path.Join(d.credentialDir, name)
+}
+
+// IsConnected returns true if the remote driver is started and its readings stream is connected
+func (d *RemoteDriver) IsConnected() bool {
+	d.Lock()
+	defer d.Unlock()
+
+	return d.connected
+}
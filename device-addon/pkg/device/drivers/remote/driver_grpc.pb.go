@@ -0,0 +1,395 @@
+// The protocol of the remote device drivers, a remote driver runs out of the agent process, e.g. in a sidecar
+// container, and serves this service on a unix socket or a tcp address. The agent calls the service to manage
+// the devices of the driver and receives the device readings from the readings stream.
+//
+// The messages are the json representations of the agent types in google.protobuf.Struct, the numbers in the
+// messages are float64, so the large integers may lose precision.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: driver.proto
+
+package remote
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Driver_Start_FullMethodName          = "/deviceaddon.driver.v1alpha1.Driver/Start"
+	Driver_Stop_FullMethodName           = "/deviceaddon.driver.v1alpha1.Driver/Stop"
+	Driver_AddDevice_FullMethodName      = "/deviceaddon.driver.v1alpha1.Driver/AddDevice"
+	Driver_RemoveDevice_FullMethodName   = "/deviceaddon.driver.v1alpha1.Driver/RemoveDevice"
+	Driver_RunCommand_FullMethodName     = "/deviceaddon.driver.v1alpha1.Driver/RunCommand"
+	Driver_GetDeviceState_FullMethodName = "/deviceaddon.driver.v1alpha1.Driver/GetDeviceState"
+	Driver_Readings_FullMethodName       = "/deviceaddon.driver.v1alpha1.Driver/Readings"
+)
+
+// DriverClient is the client API for Driver service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DriverClient interface {
+	// Start starts the driver with the driver properties, it is called again after the agent reconnects to the
+	// driver, so it must be idempotent.
+	Start(ctx context.Context, in *structpb.Struct, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Stop stops the driver.
+	Stop(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// AddDevice adds or updates a device, the request is the device config, e.g.
+	// {"name": "...", "driverType": "...", "protocolProperties": {...}, "profile": {"deviceResources": [...]}}.
+	// The devices are added again after the agent reconnects to the driver, so it must be idempotent.
+	AddDevice(ctx context.Context, in *structpb.Struct, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// RemoveDevice removes a device by its name.
+	RemoveDevice(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// RunCommand writes the command values to a device, the request is the command, e.g.
+	// {"deviceName": "...", "deviceCommand": "...", "attributes": {"<resource-name>": <value>}}.
+	RunCommand(ctx context.Context, in *structpb.Struct, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetDeviceState returns the live state of a device by its name, the response is
+	// {"connected": true, "subscribed": true, "errorCount": 0, "lastError": ""}.
+	GetDeviceState(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*structpb.Struct, error)
+	// Readings streams the device readings, each reading is
+	// {"deviceName": "...", "resourceName": "...", "value": <value>}, the binary values are base64 strings.
+	Readings(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (Driver_ReadingsClient, error)
+}
+
+type driverClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDriverClient(cc grpc.ClientConnInterface) DriverClient {
+	return &driverClient{cc}
+}
+
+func (c *driverClient) Start(ctx context.Context, in *structpb.Struct, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Driver_Start_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverClient) Stop(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Driver_Stop_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverClient) AddDevice(ctx context.Context, in *structpb.Struct, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Driver_AddDevice_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverClient) RemoveDevice(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Driver_RemoveDevice_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverClient) RunCommand(ctx context.Context, in *structpb.Struct, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Driver_RunCommand_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverClient) GetDeviceState(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*structpb.Struct, error) {
+	out := new(structpb.Struct)
+	err := c.cc.Invoke(ctx, Driver_GetDeviceState_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverClient) Readings(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (Driver_ReadingsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Driver_ServiceDesc.Streams[0], Driver_Readings_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &driverReadingsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Driver_ReadingsClient interface {
+	Recv() (*structpb.Struct, error)
+	grpc.ClientStream
+}
+
+type driverReadingsClient struct {
+	grpc.ClientStream
+}
+
+func (x *driverReadingsClient) Recv() (*structpb.Struct, error) {
+	m := new(structpb.Struct)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DriverServer is the server API for Driver service.
+// All implementations must embed UnimplementedDriverServer
+// for forward compatibility
+type DriverServer interface {
+	// Start starts the driver with the driver properties, it is called again after the agent reconnects to the
+	// driver, so it must be idempotent.
+	Start(context.Context, *structpb.Struct) (*emptypb.Empty, error)
+	// Stop stops the driver.
+	Stop(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	// AddDevice adds or updates a device, the request is the device config, e.g.
+	// {"name": "...", "driverType": "...", "protocolProperties": {...}, "profile": {"deviceResources": [...]}}.
+	// The devices are added again after the agent reconnects to the driver, so it must be idempotent.
+	AddDevice(context.Context, *structpb.Struct) (*emptypb.Empty, error)
+	// RemoveDevice removes a device by its name.
+	RemoveDevice(context.Context, *wrapperspb.StringValue) (*emptypb.Empty, error)
+	// RunCommand writes the command values to a device, the request is the command, e.g.
+	// {"deviceName": "...", "deviceCommand": "...", "attributes": {"<resource-name>": <value>}}.
+	RunCommand(context.Context, *structpb.Struct) (*emptypb.Empty, error)
+	// GetDeviceState returns the live state of a device by its name, the response is
+	// {"connected": true, "subscribed": true, "errorCount": 0, "lastError": ""}.
+	GetDeviceState(context.Context, *wrapperspb.StringValue) (*structpb.Struct, error)
+	// Readings streams the device readings, each reading is
+	// {"deviceName": "...", "resourceName": "...", "value": <value>}, the binary values are base64 strings.
+	Readings(*emptypb.Empty, Driver_ReadingsServer) error
+	mustEmbedUnimplementedDriverServer()
+}
+
+// UnimplementedDriverServer must be embedded to have forward compatible implementations.
+type UnimplementedDriverServer struct {
+}
+
+func (UnimplementedDriverServer) Start(context.Context, *structpb.Struct) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Start not implemented")
+}
+func (UnimplementedDriverServer) Stop(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
+}
+func (UnimplementedDriverServer) AddDevice(context.Context, *structpb.Struct) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddDevice not implemented")
+}
+func (UnimplementedDriverServer) RemoveDevice(context.Context, *wrapperspb.StringValue) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveDevice not implemented")
+}
+func (UnimplementedDriverServer) RunCommand(context.Context, *structpb.Struct) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunCommand not implemented")
+}
+func (UnimplementedDriverServer) GetDeviceState(context.Context, *wrapperspb.StringValue) (*structpb.Struct, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeviceState not implemented")
+}
+func (UnimplementedDriverServer) Readings(*emptypb.Empty, Driver_ReadingsServer) error {
+	return status.Errorf(codes.Unimplemented, "method Readings not implemented")
+}
+func (UnimplementedDriverServer) mustEmbedUnimplementedDriverServer() {}
+
+// UnsafeDriverServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DriverServer will
+// result in compilation errors.
+type UnsafeDriverServer interface {
+	mustEmbedUnimplementedDriverServer()
+}
+
+func RegisterDriverServer(s grpc.ServiceRegistrar, srv DriverServer) {
+	s.RegisterService(&Driver_ServiceDesc, srv)
+}
+
+func _Driver_Start_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(structpb.Struct)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServer).Start(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Driver_Start_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServer).Start(ctx, req.(*structpb.Struct))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Driver_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServer).Stop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Driver_Stop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServer).Stop(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Driver_AddDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(structpb.Struct)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServer).AddDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Driver_AddDevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServer).AddDevice(ctx, req.(*structpb.Struct))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Driver_RemoveDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(wrapperspb.StringValue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServer).RemoveDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Driver_RemoveDevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServer).RemoveDevice(ctx, req.(*wrapperspb.StringValue))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Driver_RunCommand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(structpb.Struct)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServer).RunCommand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Driver_RunCommand_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServer).RunCommand(ctx, req.(*structpb.Struct))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Driver_GetDeviceState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(wrapperspb.StringValue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServer).GetDeviceState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Driver_GetDeviceState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServer).GetDeviceState(ctx, req.(*wrapperspb.StringValue))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Driver_Readings_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DriverServer).Readings(m, &driverReadingsServer{stream})
+}
+
+type Driver_ReadingsServer interface {
+	Send(*structpb.Struct) error
+	grpc.ServerStream
+}
+
+type driverReadingsServer struct {
+	grpc.ServerStream
+}
+
+func (x *driverReadingsServer) Send(m *structpb.Struct) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Driver_ServiceDesc is the grpc.ServiceDesc for Driver service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Driver_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "deviceaddon.driver.v1alpha1.Driver",
+	HandlerType: (*DriverServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Start",
+			Handler:    _Driver_Start_Handler,
+		},
+		{
+			MethodName: "Stop",
+			Handler:    _Driver_Stop_Handler,
+		},
+		{
+			MethodName: "AddDevice",
+			Handler:    _Driver_AddDevice_Handler,
+		},
+		{
+			MethodName: "RemoveDevice",
+			Handler:    _Driver_RemoveDevice_Handler,
+		},
+		{
+			MethodName: "RunCommand",
+			Handler:    _Driver_RunCommand_Handler,
+		},
+		{
+			MethodName: "GetDeviceState",
+			Handler:    _Driver_GetDeviceState_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Readings",
+			Handler:       _Driver_Readings_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "driver.proto",
+}
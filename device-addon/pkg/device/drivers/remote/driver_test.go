@@ -0,0 +1,192 @@
+package remote_test
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	certutil "k8s.io/client-go/util/cert"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers/remote"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers/remote/fake"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/messagebuses"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+	testingutil "open-cluster-management-io/addon-contrib/device-addon/pkg/device/util/testing"
+)
+
+// rejectingDriver is a fake driver that rejects the devices with the name prefix "rejected"
+type rejectingDriver struct {
+	*fake.FakeDriver
+}
+
+func (d *rejectingDriver) AddDevice(ctx context.Context, in *structpb.Struct) (*emptypb.Empty, error) {
+	if strings.HasPrefix(fmt.Sprintf("%v", in.AsMap()["name"]), "rejected") {
+		return nil, fmt.Errorf("the device is rejected")
+	}
+	return d.FakeDriver.AddDevice(ctx, in)
+}
+
+// newTestDevice returns a device of the fake driver that has a counter
+func newTestDevice(name string) v1alpha1.DeviceConfig {
+	return testingutil.NewDevice(name, "fake", nil,
+		testingutil.NewResource("counter", "rw", util.ValueTypeInt64, nil),
+		testingutil.NewResource("reset", "w", util.ValueTypeBool, nil),
+	)
+}
+
+// startDriver serves the fake driver and starts a remote driver that connects to it
+func startDriver(t *testing.T, srv remote.DriverServer, remoteConfig map[string]interface{},
+	opts ...grpc.ServerOption) (*remote.RemoteDriver, *testingutil.FakeMsgBus) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	t.Cleanup(cancel)
+
+	go func() {
+		if err := remote.Serve(ctx, fmt.Sprintf("%s", remoteConfig["address"]), srv, opts...); err != nil {
+			t.Errorf("failed to serve the fake driver, %v", err)
+		}
+	}()
+
+	msgBus := testingutil.NewFakeMsgBus()
+	driver, err := remote.NewRemoteDriver("fake", map[string]interface{}{
+		remote.RemoteProperty: remoteConfig,
+		"interval":            "fast",
+	}, []messagebuses.MessageBus{msgBus})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := driver.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { driver.Stop(context.TODO()) })
+
+	return driver, msgBus
+}
+
+// waitForReading waits for a reading of the resource
+func waitForReading(t *testing.T, msgBus *testingutil.FakeMsgBus, resourceName string) util.Result {
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case result := <-msgBus.Results:
+			if result.Name == resourceName {
+				return result
+			}
+		case <-timeout:
+			t.Fatalf("the reading of %s is not received", resourceName)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	fakeDriver := fake.NewFakeDriver(50 * time.Millisecond)
+	driver, msgBus := startDriver(t, fakeDriver, map[string]interface{}{
+		"address": "unix://" + path.Join(t.TempDir(), "fake.sock"),
+	})
+
+	if err := driver.AddDevice(newTestDevice("device1")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the write-only resources are not read
+	result := waitForReading(t, msgBus, "counter")
+	if _, ok := result.Value.(int64); !ok {
+		t.Errorf("expected an int64 reading, but got %T", result.Value)
+	}
+
+	if properties := fakeDriver.Properties(); properties["interval"] != "fast" {
+		t.Errorf("expected the driver properties are passed to the remote driver, but got %v", properties)
+	}
+
+	state := driver.GetDeviceState("device1")
+	if state == nil || !state.Connected {
+		t.Errorf("expected the device is connected, but got %v", state)
+	}
+
+	if err := driver.RunCommand(util.Command{
+		DeviceName:    "device1",
+		DeviceCommand: "counter",
+		Attributes:    util.Attributes{"counter": 10},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	commands := fakeDriver.Commands()
+	if len(commands) != 1 || commands[0].DeviceCommand != "counter" {
+		t.Errorf("expected the command is received by the remote driver, but got %v", commands)
+	}
+
+	if err := driver.RemoveDevice("device1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := driver.RunCommand(util.Command{DeviceName: "device1", DeviceCommand: "counter"}); err == nil {
+		t.Errorf("expected the command of the removed device is rejected")
+	}
+}
+
+func TestAddDeviceFailure(t *testing.T) {
+	driver, msgBus := startDriver(t, &rejectingDriver{FakeDriver: fake.NewFakeDriver(50 * time.Millisecond)},
+		map[string]interface{}{"address": "unix://" + path.Join(t.TempDir(), "fake.sock")})
+
+	if err := driver.AddDevice(newTestDevice("device1")); err != nil {
+		t.Fatal(err)
+	}
+	// the driver is connected once the readings are received
+	waitForReading(t, msgBus, "counter")
+
+	if err := driver.AddDevice(newTestDevice("rejected")); err == nil {
+		t.Fatal("expected the device is rejected")
+	}
+
+	if state := driver.GetDeviceState("rejected"); state != nil {
+		t.Errorf("expected the rejected device is not kept, but got %v", state)
+	}
+}
+
+func TestTLS(t *testing.T) {
+	certData, keyData, err := certutil.GenerateSelfSignedCertKey("localhost", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(path.Join(dir, "ca.crt"), certData, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := tls.X509KeyPair(certData, keyData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	driver, msgBus := startDriver(t, fake.NewFakeDriver(50*time.Millisecond), map[string]interface{}{
+		"address": address,
+		"tls": map[string]interface{}{
+			"caFile":     path.Join(dir, "ca.crt"),
+			"serverName": "localhost",
+		},
+	}, grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{cert}})))
+
+	if err := driver.AddDevice(newTestDevice("device1")); err != nil {
+		t.Fatal(err)
+	}
+	waitForReading(t, msgBus, "counter")
+}
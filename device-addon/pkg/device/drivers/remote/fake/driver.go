@@ -0,0 +1,191 @@
+package fake
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers/remote"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// FakeDriver is a reference remote driver for tests, it generates a reading for each readable resource of the
+// devices in every interval, the numeric values are the counters of the readings, and it records the properties
+// and the commands that it receives.
+type FakeDriver struct {
+	remote.UnimplementedDriverServer
+	sync.Mutex
+	interval   time.Duration
+	properties map[string]interface{}
+	devices    map[string]v1alpha1.DeviceConfig
+	commands   []util.Command
+	counter    int64
+}
+
+func NewFakeDriver(interval time.Duration) *FakeDriver {
+	return &FakeDriver{
+		interval: interval,
+		devices:  make(map[string]v1alpha1.DeviceConfig),
+	}
+}
+
+func (d *FakeDriver) Start(ctx context.Context, in *structpb.Struct) (*emptypb.Empty, error) {
+	d.Lock()
+	defer d.Unlock()
+
+	d.properties = in.AsMap()
+	klog.Infof("The fake driver is started with %v", d.properties)
+	return &emptypb.Empty{}, nil
+}
+
+func (d *FakeDriver) Stop(ctx context.Context, in *emptypb.Empty) (*emptypb.Empty, error) {
+	klog.Infof("The fake driver is stopped")
+	return &emptypb.Empty{}, nil
+}
+
+func (d *FakeDriver) AddDevice(ctx context.Context, in *structpb.Struct) (*emptypb.Empty, error) {
+	device := v1alpha1.DeviceConfig{}
+	if err := remote.FromStruct(in, &device); err != nil {
+		return nil, err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	klog.Infof("Add the device %s to the fake driver", device.Name)
+	d.devices[device.Name] = device
+	return &emptypb.Empty{}, nil
+}
+
+func (d *FakeDriver) RemoveDevice(ctx context.Context, in *wrapperspb.StringValue) (*emptypb.Empty, error) {
+	d.Lock()
+	defer d.Unlock()
+
+	klog.Infof("Remove the device %s from the fake driver", in.GetValue())
+	delete(d.devices, in.GetValue())
+	return &emptypb.Empty{}, nil
+}
+
+func (d *FakeDriver) RunCommand(ctx context.Context, in *structpb.Struct) (*emptypb.Empty, error) {
+	command := util.Command{}
+	if err := remote.FromStruct(in, &command); err != nil {
+		return nil, err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	if _, ok := d.devices[command.DeviceName]; !ok {
+		return nil, fmt.Errorf("the device %s does not exist", command.DeviceName)
+	}
+
+	klog.Infof("Run the command %s of device %s", command.DeviceCommand, command.DeviceName)
+	d.commands = append(d.commands, command)
+	return &emptypb.Empty{}, nil
+}
+
+func (d *FakeDriver) GetDeviceState(ctx context.Context, in *wrapperspb.StringValue) (*structpb.Struct, error) {
+	d.Lock()
+	_, ok := d.devices[in.GetValue()]
+	d.Unlock()
+
+	return remote.ToStruct(&remote.DeviceState{Connected: ok, Subscribed: ok})
+}
+
+// Readings sends the readings of the devices in every interval until the stream is closed
+func (d *FakeDriver) Readings(in *emptypb.Empty, stream remote.Driver_ReadingsServer) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+			for _, reading := range d.nextReadings() {
+				msg, err := remote.ToStruct(&reading)
+				if err != nil {
+					return err
+				}
+
+				if err := stream.Send(msg); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// Properties returns the driver properties that the fake driver is started with
+func (d *FakeDriver) Properties() map[string]interface{} {
+	d.Lock()
+	defer d.Unlock()
+
+	return d.properties
+}
+
+// Commands returns the commands that the fake driver receives
+func (d *FakeDriver) Commands() []util.Command {
+	d.Lock()
+	defer d.Unlock()
+
+	return append([]util.Command{}, d.commands...)
+}
+
+func (d *FakeDriver) nextReadings() []remote.Reading {
+	d.Lock()
+	defer d.Unlock()
+
+	d.counter++
+	readings := []remote.Reading{}
+	for _, device := range d.devices {
+		for _, res := range device.Profile.DeviceResources {
+			if !util.IsReadable(res.Properties.ReadWrite) {
+				continue
+			}
+
+			value := fakeValue(res.Properties.ValueType, d.counter)
+			if value == nil {
+				continue
+			}
+
+			readings = append(readings, remote.Reading{
+				DeviceName:   device.Name,
+				ResourceName: res.Name,
+				Value:        value,
+			})
+		}
+	}
+
+	return readings
+}
+
+// fakeValue returns the fake value of the value type, nil is returned for the unsupported value types
+func fakeValue(valueType string, counter int64) interface{} {
+	switch valueType {
+	case util.ValueTypeBool:
+		return counter%2 == 0
+	case util.ValueTypeString:
+		return fmt.Sprintf("fake-%d", counter)
+	case util.ValueTypeBinary:
+		return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("fake-%d", counter)))
+	case util.ValueTypeFloat32, util.ValueTypeFloat64:
+		return float64(counter) + 0.5
+	case util.ValueTypeUint8, util.ValueTypeInt8:
+		return counter % 100
+	case util.ValueTypeUint16, util.ValueTypeUint32, util.ValueTypeUint64,
+		util.ValueTypeInt16, util.ValueTypeInt32, util.ValueTypeInt64:
+		return counter
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	"k8s.io/klog/v2"
+)
+
+const unixScheme = "unix://"
+
+// Serve serves the remote driver on the address until the context is done, the address is unix:///<path> for a
+// unix socket or <host>:<port> for a tcp address, the server options can set the TLS credentials
+func Serve(ctx context.Context, address string, srv DriverServer, opts ...grpc.ServerOption) error {
+	network := "tcp"
+	if strings.HasPrefix(address, unixScheme) {
+		network = "unix"
+		address = strings.TrimPrefix(address, unixScheme)
+		// remove the socket that is left by the last run
+		if err := os.Remove(address); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove the socket %s, %v", address, err)
+		}
+	}
+
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s, %v", address, err)
+	}
+
+	server := grpc.NewServer(opts...)
+	RegisterDriverServer(server, srv)
+
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+
+	klog.Infof("Serve the remote driver on %s", address)
+	return server.Serve(listener)
+}
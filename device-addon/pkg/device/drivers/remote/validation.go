@@ -0,0 +1,51 @@
+package remote
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// ValidateConfig validates the remote property of a remote driver, the other properties are validated by the
+// remote driver itself
+func ValidateConfig(properties map[string]interface{}, fldPath *field.Path) field.ErrorList {
+	remotePath := fldPath.Child(RemoteProperty)
+	remoteConfig, ok := properties[RemoteProperty].(map[string]interface{})
+	if !ok {
+		return field.ErrorList{field.Invalid(remotePath, field.OmitValueType{}, "the remote property must be an object")}
+	}
+
+	config := &Config{}
+	if err := util.ToConfigObj(remoteConfig, config); err != nil {
+		return field.ErrorList{field.Invalid(remotePath, field.OmitValueType{},
+			fmt.Sprintf("failed to parse the remote driver config, %v", err))}
+	}
+
+	errs := field.ErrorList{}
+	if len(config.Address) == 0 {
+		errs = append(errs, field.Required(remotePath.Child("address"), "the address of the remote driver is required"))
+	}
+
+	if len(config.Timeout) != 0 {
+		if _, err := time.ParseDuration(config.Timeout); err != nil {
+			errs = append(errs, field.Invalid(remotePath.Child("timeout"), config.Timeout, err.Error()))
+		}
+	}
+
+	if config.TLS != nil {
+		tlsPath := remotePath.Child("tls")
+		if strings.HasPrefix(config.Address, unixScheme) {
+			errs = append(errs, field.Invalid(tlsPath, field.OmitValueType{}, "the TLS is not supported with a unix socket"))
+		}
+
+		if (len(config.TLS.CertFile) == 0) != (len(config.TLS.KeyFile) == 0) {
+			errs = append(errs, field.Invalid(tlsPath, field.OmitValueType{}, "the certFile and keyFile must be set together"))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,87 @@
+package drivers
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers/http"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers/modbus"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers/mqtt"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers/opcua"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers/remote"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// schema validates the driver properties and the device protocol properties and resource attributes of a
+// build-in driver type
+type schema struct {
+	validateConfig func(properties map[string]interface{}, fldPath *field.Path) field.ErrorList
+	validateDevice func(device v1alpha1.DeviceConfig, fldPath *field.Path) field.ErrorList
+}
+
+var schemas = map[string]schema{
+	"mqtt":   {validateConfig: mqtt.ValidateConfig, validateDevice: mqtt.ValidateDevice},
+	"opcua":  {validateConfig: opcua.ValidateConfig, validateDevice: opcua.ValidateDevice},
+	"modbus": {validateConfig: modbus.ValidateConfig, validateDevice: modbus.ValidateDevice},
+	"http":   {validateConfig: http.ValidateConfig, validateDevice: http.ValidateDevice},
+}
+
+// BuildInTypes returns the build-in driver types
+func BuildInTypes() []string {
+	types := []string{}
+	for driverType := range schemas {
+		types = append(types, driverType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// IsBuildIn returns true if the driver type is a build-in driver type
+func IsBuildIn(driverType string) bool {
+	_, ok := schemas[driverType]
+	return ok
+}
+
+// ValidateDriver validates a driver config, a driver should be a build-in driver or a remote driver, the
+// properties of a remote driver are not validated except the remote property
+func ValidateDriver(config v1alpha1.DriverConfig, fldPath *field.Path) field.ErrorList {
+	if len(config.DriverType) == 0 {
+		return field.ErrorList{field.Required(fldPath.Child("type"), "")}
+	}
+
+	if remote.IsRemote(config.Properties.Data) {
+		return remote.ValidateConfig(config.Properties.Data, fldPath.Child("properties"))
+	}
+
+	s, ok := schemas[config.DriverType]
+	if !ok {
+		return field.ErrorList{field.NotSupported(fldPath.Child("type"), config.DriverType, BuildInTypes())}
+	}
+
+	return s.validateConfig(config.Properties.Data, fldPath.Child("properties"))
+}
+
+// ValidateDevice validates a device config with the supported value types and permissions, and the device is
+// validated with the schema of its driver type if it is a build-in driver type. The base is the referenced
+// profile of the device, the device resources of the device replace the ones of the base with the same names,
+// so only the device resources of the device are validated with the schema.
+func ValidateDevice(config v1alpha1.DeviceConfig, base v1alpha1.DeviceProfileSpec, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if len(config.Name) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("name"), ""))
+	}
+
+	if len(config.DriverType) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("driverType"), ""))
+	}
+
+	errs = append(errs, util.ValidateProfile(config.Profile, base, fldPath.Child("profile"))...)
+
+	if s, ok := schemas[config.DriverType]; ok {
+		errs = append(errs, s.validateDevice(config, fldPath)...)
+	}
+
+	return errs
+}
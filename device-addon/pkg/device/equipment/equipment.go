@@ -8,8 +8,11 @@ import (
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers"
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/messagebuses"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/metrics"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
 
 	"k8s.io/apimachinery/pkg/api/equality"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 )
 
@@ -18,43 +21,173 @@ type equipmentDriver struct {
 	config v1alpha1.DriverConfig
 }
 
+type equipmentMsgBus struct {
+	msgBus messagebuses.MessageBus
+	config v1alpha1.MessageBusConfig
+}
+
 type Equipment struct {
 	sync.Mutex
-	messageBuses map[string]messagebuses.MessageBus
+	messageBuses map[string]equipmentMsgBus
 	drivers      map[string]equipmentDriver
+	// devices records the added devices, they will be added to their driver again once the driver is reinstalled
+	devices map[string]v1alpha1.DeviceConfig
+	// rules processes the readings of the drivers before they are published to the message buses
+	rules *rulesStage
+	// reported records the last readings of the devices
+	reported *reportedValues
 }
 
 func NewEquipment() *Equipment {
+	reported := newReportedValues()
 	return &Equipment{
-		messageBuses: make(map[string]messagebuses.MessageBus),
+		messageBuses: make(map[string]equipmentMsgBus),
 		drivers:      make(map[string]equipmentDriver),
+		devices:      make(map[string]v1alpha1.DeviceConfig),
+		rules:        newRulesStage(reported),
+		reported:     reported,
 	}
 }
 
 func (e *Equipment) Start(ctx context.Context, configs []v1alpha1.MessageBusConfig) error {
+	return e.SetMessageBuses(ctx, configs)
+}
+
+// SetMessageBuses applies the message bus configs to the equipment, the message buses that are not changed are
+// kept running. A changed message bus is replaced once its new one is started, so the readings are still published
+// to the last one if the new one fails to start. If the new one cannot be started while the last one is running,
+// e.g. the build-in MQTT broker listens on the same port, the last one is stopped first, and it is started again if
+// the new one still fails to start.
+func (e *Equipment) SetMessageBuses(ctx context.Context, configs []v1alpha1.MessageBusConfig) error {
 	e.Lock()
 	defer e.Unlock()
 
+	desired := map[string]v1alpha1.MessageBusConfig{}
 	for _, c := range configs {
-		newMsgBus, err := messagebuses.Get(c)
-		if newMsgBus == nil {
+		desired[c.MessageBusType] = c
+	}
+
+	for busType := range e.messageBuses {
+		if _, ok := desired[busType]; ok {
 			continue
 		}
 
-		if err != nil {
-			return err
+		klog.Infof("Stop the message bus %s", busType)
+		e.stopMessageBus(busType)
+	}
+
+	errs := []error{}
+	for busType, c := range desired {
+		if err := e.replaceMessageBus(ctx, busType, c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// replaceMessageBus starts the message bus with the config and replaces the running one with it, the running one is
+// kept if the new one fails to start. If the message buses buffer the readings in the same dir, they share the
+// buffer, and the buffered readings are forwarded by the new one once the running one is stopped.
+func (e *Equipment) replaceMessageBus(ctx context.Context, busType string, config v1alpha1.MessageBusConfig) error {
+	last, running := e.messageBuses[busType]
+	if running && equality.Semantic.DeepEqual(last.config, config) {
+		return nil
+	}
+
+	newMsgBus, err := messagebuses.Get(config)
+	if err != nil {
+		return err
+	}
+
+	if newMsgBus == nil {
+		// the message bus is disabled
+		if running {
+			klog.Infof("Stop the message bus %s", busType)
+			e.stopMessageBus(busType)
+		}
+		return nil
+	}
+
+	err = e.startMessageBus(ctx, newMsgBus, config)
+	if err != nil && running {
+		klog.Warningf("The message bus %s is failed to start with the last one, stop the last one and retry, %v",
+			busType, err)
+		e.stopMessageBus(busType)
+		running = false
+
+		// a message bus is not restarted after it is stopped, so the message buses are recreated
+		if newMsgBus, err = messagebuses.Get(config); err == nil {
+			err = e.startMessageBus(ctx, newMsgBus, config)
 		}
 
-		if err := newMsgBus.Start(ctx); err != nil {
-			return fmt.Errorf("failed to start message bus %s, %v", c.MessageBusType, err)
+		if err != nil {
+			// restore the last one, it is replaced with the next update
+			if lastMsgBus, lastErr := messagebuses.Get(last.config); lastErr != nil {
+				klog.Errorf("failed to restore the message bus %s, %v", busType, lastErr)
+			} else if lastErr := e.startMessageBus(ctx, lastMsgBus, last.config); lastErr != nil {
+				klog.Errorf("failed to restore the message bus %s, %v", busType, lastErr)
+			} else {
+				e.messageBuses[busType] = equipmentMsgBus{msgBus: lastMsgBus, config: last.config}
+				e.resetMessageBuses()
+			}
 		}
+	}
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("The message bus %s is started", busType)
+	e.messageBuses[busType] = equipmentMsgBus{msgBus: newMsgBus, config: config}
+	e.resetMessageBuses()
 
-		e.messageBuses[c.MessageBusType] = newMsgBus
+	if running {
+		klog.Infof("Stop the last message bus %s", busType)
+		last.msgBus.Stop(context.TODO())
 	}
 
 	return nil
 }
 
+// startMessageBus starts the message bus and subscribes the commands from it
+func (e *Equipment) startMessageBus(ctx context.Context, msgBus messagebuses.MessageBus, config v1alpha1.MessageBusConfig) error {
+	if err := msgBus.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start message bus %s, %v", config.MessageBusType, err)
+	}
+
+	if err := msgBus.SendData(e.RunCommand); err != nil {
+		msgBus.Stop(context.TODO())
+		return fmt.Errorf("failed to subscribe commands from message bus %s, %v", config.MessageBusType, err)
+	}
+
+	return nil
+}
+
+// stopMessageBus removes the message bus from the rules stage and stops it
+func (e *Equipment) stopMessageBus(busType string) {
+	last, ok := e.messageBuses[busType]
+	if !ok {
+		return
+	}
+
+	delete(e.messageBuses, busType)
+	e.resetMessageBuses()
+	last.msgBus.Stop(context.TODO())
+}
+
+func (e *Equipment) resetMessageBuses() {
+	msgBuses := []messagebuses.MessageBus{}
+	for _, m := range e.messageBuses {
+		msgBuses = append(msgBuses, m.msgBus)
+	}
+	e.rules.setMessageBuses(msgBuses)
+}
+
+// SetRules replaces the processing rules of the readings, the installed drivers are not restarted
+func (e *Equipment) SetRules(rules []v1alpha1.ProcessingRule) error {
+	return e.rules.setRules(rules)
+}
+
 func (e *Equipment) Stop() {
 	e.Lock()
 	defer e.Unlock()
@@ -63,59 +196,126 @@ func (e *Equipment) Stop() {
 		d.driver.Stop(context.TODO())
 	}
 
+	e.rules.Stop(context.TODO())
+
 	for _, m := range e.messageBuses {
-		m.Stop(context.TODO())
+		m.msgBus.Stop(context.TODO())
 	}
 }
 
+// InstallDriver installs the driver with the config, the driver is reinstalled if its config is changed. The new
+// driver is started before the installed one is removed, so the installed one keeps running if the new one fails
+// to start. If the new one cannot be started while the installed one is running, e.g. the webhook of the http driver
+// listens on the same address, the installed one is stopped first, and it is started again if the new one still
+// fails to start.
 func (e *Equipment) InstallDriver(config v1alpha1.DriverConfig) error {
 	e.Lock()
 	defer e.Unlock()
 
-	msgBuses := []messagebuses.MessageBus{}
-	for _, m := range e.messageBuses {
-		msgBuses = append(msgBuses, m)
+	lastDriver, installed := e.drivers[config.DriverType]
+	if installed && equality.Semantic.DeepEqual(lastDriver.config, config) {
+		klog.Infof("The driver %s already exists", config.DriverType)
+		return nil
 	}
 
-	d := drivers.Get(config.DriverType, config.Properties.Data, msgBuses)
-	if d == nil {
-		return nil
+	d, err := e.newDriver(config)
+	if err != nil {
+		return err
 	}
 
-	if lastDriver, ok := e.drivers[config.DriverType]; ok {
-		if equality.Semantic.DeepEqual(lastDriver.config, config) {
-			klog.Infof("The driver %s already exists", config.DriverType)
-			return nil
+	err = startDriver(d, config)
+	if err != nil && installed {
+		klog.Warningf("The driver %s is failed to start with the installed one, stop the installed one and retry, %v",
+			config.DriverType, err)
+		e.stopDriver(config.DriverType)
+		installed = false
+
+		// a driver is not restarted after it is stopped, so the drivers are recreated
+		if d, err = e.newDriver(config); err == nil {
+			err = startDriver(d, config)
 		}
 
-		klog.Infof("Reinstall the driver %s already exists", config.DriverType)
-		d.Stop(context.TODO())
+		if err != nil {
+			// restore the installed one, it is reinstalled with the next install
+			if last, lastErr := e.newDriver(lastDriver.config); lastErr != nil {
+				klog.Errorf("failed to restore the driver %s, %v", config.DriverType, lastErr)
+			} else if lastErr := startDriver(last, lastDriver.config); lastErr != nil {
+				klog.Errorf("failed to restore the driver %s, %v", config.DriverType, lastErr)
+			} else {
+				e.addDriver(last, lastDriver.config)
+			}
+		}
+	}
+	if err != nil {
+		return err
 	}
 
-	if err := d.Start(context.TODO()); err != nil {
-		return fmt.Errorf("failed to start driver %s", config.DriverType)
+	// the devices are added to the new driver once the installed one is stopped, so the connections of the devices,
+	// e.g. a serial port, are released by the installed one
+	if installed {
+		klog.Infof("Reinstall the driver %s", config.DriverType)
+		e.stopDriver(config.DriverType)
 	}
 
 	klog.Infof("The driver %s is installed", config.DriverType)
+	e.addDriver(d, config)
+	return nil
+}
+
+// newDriver creates the driver with the config, the readings of the driver are processed by the rules
+func (e *Equipment) newDriver(config v1alpha1.DriverConfig) (drivers.Driver, error) {
+	d, err := drivers.Get(config.DriverType, config.Properties.Data, []messagebuses.MessageBus{e.rules})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create driver %s, %v", config.DriverType, err)
+	}
+	return d, nil
+}
+
+func startDriver(d drivers.Driver, config v1alpha1.DriverConfig) error {
+	if err := d.Start(context.TODO()); err != nil {
+		return fmt.Errorf("failed to start driver %s, %v", config.DriverType, err)
+	}
+	return nil
+}
+
+// addDriver records the started driver and adds the devices of the driver to it
+func (e *Equipment) addDriver(d drivers.Driver, config v1alpha1.DriverConfig) {
 	e.drivers[config.DriverType] = equipmentDriver{
 		driver: d,
 		config: config,
 	}
-	return nil
+
+	for _, device := range e.devices {
+		if device.DriverType != config.DriverType {
+			continue
+		}
+
+		if err := d.AddDevice(device); err != nil {
+			klog.Errorf("failed to add device %s to driver %s, %v", device.Name, config.DriverType, err)
+		}
+	}
+}
+
+func (e *Equipment) stopDriver(driverType string) {
+	d, ok := e.drivers[driverType]
+	if !ok {
+		return
+	}
+
+	d.driver.Stop(context.TODO())
+	delete(e.drivers, driverType)
 }
 
 func (e *Equipment) UnInstallDriver(config v1alpha1.DriverConfig) error {
 	e.Lock()
 	defer e.Unlock()
 
-	d, ok := e.drivers[config.DriverType]
-	if !ok {
+	if _, ok := e.drivers[config.DriverType]; !ok {
 		klog.Infof("The driver %s does not exist", config.DriverType)
 		return nil
 	}
 
-	d.driver.Stop(context.TODO())
-	delete(e.drivers, config.DriverType)
+	e.stopDriver(config.DriverType)
 	return nil
 }
 
@@ -129,3 +329,118 @@ func (e *Equipment) GetDriver(driverType string) drivers.Driver {
 	}
 	return d.driver
 }
+
+// AddDevice adds the device to its driver, the driver of the device must be installed
+func (e *Equipment) AddDevice(config v1alpha1.DeviceConfig) error {
+	e.Lock()
+	defer e.Unlock()
+
+	d, ok := e.drivers[config.DriverType]
+	if !ok {
+		return fmt.Errorf("the driver %s of device %s is not installed", config.DriverType, config.Name)
+	}
+
+	if last, ok := e.devices[config.Name]; ok && last.DriverType != config.DriverType {
+		if lastDriver, ok := e.drivers[last.DriverType]; ok {
+			if err := lastDriver.driver.RemoveDevice(config.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := d.driver.AddDevice(config); err != nil {
+		return err
+	}
+
+	e.devices[config.Name] = config
+	return nil
+}
+
+// RemoveDevice removes the device from its driver
+func (e *Equipment) RemoveDevice(deviceName string) error {
+	e.Lock()
+	defer e.Unlock()
+
+	device, ok := e.devices[deviceName]
+	if !ok {
+		return nil
+	}
+
+	if d, ok := e.drivers[device.DriverType]; ok {
+		if err := d.driver.RemoveDevice(deviceName); err != nil {
+			return err
+		}
+	}
+
+	delete(e.devices, deviceName)
+	e.rules.removeDevice(deviceName)
+	e.reported.remove(deviceName)
+	metrics.DeleteDevice(deviceName)
+	return nil
+}
+
+// RunCommand sends the command to the driver of the command device
+func (e *Equipment) RunCommand(command util.Command) error {
+	e.Lock()
+	device, ok := e.devices[command.DeviceName]
+	if !ok {
+		e.Unlock()
+		return fmt.Errorf("the device %s does not exist", command.DeviceName)
+	}
+	d, ok := e.drivers[device.DriverType]
+	e.Unlock()
+
+	if !ok {
+		return fmt.Errorf("the driver %s of device %s is not installed", device.DriverType, command.DeviceName)
+	}
+
+	return d.driver.RunCommand(command)
+}
+
+// GetDeviceState returns the live state of the device from its driver
+func (e *Equipment) GetDeviceState(deviceName string) *util.DeviceState {
+	e.Lock()
+	device, ok := e.devices[deviceName]
+	if !ok {
+		e.Unlock()
+		return nil
+	}
+	d, ok := e.drivers[device.DriverType]
+	e.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	return d.driver.GetDeviceState(deviceName)
+}
+
+// GetReportedValues returns the last readings of the device resources, they are keyed by the resource names
+func (e *Equipment) GetReportedValues(deviceName string) map[string]util.Result {
+	return e.reported.get(deviceName)
+}
+
+// DiscoverDevices returns the devices that are discovered by the installed drivers
+func (e *Equipment) DiscoverDevices(ctx context.Context) []v1alpha1.DeviceConfig {
+	e.Lock()
+	discoverers := map[string]drivers.Discoverer{}
+	for driverType, d := range e.drivers {
+		if discoverer, ok := d.driver.(drivers.Discoverer); ok {
+			discoverers[driverType] = discoverer
+		}
+	}
+	e.Unlock()
+
+	devices := []v1alpha1.DeviceConfig{}
+	for driverType, discoverer := range discoverers {
+		discovered, err := discoverer.Discover(ctx)
+		if err != nil {
+			klog.Errorf("failed to discover devices with driver %s, %v", driverType, err)
+			continue
+		}
+
+		devices = append(devices, discovered...)
+	}
+
+	return devices
+}
@@ -0,0 +1,162 @@
+package equipment
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+)
+
+func freeAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	return listener.Addr().String()
+}
+
+func newBrokerMsgBusConfig(address, dataTopic string) v1alpha1.MessageBusConfig {
+	return v1alpha1.MessageBusConfig{
+		MessageBusType: "mqtt",
+		Enabled:        true,
+		Properties: v1alpha1.Values{Data: map[string]interface{}{
+			"dataTopic": dataTopic,
+			"broker":    map[string]interface{}{"address": address},
+		}},
+	}
+}
+
+func isListening(address string) bool {
+	conn, err := net.DialTimeout("tcp", address, time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+func TestSetMessageBuses(t *testing.T) {
+	address := freeAddress(t)
+
+	e := NewEquipment()
+	defer e.Stop()
+
+	if err := e.Start(context.TODO(), []v1alpha1.MessageBusConfig{
+		newBrokerMsgBusConfig(address, "devices/+/data/+"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the new build-in broker listens on the same address, so it is started after the last one is stopped
+	changed := newBrokerMsgBusConfig(address, "devices/+/readings/+")
+	if err := e.SetMessageBuses(context.TODO(), []v1alpha1.MessageBusConfig{changed}); err != nil {
+		t.Fatal(err)
+	}
+	if e.messageBuses["mqtt"].config.Properties.Data["dataTopic"] != "devices/+/readings/+" {
+		t.Errorf("expected the message bus is replaced, but got %v", e.messageBuses["mqtt"].config)
+	}
+
+	// the last message bus is kept if the new one fails to start
+	occupied, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer occupied.Close()
+
+	if err := e.SetMessageBuses(context.TODO(), []v1alpha1.MessageBusConfig{
+		newBrokerMsgBusConfig(occupied.Addr().String(), "devices/+/data/+"),
+	}); err == nil {
+		t.Errorf("expected the new message bus fails to start")
+	}
+	if e.messageBuses["mqtt"].config.Properties.Data["dataTopic"] != "devices/+/readings/+" {
+		t.Errorf("expected the last message bus is kept, but got %v", e.messageBuses["mqtt"].config)
+	}
+	if !isListening(address) {
+		t.Errorf("expected the last build-in broker is listening on %s", address)
+	}
+
+	// the removed message bus is stopped
+	if err := e.SetMessageBuses(context.TODO(), []v1alpha1.MessageBusConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.messageBuses) != 0 {
+		t.Errorf("expected no message bus, but got %v", e.messageBuses)
+	}
+	if isListening(address) {
+		t.Errorf("expected the build-in broker is stopped")
+	}
+}
+
+func newWebhookDriverConfig(address string) v1alpha1.DriverConfig {
+	return v1alpha1.DriverConfig{
+		DriverType: "http",
+		Properties: v1alpha1.Values{Data: map[string]interface{}{
+			"webhook": map[string]interface{}{"address": address, "insecure": true},
+		}},
+	}
+}
+
+func TestInstallDriver(t *testing.T) {
+	address := freeAddress(t)
+
+	e := NewEquipment()
+	defer e.Stop()
+
+	if err := e.InstallDriver(newWebhookDriverConfig(address)); err != nil {
+		t.Fatal(err)
+	}
+	if !isListening(address) {
+		t.Errorf("expected the webhook is listening on %s", address)
+	}
+
+	// the new driver is started on the same address after the installed one is stopped
+	changed := newWebhookDriverConfig(address)
+	changed.Properties.Data["pollInterval"] = "10s"
+	if err := e.InstallDriver(changed); err != nil {
+		t.Fatal(err)
+	}
+	if e.drivers["http"].config.Properties.Data["pollInterval"] != "10s" {
+		t.Errorf("expected the driver is reinstalled, but got %v", e.drivers["http"].config)
+	}
+	if !isListening(address) {
+		t.Errorf("expected the webhook is listening on %s", address)
+	}
+
+	// the installed driver is kept if the new one fails to start
+	occupied, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer occupied.Close()
+
+	if err := e.InstallDriver(newWebhookDriverConfig(occupied.Addr().String())); err == nil {
+		t.Errorf("expected the new driver fails to start")
+	}
+	if e.drivers["http"].config.Properties.Data["pollInterval"] != "10s" {
+		t.Errorf("expected the installed driver is kept, but got %v", e.drivers["http"].config)
+	}
+	if !isListening(address) {
+		t.Errorf("expected the installed webhook is listening on %s", address)
+	}
+
+	// the driver is failed to create with an invalid config
+	invalid := newWebhookDriverConfig(address)
+	invalid.Properties.Data["maxBackoff"] = "1min"
+	if err := e.InstallDriver(invalid); err == nil {
+		t.Errorf("expected the invalid driver config is rejected")
+	}
+
+	if err := e.UnInstallDriver(changed); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.drivers) != 0 {
+		t.Errorf("expected no driver, but got %v", e.drivers)
+	}
+	if isListening(address) {
+		t.Errorf("expected the webhook is stopped")
+	}
+}
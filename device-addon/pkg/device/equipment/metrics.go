@@ -0,0 +1,117 @@
+package equipment
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/drivers"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/metrics"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+var (
+	readingsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "device", "readings_total"),
+		"Number of the readings that are received from a device.",
+		[]string{"driver", "device"}, nil)
+	rejectedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "device", "readings_rejected_total"),
+		"Number of the readings of a device that are rejected by the validation.",
+		[]string{"driver", "device"}, nil)
+	errorsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "device", "errors_total"),
+		"Number of the errors that occurred when the driver handles a device.",
+		[]string{"driver", "device"}, nil)
+	lastReadingDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "device", "last_reading_timestamp_seconds"),
+		"Unix time of the last reading that is received from a device.",
+		[]string{"driver", "device"}, nil)
+	connectedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "device", "connected"),
+		"Whether the driver is connected to a device, 1 is connected and 0 is disconnected.",
+		[]string{"driver", "device"}, nil)
+	driverConnectedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "driver", "connected"),
+		"Whether a driver is connected, e.g. to the MQTT broker or to the remote driver, 1 is connected and 0 is disconnected.",
+		[]string{"driver"}, nil)
+	driverConnectedDevicesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "driver", "connected_devices"),
+		"Number of the devices that a driver is connected to.",
+		[]string{"driver"}, nil)
+)
+
+// deviceCollector collects the metrics of the devices from the device states that are reported by their drivers
+type deviceCollector struct {
+	equipment *Equipment
+}
+
+// RegisterMetrics registers the metrics of the devices of the equipment to the default prometheus registry
+func (e *Equipment) RegisterMetrics() error {
+	return prometheus.Register(&deviceCollector{equipment: e})
+}
+
+func (c *deviceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- readingsDesc
+	ch <- rejectedDesc
+	ch <- errorsDesc
+	ch <- lastReadingDesc
+	ch <- connectedDesc
+	ch <- driverConnectedDesc
+	ch <- driverConnectedDevicesDesc
+}
+
+func (c *deviceCollector) Collect(ch chan<- prometheus.Metric) {
+	c.equipment.Lock()
+	devices := map[string]string{}
+	for name, device := range c.equipment.devices {
+		devices[name] = device.DriverType
+	}
+	installed := map[string]drivers.Driver{}
+	for driverType, d := range c.equipment.drivers {
+		installed[driverType] = d.driver
+	}
+	c.equipment.Unlock()
+
+	// the drivers without their own connection only report the number of the connected devices
+	connectedDevices := map[string]int{}
+	for driverType, d := range installed {
+		connectedDevices[driverType] = 0
+		if reporter, ok := d.(drivers.ConnectionReporter); ok {
+			ch <- prometheus.MustNewConstMetric(driverConnectedDesc, prometheus.GaugeValue,
+				boolToFloat(reporter.IsConnected()), driverType)
+		}
+	}
+
+	for name, driverType := range devices {
+		// the device that has never been reported by its driver is also collected, so it can be alerted
+		state := c.equipment.GetDeviceState(name)
+		if state == nil {
+			state = &util.DeviceState{}
+		}
+
+		if state.Connected {
+			if _, ok := connectedDevices[driverType]; ok {
+				connectedDevices[driverType]++
+			}
+		}
+
+		ch <- prometheus.MustNewConstMetric(readingsDesc, prometheus.CounterValue, float64(state.ReadingCount), driverType, name)
+		ch <- prometheus.MustNewConstMetric(rejectedDesc, prometheus.CounterValue, float64(state.RejectedCount), driverType, name)
+		ch <- prometheus.MustNewConstMetric(errorsDesc, prometheus.CounterValue, float64(state.ErrorCount), driverType, name)
+		ch <- prometheus.MustNewConstMetric(connectedDesc, prometheus.GaugeValue, boolToFloat(state.Connected), driverType, name)
+		if !state.LastSeen.IsZero() {
+			ch <- prometheus.MustNewConstMetric(lastReadingDesc, prometheus.GaugeValue,
+				float64(state.LastSeen.UnixNano())/1e9, driverType, name)
+		}
+	}
+
+	for driverType, count := range connectedDevices {
+		ch <- prometheus.MustNewConstMetric(driverConnectedDevicesDesc, prometheus.GaugeValue, float64(count), driverType)
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
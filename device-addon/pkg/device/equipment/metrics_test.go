@@ -0,0 +1,69 @@
+package equipment
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// fakeDriver reports the device states and its own connection if it is connectable
+type fakeDriver struct {
+	driverType string
+	states     map[string]*util.DeviceState
+}
+
+func (d *fakeDriver) Start(ctx context.Context) error                    { return nil }
+func (d *fakeDriver) Stop(ctx context.Context)                           {}
+func (d *fakeDriver) AddDevice(device v1alpha1.DeviceConfig) error       { return nil }
+func (d *fakeDriver) RemoveDevice(deviceName string) error               { return nil }
+func (d *fakeDriver) RunCommand(command util.Command) error              { return nil }
+func (d *fakeDriver) GetDeviceState(deviceName string) *util.DeviceState { return d.states[deviceName] }
+func (d *fakeDriver) GetType() string                                    { return d.driverType }
+
+type fakeConnectedDriver struct {
+	fakeDriver
+}
+
+func (d *fakeConnectedDriver) IsConnected() bool { return true }
+
+func TestCollect(t *testing.T) {
+	e := NewEquipment()
+	e.drivers["modbus"] = equipmentDriver{driver: &fakeDriver{
+		driverType: "modbus",
+		states: map[string]*util.DeviceState{
+			"plc1": {Connected: true, ReadingCount: 3},
+			"plc2": {Connected: false, ErrorCount: 1},
+		},
+	}}
+	e.drivers["mqtt"] = equipmentDriver{driver: &fakeConnectedDriver{fakeDriver{driverType: "mqtt"}}}
+	e.devices["plc1"] = v1alpha1.DeviceConfig{Name: "plc1", DriverType: "modbus"}
+	e.devices["plc2"] = v1alpha1.DeviceConfig{Name: "plc2", DriverType: "modbus"}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(&deviceCollector{equipment: e})
+
+	expected := `
+# HELP device_addon_driver_connected Whether a driver is connected, e.g. to the MQTT broker or to the remote driver, 1 is connected and 0 is disconnected.
+# TYPE device_addon_driver_connected gauge
+device_addon_driver_connected{driver="mqtt"} 1
+# HELP device_addon_driver_connected_devices Number of the devices that a driver is connected to.
+# TYPE device_addon_driver_connected_devices gauge
+device_addon_driver_connected_devices{driver="modbus"} 1
+device_addon_driver_connected_devices{driver="mqtt"} 0
+# HELP device_addon_device_readings_total Number of the readings that are received from a device.
+# TYPE device_addon_device_readings_total counter
+device_addon_device_readings_total{device="plc1",driver="modbus"} 3
+device_addon_device_readings_total{device="plc2",driver="modbus"} 0
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"device_addon_driver_connected", "device_addon_driver_connected_devices",
+		"device_addon_device_readings_total"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,101 @@
+package equipment
+
+import (
+	"context"
+	"sync"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/messagebuses"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/rules"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// rulesStage is the message bus of the drivers, it records the reported values of the readings, processes the
+// readings with the rules and then publishes them to the message buses of the equipment
+type rulesStage struct {
+	sync.RWMutex
+	processor *rules.Processor
+	msgBuses  []messagebuses.MessageBus
+	reported  *reportedValues
+}
+
+func newRulesStage(reported *reportedValues) *rulesStage {
+	s := &rulesStage{reported: reported}
+	// the readings are published directly without rules
+	s.processor, _ = rules.NewProcessor(nil, s.publish)
+	return s
+}
+
+func (s *rulesStage) Start(ctx context.Context) error {
+	return nil
+}
+
+func (s *rulesStage) Stop(ctx context.Context) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.processor.Stop()
+}
+
+func (s *rulesStage) ReceiveData(deviceName string, result util.Result) error {
+	s.reported.record(deviceName, result)
+
+	s.RLock()
+	processor := s.processor
+	s.RUnlock()
+
+	return processor.Process(deviceName, result)
+}
+
+// SendData is not supported, the equipment subscribes the commands from the message buses
+func (s *rulesStage) SendData(handler util.CommandHandler) error {
+	return nil
+}
+
+func (s *rulesStage) setMessageBuses(msgBuses []messagebuses.MessageBus) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.msgBuses = msgBuses
+}
+
+// setRules replaces the rules, the states of the last rules are dropped
+func (s *rulesStage) setRules(configs []v1alpha1.ProcessingRule) error {
+	processor, err := rules.NewProcessor(configs, s.publish)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	last := s.processor
+	s.processor = processor
+	s.Unlock()
+
+	last.Stop()
+	return nil
+}
+
+func (s *rulesStage) removeDevice(deviceName string) {
+	s.RLock()
+	processor := s.processor
+	s.RUnlock()
+
+	processor.RemoveDevice(deviceName)
+}
+
+// publish publishes a result to all of the message buses, the errors of the message buses are aggregated
+func (s *rulesStage) publish(deviceName string, result util.Result) error {
+	s.RLock()
+	msgBuses := s.msgBuses
+	s.RUnlock()
+
+	var errs []error
+	for _, msgBus := range msgBuses {
+		if err := msgBus.ReceiveData(deviceName, result); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
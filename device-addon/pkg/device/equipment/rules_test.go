@@ -0,0 +1,165 @@
+package equipment
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/messagebuses"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+	testingutil "open-cluster-management-io/addon-contrib/device-addon/pkg/device/util/testing"
+)
+
+func TestRulesStageReceiveData(t *testing.T) {
+	cases := []struct {
+		name          string
+		rules         []v1alpha1.ProcessingRule
+		results       []util.Result
+		busErr        error
+		expectedErr   bool
+		expectedCount int
+	}{
+		{
+			name:          "no rules",
+			results:       []util.Result{{Name: "temperature", Type: util.ValueTypeFloat64, Value: 20.0}},
+			expectedCount: 1,
+		},
+		{
+			name:        "message bus error",
+			results:     []util.Result{{Name: "temperature", Type: util.ValueTypeFloat64, Value: 20.0}},
+			busErr:      fmt.Errorf("the broker is unreachable"),
+			expectedErr: true,
+		},
+		{
+			name: "suppressed by deadband",
+			rules: []v1alpha1.ProcessingRule{
+				{Name: "deadband", Deadband: &v1alpha1.DeadbandRule{Absolute: 1}},
+			},
+			results: []util.Result{
+				{Name: "temperature", Type: util.ValueTypeFloat64, Value: 20.0},
+				{Name: "temperature", Type: util.ValueTypeFloat64, Value: 20.5},
+				{Name: "temperature", Type: util.ValueTypeFloat64, Value: 21.5},
+			},
+			expectedCount: 2,
+		},
+		{
+			name: "suppressed results are not failed",
+			rules: []v1alpha1.ProcessingRule{
+				{Name: "aggregate", Aggregate: &v1alpha1.AggregateRule{Window: "1h"}},
+			},
+			results:       []util.Result{{Name: "temperature", Type: util.ValueTypeFloat64, Value: 20.0}},
+			busErr:        fmt.Errorf("the broker is unreachable"),
+			expectedCount: 0,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			reported := newReportedValues()
+			stage := newRulesStage(reported)
+			defer stage.Stop(context.TODO())
+
+			msgBus := testingutil.NewFakeMsgBus()
+			msgBus.SetErr(c.busErr)
+			stage.setMessageBuses([]messagebuses.MessageBus{msgBus})
+			if err := stage.setRules(c.rules); err != nil {
+				t.Fatal(err)
+			}
+
+			var errs []error
+			for _, result := range c.results {
+				if err := stage.ReceiveData("sensor", result); err != nil {
+					errs = append(errs, err)
+				}
+			}
+
+			if c.expectedErr && len(errs) == 0 {
+				t.Errorf("expected the message bus error, but got nil")
+			}
+			if !c.expectedErr && len(errs) != 0 {
+				t.Errorf("expected no error, but got %v", errs)
+			}
+
+			if received := msgBus.Received(); len(received) != c.expectedCount {
+				t.Errorf("expected %d results, but got %v", c.expectedCount, received)
+			}
+
+			// the reported values are recorded before the rules
+			last := c.results[len(c.results)-1]
+			if reported.get("sensor")["temperature"].Value != last.Value {
+				t.Errorf("expected the reported value %v, but got %v", last.Value, reported.get("sensor"))
+			}
+		})
+	}
+}
+
+func TestRulesStageAggregate(t *testing.T) {
+	cases := []struct {
+		name     string
+		function string
+		results  []util.Result
+		expected util.Result
+	}{
+		{
+			name: "avg of integers",
+			results: []util.Result{
+				{Name: "counter", Type: util.ValueTypeInt16, Value: int16(1)},
+				{Name: "counter", Type: util.ValueTypeInt16, Value: int16(2)},
+			},
+			expected: util.Result{Name: "counter", Type: util.ValueTypeInt16, Value: int16(2)},
+		},
+		{
+			name: "avg of floats",
+			results: []util.Result{
+				{Name: "temperature", Type: util.ValueTypeFloat32, Value: float32(20)},
+				{Name: "temperature", Type: util.ValueTypeFloat32, Value: float32(21)},
+			},
+			expected: util.Result{Name: "temperature", Type: util.ValueTypeFloat32, Value: float32(20.5)},
+		},
+		{
+			name:     "max",
+			function: "max",
+			results: []util.Result{
+				{Name: "temperature", Type: util.ValueTypeFloat64, Value: 21.0},
+				{Name: "temperature", Type: util.ValueTypeFloat64, Value: 20.0},
+			},
+			expected: util.Result{Name: "temperature", Type: util.ValueTypeFloat64, Value: 21.0},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stage := newRulesStage(newReportedValues())
+			defer stage.Stop(context.TODO())
+
+			msgBus := testingutil.NewFakeMsgBus()
+			stage.setMessageBuses([]messagebuses.MessageBus{msgBus})
+			if err := stage.setRules([]v1alpha1.ProcessingRule{{
+				Name:      "aggregate",
+				Aggregate: &v1alpha1.AggregateRule{Window: "100ms", Function: c.function},
+			}}); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, result := range c.results {
+				if err := stage.ReceiveData("sensor", result); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			select {
+			case result := <-msgBus.Results:
+				if result.Name != c.expected.Name || result.Type != c.expected.Type || result.Value != c.expected.Value {
+					t.Errorf("expected %v, but got %v", c.expected, result)
+				}
+				if result.CreateTimestamp == 0 {
+					t.Errorf("expected the create timestamp is set")
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("the aggregate is not published")
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package equipment
+
+import (
+	"sync"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// reportedValues records the last readings of the device resources before they are processed by the rules, they
+// are the reported side of the device twin
+type reportedValues struct {
+	sync.RWMutex
+	values map[string]map[string]util.Result
+}
+
+func newReportedValues() *reportedValues {
+	return &reportedValues{
+		values: make(map[string]map[string]util.Result),
+	}
+}
+
+func (r *reportedValues) record(deviceName string, result util.Result) {
+	// the binary values are not reported
+	if result.Type == util.ValueTypeBinary {
+		return
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.values[deviceName]; !ok {
+		r.values[deviceName] = make(map[string]util.Result)
+	}
+	r.values[deviceName][result.Name] = result
+}
+
+func (r *reportedValues) get(deviceName string) map[string]util.Result {
+	r.RLock()
+	defer r.RUnlock()
+
+	values := make(map[string]util.Result, len(r.values[deviceName]))
+	for name, result := range r.values[deviceName] {
+		values[name] = result
+	}
+	return values
+}
+
+func (r *reportedValues) remove(deviceName string) {
+	r.Lock()
+	defer r.Unlock()
+
+	delete(r.values, deviceName)
+}
@@ -0,0 +1,232 @@
+package buffer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/metrics"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+const (
+	OverflowPolicyDropOldest = "dropOldest"
+	OverflowPolicyDropNewest = "dropNewest"
+)
+
+const (
+	defaultDir      = "/var/lib/device-addon/buffer"
+	defaultMaxItems = 10000
+
+	minRetryInterval = 1 * time.Second
+	maxRetryInterval = 30 * time.Second
+)
+
+// Config is the buffer configuration of a message bus, it is set with the "buffer" property of the message bus
+type Config struct {
+	// Dir is the directory to persist the buffered readings, the readings of a message bus are saved in
+	// the sub directory that is named with the message bus type, default is /var/lib/device-addon/buffer
+	Dir string `json:"dir"`
+	// MaxItems is the max number of the buffered readings, default is 10000
+	MaxItems int `json:"maxItems"`
+	// Retention is how long a reading is kept in the buffer, e.g. 24h, the readings are kept until they are
+	// delivered if it is empty
+	Retention string `json:"retention"`
+	// OverflowPolicy is the policy when the buffer is full: dropOldest (default) or dropNewest
+	OverflowPolicy string `json:"overflowPolicy"`
+}
+
+// MessageBus is the message bus that is wrapped by the buffer
+type MessageBus interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context)
+	ReceiveData(deviceName string, result util.Result) error
+	SendData(handler util.CommandHandler) error
+}
+
+// BufferedMsgBus puts a persistent queue between the drivers and a message bus, the readings from the drivers
+// are saved to the queue firstly, then they are forwarded to the message bus in order. If the message bus is
+// unreachable, the readings are kept in the queue and replayed once the message bus is recovered.
+type BufferedMsgBus struct {
+	busType    string
+	msgBus     MessageBus
+	queue      *queue
+	retention  time.Duration
+	cancelFunc context.CancelFunc
+	done       chan struct{}
+}
+
+func NewBufferedMsgBus(busType string, msgBus MessageBus, properties interface{}) (*BufferedMsgBus, error) {
+	config := &Config{}
+	if data, ok := properties.(map[string]interface{}); ok {
+		if err := util.ToConfigObj(data, config); err != nil {
+			return nil, fmt.Errorf("failed to parse the buffer config of message bus %s, %v", busType, err)
+		}
+	}
+
+	if len(config.Dir) == 0 {
+		config.Dir = defaultDir
+	}
+
+	if config.MaxItems == 0 {
+		config.MaxItems = defaultMaxItems
+	}
+
+	switch config.OverflowPolicy {
+	case "":
+		config.OverflowPolicy = OverflowPolicyDropOldest
+	case OverflowPolicyDropOldest, OverflowPolicyDropNewest:
+	default:
+		return nil, fmt.Errorf("unsupported overflow policy %s", config.OverflowPolicy)
+	}
+
+	var retention time.Duration
+	if len(config.Retention) != 0 {
+		var err error
+		retention, err = time.ParseDuration(config.Retention)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retention %s, %v", config.Retention, err)
+		}
+	}
+
+	q, err := openQueue(filepath.Join(config.Dir, busType), config.MaxItems, config.OverflowPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	klog.Infof("Buffer the readings of message bus %s in %s, %d readings are recovered", busType, q.dir, q.Len())
+	metrics.QueueDepth.WithLabelValues(busType).Set(float64(q.Len()))
+
+	return &BufferedMsgBus{
+		busType:   busType,
+		msgBus:    msgBus,
+		queue:     q,
+		retention: retention,
+	}, nil
+}
+
+func (b *BufferedMsgBus) Start(ctx context.Context) error {
+	if err := b.msgBus.Start(ctx); err != nil {
+		return err
+	}
+
+	forwardCtx, cancel := context.WithCancel(ctx)
+	b.cancelFunc = cancel
+	b.done = make(chan struct{})
+	go func() {
+		defer close(b.done)
+		b.forward(forwardCtx)
+	}()
+
+	return nil
+}
+
+func (b *BufferedMsgBus) Stop(ctx context.Context) {
+	if b.cancelFunc != nil {
+		b.cancelFunc()
+		<-b.done
+	}
+
+	b.msgBus.Stop(ctx)
+}
+
+// ReceiveData saves the reading to the queue, the reading will be forwarded to the message bus asynchronously
+func (b *BufferedMsgBus) ReceiveData(deviceName string, result util.Result) error {
+	// the value is saved with its json format, so it can be restored from the queue
+	result.Value = util.ToJSONValue(result)
+	dropped, err := b.queue.Push(item{
+		DeviceName: deviceName,
+		Result:     result,
+		EnqueuedAt: time.Now(),
+	})
+	if dropped {
+		metrics.QueueDropped.WithLabelValues(b.busType, "overflow").Inc()
+	}
+	metrics.QueueDepth.WithLabelValues(b.busType).Set(float64(b.queue.Len()))
+	if err != nil {
+		return fmt.Errorf("failed to buffer the reading %s of device %s, %v", result.Name, deviceName, err)
+	}
+
+	return nil
+}
+
+func (b *BufferedMsgBus) SendData(handler util.CommandHandler) error {
+	return b.msgBus.SendData(handler)
+}
+
+// forward sends the buffered readings to the message bus in order, a reading is removed from the queue only
+// after it is sent, if the sending fails, it is retried with a backoff.
+func (b *BufferedMsgBus) forward(ctx context.Context) {
+	// the message bus that is replaced by this one may still forward the readings of the same queue, they
+	// are forwarded by this one once the replaced one is stopped
+	if !b.queue.Acquire(ctx) {
+		return
+	}
+	defer b.queue.Release()
+
+	retryInterval := minRetryInterval
+	for {
+		i, err := b.queue.Peek()
+		if err != nil {
+			klog.Errorf("failed to read the buffer of message bus %s, %v", b.busType, err)
+			if !b.wait(ctx, retryInterval) {
+				return
+			}
+			continue
+		}
+
+		if i == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-b.queue.notify:
+			}
+			continue
+		}
+
+		if b.retention > 0 && time.Since(i.EnqueuedAt) > b.retention {
+			klog.V(4).Infof("Drop the expired reading %s of device %s", i.Result.Name, i.DeviceName)
+			metrics.QueueDropped.WithLabelValues(b.busType, "expired").Inc()
+			b.pop()
+			continue
+		}
+
+		if err := b.msgBus.ReceiveData(i.DeviceName, i.Result); err != nil {
+			metrics.PublishFailures.WithLabelValues(b.busType).Inc()
+			klog.Errorf("failed to send the reading %s of device %s to message bus %s, retry after %s, %v",
+				i.Result.Name, i.DeviceName, b.busType, retryInterval, err)
+			if !b.wait(ctx, retryInterval) {
+				return
+			}
+
+			retryInterval = retryInterval * 2
+			if retryInterval > maxRetryInterval {
+				retryInterval = maxRetryInterval
+			}
+			continue
+		}
+
+		retryInterval = minRetryInterval
+		b.pop()
+	}
+}
+
+func (b *BufferedMsgBus) pop() {
+	if err := b.queue.Pop(); err != nil {
+		klog.Errorf("failed to remove the reading from the buffer of message bus %s, %v", b.busType, err)
+	}
+	metrics.QueueDepth.WithLabelValues(b.busType).Set(float64(b.queue.Len()))
+}
+
+// wait returns false if the context is done before the interval is passed
+func (b *BufferedMsgBus) wait(ctx context.Context, interval time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(interval):
+		return true
+	}
+}
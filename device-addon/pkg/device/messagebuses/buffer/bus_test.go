@@ -0,0 +1,112 @@
+package buffer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+	testingutil "open-cluster-management-io/addon-contrib/device-addon/pkg/device/util/testing"
+)
+
+func waitFor(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("the condition is not met in 5s")
+}
+
+func TestReplaceBufferedMsgBus(t *testing.T) {
+	properties := map[string]interface{}{"dir": t.TempDir()}
+
+	oldBus := testingutil.NewFakeMsgBus()
+	oldBus.SetErr(fmt.Errorf("the message bus is unreachable"))
+	oldBuffered, err := NewBufferedMsgBus("mqtt", oldBus, properties)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := oldBuffered.Start(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := oldBuffered.ReceiveData("device1", util.Result{Name: "r1", Value: 1}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return len(oldBuffered.queue.consumer) == 1 })
+
+	// the replacement is started while the old one is running, they share the queue of the same dir
+	newBus := testingutil.NewFakeMsgBus()
+	newBuffered, err := NewBufferedMsgBus("mqtt", newBus, properties)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newBuffered.queue != oldBuffered.queue {
+		t.Fatalf("expected the queue is shared")
+	}
+	if err := newBuffered.Start(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	defer newBuffered.Stop(context.TODO())
+
+	if err := newBuffered.ReceiveData("device1", util.Result{Name: "r2", Value: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the readings are forwarded by the old one until it is stopped
+	time.Sleep(100 * time.Millisecond)
+	if len(newBus.Results) != 0 {
+		t.Errorf("expected no reading is sent by the new one, but got %v", newBus.Received())
+	}
+
+	oldBuffered.Stop(context.TODO())
+	waitFor(t, func() bool { return len(newBus.Results) == 2 })
+
+	sent := newBus.Received()
+	if sent[0].Name != "r1" || sent[1].Name != "r2" {
+		t.Errorf("expected the readings are sent in order, but got %v", sent)
+	}
+	if received := oldBus.Received(); len(received) != 0 {
+		t.Errorf("expected no reading is sent by the old one, but got %v", received)
+	}
+
+	// no reading is sent twice
+	time.Sleep(100 * time.Millisecond)
+	if received := newBus.Received(); len(received) != 0 {
+		t.Errorf("expected no more readings, but got %v", received)
+	}
+}
+
+func TestRetention(t *testing.T) {
+	msgBus := testingutil.NewFakeMsgBus()
+	buffered, err := NewBufferedMsgBus("mqtt", msgBus, map[string]interface{}{"dir": t.TempDir(), "retention": "1h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the reading that is buffered before the retention is dropped
+	expired := newItem("expired")
+	expired.EnqueuedAt = time.Now().Add(-2 * time.Hour)
+	if _, err := buffered.queue.Push(expired); err != nil {
+		t.Fatal(err)
+	}
+	if err := buffered.ReceiveData("device1", util.Result{Name: "fresh", Value: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := buffered.Start(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	defer buffered.Stop(context.TODO())
+
+	waitFor(t, func() bool { return buffered.queue.Len() == 0 })
+
+	received := msgBus.Received()
+	if len(received) != 1 || received[0].Name != "fresh" {
+		t.Errorf("expected only the fresh reading is sent, but got %v", received)
+	}
+}
@@ -0,0 +1,274 @@
+package buffer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+const itemFileSuffix = ".json"
+
+// ErrQueueFull is returned when the queue is full and its overflow policy is dropNewest
+var ErrQueueFull = errors.New("the queue is full")
+
+// item is a reading that is buffered in the queue
+type item struct {
+	DeviceName string      `json:"deviceName"`
+	Result     util.Result `json:"result"`
+	EnqueuedAt time.Time   `json:"enqueuedAt"`
+}
+
+var (
+	queuesLock sync.Mutex
+	// queues are the opened queues that are keyed by their dirs, a queue dir is opened once in the process, so a
+	// message bus and its replacement share the queue if they buffer the readings in the same dir
+	queues = map[string]*queue{}
+)
+
+// queue is a bounded FIFO queue that persists its items to a directory, each item is saved in one file that
+// is named with its sequence number, so the items can be recovered in order after the agent is restarted.
+type queue struct {
+	sync.Mutex
+	dir            string
+	maxItems       int
+	overflowPolicy string
+	// head is the sequence number of the first item and tail is the sequence number of the next item
+	head uint64
+	tail uint64
+	// notify is used to wake up the consumer when an item is added
+	notify chan struct{}
+	// consumer is held by the consumer of the queue, the queue is consumed by one consumer at a time, so an
+	// item is not sent twice or removed before it is sent when the consumer is replaced
+	consumer chan struct{}
+}
+
+// openQueue returns the opened queue of the dir with the new max items and overflow policy, the queue is opened
+// if it is not opened yet
+func openQueue(dir string, maxItems int, overflowPolicy string) (*queue, error) {
+	queuesLock.Lock()
+	defer queuesLock.Unlock()
+
+	dir = filepath.Clean(dir)
+	if q, ok := queues[dir]; ok {
+		q.Lock()
+		q.maxItems = maxItems
+		q.overflowPolicy = overflowPolicy
+		q.Unlock()
+		return q, nil
+	}
+
+	q, err := newQueue(dir, maxItems, overflowPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	queues[dir] = q
+	return q, nil
+}
+
+func newQueue(dir string, maxItems int, overflowPolicy string) (*queue, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create the queue dir %s, %v", dir, err)
+	}
+
+	q := &queue{
+		dir:            dir,
+		maxItems:       maxItems,
+		overflowPolicy: overflowPolicy,
+		notify:         make(chan struct{}, 1),
+		consumer:       make(chan struct{}, 1),
+	}
+
+	seqs, err := q.listSeqs()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(seqs) != 0 {
+		q.head = seqs[0]
+		q.tail = seqs[len(seqs)-1] + 1
+	}
+
+	return q, nil
+}
+
+// Acquire waits until the queue has no other consumer, false is returned if the context is done before that
+func (q *queue) Acquire(ctx context.Context) bool {
+	select {
+	case q.consumer <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Release releases the queue, so the queue can be consumed by the next consumer
+func (q *queue) Release() {
+	<-q.consumer
+}
+
+// Len returns the number of the items in the queue
+func (q *queue) Len() int {
+	q.Lock()
+	defer q.Unlock()
+
+	return int(q.tail - q.head)
+}
+
+// Push appends an item to the tail of the queue, if the queue is full, the oldest item is dropped with the
+// dropOldest policy and ErrQueueFull is returned with the dropNewest policy. The dropped is true if an item
+// is dropped.
+func (q *queue) Push(i item) (dropped bool, err error) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.maxItems > 0 && int(q.tail-q.head) >= q.maxItems {
+		if q.overflowPolicy == OverflowPolicyDropNewest {
+			return true, ErrQueueFull
+		}
+
+		if err := q.remove(q.head); err != nil {
+			return false, err
+		}
+		q.head++
+		dropped = true
+	}
+
+	data, err := json.Marshal(i)
+	if err != nil {
+		return dropped, err
+	}
+
+	// write a temporary file and rename it, so a partial item is never read after a crash
+	tmp := filepath.Join(q.dir, fmt.Sprintf(".%d.tmp", q.tail))
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return dropped, fmt.Errorf("failed to write the queue item, %v", err)
+	}
+	if err := os.Rename(tmp, q.itemFile(q.tail)); err != nil {
+		return dropped, fmt.Errorf("failed to save the queue item, %v", err)
+	}
+	q.tail++
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+
+	return dropped, nil
+}
+
+// Peek returns the first item of the queue without removing it, nil is returned if the queue is empty
+func (q *queue) Peek() (*item, error) {
+	q.Lock()
+	defer q.Unlock()
+
+	for q.head < q.tail {
+		data, err := os.ReadFile(q.itemFile(q.head))
+		if os.IsNotExist(err) {
+			q.head++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the queue item %d, %v", q.head, err)
+		}
+
+		// keep the numbers as they are, so the large integers are not changed to float64
+		i := &item{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(i); err != nil {
+			// skip the corrupted item, it cannot be delivered anyway
+			if err := q.remove(q.head); err != nil {
+				return nil, err
+			}
+			q.head++
+			continue
+		}
+
+		return i, nil
+	}
+
+	return nil, nil
+}
+
+// Pop removes the first item of the queue
+func (q *queue) Pop() error {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.head == q.tail {
+		return nil
+	}
+
+	// the item is skipped even if its file is failed to remove, otherwise it will be delivered repeatedly
+	err := q.remove(q.head)
+	q.head++
+	return err
+}
+
+func (q *queue) itemFile(seq uint64) string {
+	return filepath.Join(q.dir, fmt.Sprintf("%020d%s", seq, itemFileSuffix))
+}
+
+func (q *queue) remove(seq uint64) error {
+	if err := os.Remove(q.itemFile(seq)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove the queue item %d, %v", seq, err)
+	}
+	return nil
+}
+
+// listSeqs returns the sorted sequence numbers of the items that are saved in the queue dir, the gaps between
+// the sequence numbers are removed by renaming the items, so the items are always continuous.
+func (q *queue) listSeqs() ([]uint64, error) {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the queue dir %s, %v", q.dir, err)
+	}
+
+	seqs := []uint64{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasSuffix(name, ".tmp") {
+			// the item was not saved completely
+			_ = os.Remove(filepath.Join(q.dir, name))
+			continue
+		}
+
+		if !strings.HasSuffix(name, itemFileSuffix) {
+			continue
+		}
+
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, itemFileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+
+	for i, seq := range seqs {
+		if i == 0 || seq == seqs[0]+uint64(i) {
+			continue
+		}
+
+		expected := seqs[0] + uint64(i)
+		if err := os.Rename(q.itemFile(seq), q.itemFile(expected)); err != nil {
+			return nil, fmt.Errorf("failed to recover the queue item %d, %v", seq, err)
+		}
+		seqs[i] = expected
+	}
+
+	return seqs, nil
+}
@@ -0,0 +1,168 @@
+package buffer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+func newItem(name string) item {
+	return item{DeviceName: "device1", Result: util.Result{Name: name, Value: 1}, EnqueuedAt: time.Now()}
+}
+
+// popAll returns the names of the items in the queue and removes them
+func popAll(t *testing.T, q *queue) []string {
+	names := []string{}
+	for {
+		i, err := q.Peek()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == nil {
+			return names
+		}
+
+		names = append(names, i.Result.Name)
+		if err := q.Pop(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestQueueRecovery(t *testing.T) {
+	dir := t.TempDir()
+
+	q, err := newQueue(dir, 10, OverflowPolicyDropOldest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"r1", "r2", "r3"} {
+		if _, err := q.Push(newItem(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.Pop(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the items of an interrupted write and the corrupted items are not recovered
+	if err := os.WriteFile(filepath.Join(dir, ".3.tmp"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(q.itemFile(3), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// the queue is opened again after the agent is restarted
+	recovered, err := newQueue(dir, 10, OverflowPolicyDropOldest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".3.tmp")); !os.IsNotExist(err) {
+		t.Errorf("expected the temporary item is removed, but got %v", err)
+	}
+	if recovered.Len() != 3 {
+		t.Errorf("expected 3 items are recovered, but got %d", recovered.Len())
+	}
+
+	if _, err := recovered.Push(newItem("r4")); err != nil {
+		t.Fatal(err)
+	}
+	if names := popAll(t, recovered); fmt.Sprint(names) != "[r2 r3 r4]" {
+		t.Errorf("expected the items [r2 r3 r4], but got %v", names)
+	}
+	if recovered.Len() != 0 {
+		t.Errorf("expected the queue is empty, but got %d", recovered.Len())
+	}
+}
+
+func TestQueueGapRenumbering(t *testing.T) {
+	dir := t.TempDir()
+
+	q, err := newQueue(dir, 10, OverflowPolicyDropOldest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := q.Push(newItem(fmt.Sprintf("r%d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the items are lost in the middle of the queue, e.g. their files are removed manually
+	for _, seq := range []uint64{1, 3} {
+		if err := os.Remove(q.itemFile(seq)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recovered, err := newQueue(dir, 10, OverflowPolicyDropOldest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered.head != 0 || recovered.tail != 3 {
+		t.Errorf("expected the items are renumbered from 0 to 2, but got %d to %d", recovered.head, recovered.tail-1)
+	}
+	for seq := uint64(0); seq < 3; seq++ {
+		if _, err := os.Stat(recovered.itemFile(seq)); err != nil {
+			t.Errorf("expected the item %d exists, but got %v", seq, err)
+		}
+	}
+	if names := popAll(t, recovered); fmt.Sprint(names) != "[r0 r2 r4]" {
+		t.Errorf("expected the items [r0 r2 r4], but got %v", names)
+	}
+}
+
+func TestQueueOverflow(t *testing.T) {
+	cases := []struct {
+		name            string
+		overflowPolicy  string
+		expectedErr     error
+		expectedDropped bool
+		expectedItems   string
+	}{
+		{
+			name:            "drop oldest",
+			overflowPolicy:  OverflowPolicyDropOldest,
+			expectedDropped: true,
+			expectedItems:   "[r2 r3]",
+		},
+		{
+			name:            "drop newest",
+			overflowPolicy:  OverflowPolicyDropNewest,
+			expectedErr:     ErrQueueFull,
+			expectedDropped: true,
+			expectedItems:   "[r1 r2]",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			q, err := newQueue(t.TempDir(), 2, c.overflowPolicy)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, name := range []string{"r1", "r2"} {
+				if dropped, err := q.Push(newItem(name)); err != nil || dropped {
+					t.Fatalf("expected the item %s is pushed, but got %v, %v", name, dropped, err)
+				}
+			}
+
+			dropped, err := q.Push(newItem("r3"))
+			if err != c.expectedErr || dropped != c.expectedDropped {
+				t.Errorf("expected %v, %v, but got %v, %v", c.expectedDropped, c.expectedErr, dropped, err)
+			}
+			if q.Len() != 2 {
+				t.Errorf("expected 2 items, but got %d", q.Len())
+			}
+			if names := popAll(t, q); fmt.Sprint(names) != c.expectedItems {
+				t.Errorf("expected the items %s, but got %v", c.expectedItems, names)
+			}
+		})
+	}
+}
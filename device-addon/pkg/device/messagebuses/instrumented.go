@@ -0,0 +1,37 @@
+package messagebuses
+
+import (
+	"context"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/metrics"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// instrumentedMsgBus records the metrics of the readings that are published to a message bus
+type instrumentedMsgBus struct {
+	busType string
+	msgBus  MessageBus
+}
+
+func (m *instrumentedMsgBus) Start(ctx context.Context) error {
+	return m.msgBus.Start(ctx)
+}
+
+func (m *instrumentedMsgBus) Stop(ctx context.Context) {
+	m.msgBus.Stop(ctx)
+}
+
+func (m *instrumentedMsgBus) ReceiveData(deviceName string, result util.Result) error {
+	metrics.RecordReadingValue(deviceName, result.Name, result.Value)
+
+	if err := m.msgBus.ReceiveData(deviceName, result); err != nil {
+		metrics.PublishFailures.WithLabelValues(m.busType).Inc()
+		return err
+	}
+
+	return nil
+}
+
+func (m *instrumentedMsgBus) SendData(handler util.CommandHandler) error {
+	return m.msgBus.SendData(handler)
+}
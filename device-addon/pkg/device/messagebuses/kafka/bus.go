@@ -0,0 +1,272 @@
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// messageWriter writes the messages to the kafka topics, it is implemented by the kafka.Writer
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// messageReader reads the messages from a kafka topic, it is implemented by the kafka.Reader
+type messageReader interface {
+	ReadMessage(ctx context.Context) (kafka.Message, error)
+	Close() error
+}
+
+type KafkaMsgBus struct {
+	config       *Config
+	dataTopic    string
+	batchTimeout time.Duration
+	compression  kafka.Compression
+	payload      util.PayloadFunc
+
+	newWriter  func() messageWriter
+	newReader  func() messageReader
+	writer     messageWriter
+	reader     messageReader
+	dialer     *kafka.Dialer
+	transport  *kafka.Transport
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+}
+
+func NewKafkaMsgBus(config v1alpha1.MessageBusConfig) (*KafkaMsgBus, error) {
+	busConfig := &Config{}
+	if err := util.ToConfigObj(config.Properties.Data, busConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse kafka message bus config, %v", err)
+	}
+
+	if len(busConfig.Brokers) == 0 {
+		return nil, fmt.Errorf("brokers are required by kafka message bus")
+	}
+
+	if len(busConfig.DataTopic) == 0 {
+		klog.Infof("Using %s as the default data topic", defaultDataTopic)
+		busConfig.DataTopic = defaultDataTopic
+	}
+
+	if len(busConfig.GroupID) == 0 {
+		busConfig.GroupID = defaultGroupID
+	}
+
+	if busConfig.BatchSize == 0 {
+		busConfig.BatchSize = defaultBatchSize
+	}
+
+	if len(busConfig.BatchTimeout) == 0 {
+		busConfig.BatchTimeout = defaultBatchTimeout
+	}
+
+	batchTimeout, err := time.ParseDuration(busConfig.BatchTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid batch timeout %s, %v", busConfig.BatchTimeout, err)
+	}
+
+	compression, err := toCompression(busConfig.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := util.GetPayloadFunc(busConfig.PayloadFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	mechanism, err := toSASLMechanism(busConfig.SASL)
+	if err != nil {
+		return nil, err
+	}
+
+	var tlsConfig *tls.Config
+	if busConfig.TLS != nil {
+		tlsConfig, err = util.NewTLSConfig(busConfig.TLS.CAFile, busConfig.TLS.CertFile,
+			busConfig.TLS.KeyFile, busConfig.TLS.InsecureSkipVerify)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bus := &KafkaMsgBus{
+		config:       busConfig,
+		dataTopic:    strings.Replace(busConfig.DataTopic, "+", "%s", -1),
+		batchTimeout: batchTimeout,
+		compression:  compression,
+		payload:      payload,
+		transport: &kafka.Transport{
+			ClientID: "device-addon",
+			SASL:     mechanism,
+			TLS:      tlsConfig,
+		},
+		dialer: &kafka.Dialer{
+			ClientID:      "device-addon",
+			Timeout:       10 * time.Second,
+			DualStack:     true,
+			SASLMechanism: mechanism,
+			TLS:           tlsConfig,
+		},
+	}
+	bus.newWriter = bus.newKafkaWriter
+	bus.newReader = bus.newKafkaReader
+	return bus, nil
+}
+
+func (k *KafkaMsgBus) Start(ctx context.Context) error {
+	k.ctx, k.cancelFunc = context.WithCancel(ctx)
+	k.writer = k.newWriter()
+
+	klog.Infof("Connect to kafka message bus %v", k.config.Brokers)
+	return nil
+}
+
+// newKafkaWriter returns a synchronous writer, so the publish errors are returned to the callers, e.g. the
+// buffer of the message bus keeps the readings that fail to be published
+func (k *KafkaMsgBus) newKafkaWriter() messageWriter {
+	return &kafka.Writer{
+		Addr:                   kafka.TCP(k.config.Brokers...),
+		Balancer:               &kafka.Hash{},
+		BatchSize:              k.config.BatchSize,
+		BatchTimeout:           k.batchTimeout,
+		Compression:            k.compression,
+		RequiredAcks:           kafka.RequireOne,
+		AllowAutoTopicCreation: true,
+		Transport:              k.transport,
+	}
+}
+
+func (k *KafkaMsgBus) newKafkaReader() messageReader {
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     k.config.Brokers,
+		GroupID:     k.config.GroupID,
+		Topic:       k.config.CommandTopic,
+		Dialer:      k.dialer,
+		StartOffset: kafka.LastOffset,
+	})
+}
+
+func (k *KafkaMsgBus) ReceiveData(deviceName string, result util.Result) error {
+	topic := fmt.Sprintf(k.dataTopic, deviceName, result.Name)
+	data := k.payload(result)
+
+	klog.Infof("Send data to kafka message bus, [%s] [%s] %s", topic, deviceName, string(data))
+	// the device name is used as the message key, so the data of one device is kept in order
+	if err := k.writer.WriteMessages(k.ctx, kafka.Message{
+		Topic: topic,
+		Key:   []byte(deviceName),
+		Value: data,
+	}); err != nil {
+		return fmt.Errorf("failed to send data to kafka message bus, %v", err)
+	}
+
+	return nil
+}
+
+func (k *KafkaMsgBus) SendData(handler util.CommandHandler) error {
+	if len(k.config.CommandTopic) == 0 {
+		klog.Infof("There is no command topic, skip consuming the commands from kafka message bus")
+		return nil
+	}
+
+	k.reader = k.newReader()
+
+	go func() {
+		klog.Infof("Consuming the commands from the topic %s", k.config.CommandTopic)
+		for {
+			msg, err := k.reader.ReadMessage(k.ctx)
+			if err != nil {
+				if k.ctx.Err() != nil || errors.Is(err, io.EOF) {
+					klog.Infof("Stop consuming the commands from the topic %s", k.config.CommandTopic)
+					return
+				}
+
+				klog.Errorf("failed to read the command from the topic %s, %v", k.config.CommandTopic, err)
+				time.Sleep(time.Second)
+				continue
+			}
+
+			command := util.Command{}
+			if err := json.Unmarshal(msg.Value, &command); err != nil {
+				klog.Errorf("failed to unmarshal the command %s, %v", string(msg.Value), err)
+				continue
+			}
+
+			klog.Infof("Receive command [%s] [%s] %s", command.DeviceName, command.DeviceCommand, string(msg.Value))
+			if err := handler(command); err != nil {
+				klog.Errorf("failed to run the command %s of device %s, %v", command.DeviceCommand, command.DeviceName, err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (k *KafkaMsgBus) Stop(ctx context.Context) {
+	if k.cancelFunc != nil {
+		k.cancelFunc()
+	}
+
+	if k.writer != nil {
+		if err := k.writer.Close(); err != nil {
+			klog.Errorf("failed to close kafka writer, %v", err)
+		}
+	}
+
+	if k.reader != nil {
+		if err := k.reader.Close(); err != nil {
+			klog.Errorf("failed to close kafka reader, %v", err)
+		}
+	}
+}
+
+func toCompression(compression string) (kafka.Compression, error) {
+	switch strings.ToLower(compression) {
+	case "", "none":
+		return 0, nil
+	case "gzip":
+		return kafka.Gzip, nil
+	case "snappy":
+		return kafka.Snappy, nil
+	case "lz4":
+		return kafka.Lz4, nil
+	case "zstd":
+		return kafka.Zstd, nil
+	}
+
+	return 0, fmt.Errorf("unsupported compression %s", compression)
+}
+
+func toSASLMechanism(config *SASLConfig) (sasl.Mechanism, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	switch strings.ToLower(config.Mechanism) {
+	case SASLMechanismPlain:
+		return plain.Mechanism{Username: config.Username, Password: config.Password}, nil
+	case SASLMechanismScramSHA256:
+		return scram.Mechanism(scram.SHA256, config.Username, config.Password)
+	case SASLMechanismScramSHA512:
+		return scram.Mechanism(scram.SHA512, config.Username, config.Password)
+	}
+
+	return nil, fmt.Errorf("unsupported SASL mechanism %s", config.Mechanism)
+}
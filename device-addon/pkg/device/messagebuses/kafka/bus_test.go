@@ -0,0 +1,196 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/messagebuses/buffer"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// fakeKafka is an in-process kafka, the messages are written to and read from the topics in memory
+type fakeKafka struct {
+	sync.Mutex
+	topics   map[string][]kafka.Message
+	commands chan kafka.Message
+	// writeErr is returned by the writes if it is set, it simulates an unreachable kafka
+	writeErr error
+}
+
+func newFakeKafka() *fakeKafka {
+	return &fakeKafka{
+		topics:   map[string][]kafka.Message{},
+		commands: make(chan kafka.Message, 10),
+	}
+}
+
+func (f *fakeKafka) setWriteErr(err error) {
+	f.Lock()
+	defer f.Unlock()
+	f.writeErr = err
+}
+
+func (f *fakeKafka) messages(topic string) []kafka.Message {
+	f.Lock()
+	defer f.Unlock()
+	return append([]kafka.Message{}, f.topics[topic]...)
+}
+
+func (f *fakeKafka) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.writeErr != nil {
+		return f.writeErr
+	}
+
+	for _, msg := range msgs {
+		f.topics[msg.Topic] = append(f.topics[msg.Topic], msg)
+	}
+	return nil
+}
+
+func (f *fakeKafka) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	select {
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	case msg, ok := <-f.commands:
+		if !ok {
+			return kafka.Message{}, io.EOF
+		}
+		return msg, nil
+	}
+}
+
+func (f *fakeKafka) Close() error {
+	return nil
+}
+
+func newFakeKafkaMsgBus(t *testing.T, f *fakeKafka, properties map[string]interface{}) *KafkaMsgBus {
+	bus, err := NewKafkaMsgBus(v1alpha1.MessageBusConfig{
+		MessageBusType: "kafka",
+		Enabled:        true,
+		Properties:     v1alpha1.Values{Data: properties},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bus.newWriter = func() messageWriter { return f }
+	bus.newReader = func() messageReader { return f }
+	return bus
+}
+
+func TestReceiveData(t *testing.T) {
+	f := newFakeKafka()
+	bus := newFakeKafkaMsgBus(t, f, map[string]interface{}{"brokers": []interface{}{"kafka:9092"}})
+	if err := bus.Start(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	defer bus.Stop(context.TODO())
+
+	if err := bus.ReceiveData("device1", util.Result{Name: "temperature", Value: 21.5}); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := f.messages("devices.device1.data.temperature")
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 message, but got %d", len(messages))
+	}
+	if string(messages[0].Key) != "device1" {
+		t.Errorf("expected the key device1, but got %s", string(messages[0].Key))
+	}
+	if string(messages[0].Value) != `{"temperature":21.5}` {
+		t.Errorf("unexpected payload %s", string(messages[0].Value))
+	}
+
+	// the write errors are returned to the caller
+	f.setWriteErr(fmt.Errorf("kafka is unreachable"))
+	if err := bus.ReceiveData("device1", util.Result{Name: "temperature", Value: 22}); err == nil {
+		t.Errorf("expected the write error is returned")
+	}
+}
+
+func TestSendData(t *testing.T) {
+	f := newFakeKafka()
+	bus := newFakeKafkaMsgBus(t, f, map[string]interface{}{
+		"brokers":      []interface{}{"kafka:9092"},
+		"commandTopic": "devices.command",
+	})
+	if err := bus.Start(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	defer bus.Stop(context.TODO())
+
+	commands := make(chan util.Command, 1)
+	if err := bus.SendData(func(command util.Command) error {
+		commands <- command
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	f.commands <- kafka.Message{
+		Topic: "devices.command",
+		Value: []byte(`{"deviceName": "device1", "deviceCommand": "counter", "attributes": {"counter": 10}}`),
+	}
+
+	select {
+	case command := <-commands:
+		if command.DeviceName != "device1" || command.DeviceCommand != "counter" {
+			t.Errorf("unexpected command %v", command)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the command is not received")
+	}
+}
+
+func TestBufferedReadingsAreKept(t *testing.T) {
+	f := newFakeKafka()
+	f.setWriteErr(fmt.Errorf("kafka is unreachable"))
+	bus := newFakeKafkaMsgBus(t, f, map[string]interface{}{"brokers": []interface{}{"kafka:9092"}})
+
+	buffered, err := buffer.NewBufferedMsgBus("kafka", bus, map[string]interface{}{"dir": t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := buffered.Start(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	defer buffered.Stop(context.TODO())
+
+	for i := 0; i < 3; i++ {
+		if err := buffered.ReceiveData("device1", util.Result{Name: "counter", Value: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the readings are not delivered and they are kept in the buffer until kafka is recovered
+	time.Sleep(100 * time.Millisecond)
+	if messages := f.messages("devices.device1.data.counter"); len(messages) != 0 {
+		t.Fatalf("expected no message, but got %d", len(messages))
+	}
+	f.setWriteErr(nil)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		messages := f.messages("devices.device1.data.counter")
+		if len(messages) == 3 {
+			for i, msg := range messages {
+				if string(msg.Value) != fmt.Sprintf(`{"counter":%d}`, i) {
+					t.Errorf("expected the reading %d in order, but got %s", i, string(msg.Value))
+				}
+			}
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("expected 3 messages, but got %d", len(f.messages("devices.device1.data.counter")))
+}
@@ -0,0 +1,57 @@
+package kafka
+
+const (
+	SASLMechanismPlain       = "plain"
+	SASLMechanismScramSHA256 = "scram-sha-256"
+	SASLMechanismScramSHA512 = "scram-sha-512"
+)
+
+const (
+	defaultDataTopic    = "devices.+.data.+"
+	defaultBatchSize    = 100
+	defaultBatchTimeout = "10ms"
+	defaultGroupID      = "device-addon"
+)
+
+// Config is the kafka message bus configuration, it is converted from the message bus properties
+type Config struct {
+	// Brokers is the list of the kafka broker addresses, e.g. kafka:9092
+	Brokers []string `json:"brokers"`
+	// DataTopic is the topic that the device data is published to, the first "+" is replaced with the
+	// device name and the second is replaced with the device resource name, e.g. devices.+.data.+
+	DataTopic string `json:"dataTopic"`
+	// CommandTopic is the topic that the commands are consumed from, the message value is a json command,
+	// e.g. {"deviceName": "device1", "deviceCommand": "counter", "attributes": {"counter": 10}}, the commands
+	// are not consumed if it is empty.
+	CommandTopic string `json:"commandTopic"`
+	// GroupID is the consumer group id of the command topic
+	GroupID string `json:"groupId"`
+	// PayloadFormat is the format of the published data, jsonObj or jsonMap
+	PayloadFormat string `json:"payloadFormat"`
+	// BatchSize is the max number of the messages in one batch
+	BatchSize int `json:"batchSize"`
+	// BatchTimeout is the max time to wait for a batch to be filled, e.g. 10ms, the data is published
+	// synchronously, so a publish may be delayed by the timeout
+	BatchTimeout string `json:"batchTimeout"`
+	// Compression is the compression codec of the messages: gzip, snappy, lz4 or zstd, the messages are not
+	// compressed if it is empty
+	Compression string `json:"compression"`
+	// SASL is the SASL authentication configuration
+	SASL *SASLConfig `json:"sasl,omitempty"`
+	// TLS is the TLS configuration
+	TLS *TLSConfig `json:"tls,omitempty"`
+}
+
+type SASLConfig struct {
+	// Mechanism: plain, scram-sha-256 or scram-sha-512
+	Mechanism string `json:"mechanism"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+}
+
+type TLSConfig struct {
+	CAFile             string `json:"caFile"`
+	CertFile           string `json:"certFile"`
+	KeyFile            string `json:"keyFile"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
+}
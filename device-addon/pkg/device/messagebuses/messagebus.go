@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/messagebuses/buffer"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/messagebuses/kafka"
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/messagebuses/mqtt"
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
 
@@ -14,15 +16,55 @@ import (
 type MessageBus interface {
 	Start(ctx context.Context) error
 	Stop(ctx context.Context)
+	// ReceiveData receives the data from the devices and publishes it to the message bus
 	ReceiveData(deviceName string, result util.Result) error
-	SendData() error
+	// SendData subscribes to the commands from the message bus and sends them to the devices with the handler
+	SendData(handler util.CommandHandler) error
 }
 
+// SupportedTypes are the supported message bus types
+var SupportedTypes = []string{"mqtt", "kafka"}
+
+// bufferProperty is the message bus property to configure the buffer of the message bus, the readings are
+// sent to the message bus directly if it is not set
+const bufferProperty = "buffer"
+
 func Get(config v1alpha1.MessageBusConfig) (MessageBus, error) {
+	msgBus, err := get(config)
+	if err != nil || msgBus == nil {
+		return msgBus, err
+	}
+
+	bufferConfig, ok := config.Properties.Data[bufferProperty]
+	if !ok {
+		return &instrumentedMsgBus{busType: config.MessageBusType, msgBus: msgBus}, nil
+	}
+
+	bufferedMsgBus, err := buffer.NewBufferedMsgBus(config.MessageBusType, msgBus, bufferConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &instrumentedMsgBus{busType: config.MessageBusType, msgBus: bufferedMsgBus}, nil
+}
+
+func get(config v1alpha1.MessageBusConfig) (MessageBus, error) {
 	switch config.MessageBusType {
 	case "mqtt":
 		if config.Enabled {
-			return mqtt.NewMQTTMsgBus(config), nil
+			bus, err := mqtt.NewMQTTMsgBus(config)
+			if err != nil {
+				return nil, err
+			}
+			return bus, nil
+		}
+	case "kafka":
+		if config.Enabled {
+			bus, err := kafka.NewKafkaMsgBus(config)
+			if err != nil {
+				return nil, err
+			}
+			return bus, nil
 		}
 	default:
 		return nil, fmt.Errorf("unsupported message bus type %s", config.MessageBusType)
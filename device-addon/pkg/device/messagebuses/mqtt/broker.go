@@ -0,0 +1,264 @@
+package mqtt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"time"
+
+	mochi "github.com/mochi-co/mqtt/v2"
+	"github.com/mochi-co/mqtt/v2/hooks/auth"
+	"github.com/mochi-co/mqtt/v2/listeners"
+	"github.com/rs/zerolog"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/client"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+const (
+	defaultBrokerAddress = ":1883"
+	brokerStartTimeout   = 30 * time.Second
+
+	// internalUsername is the user of the message bus client when the broker auth is enabled
+	internalUsername = "device-addon-msgbus"
+)
+
+// BrokerConfig is the embedded broker configuration, it is the broker property of the mqtt message bus
+type BrokerConfig struct {
+	// Address is the address of the tcp listener, default is :1883, the message bus connects to the broker with
+	// it, so it can be 127.0.0.1:1883 if the devices only connect to the TLS or websocket listeners
+	Address string `json:"address"`
+	// TLS is the TLS listener, it is not started if it is not set
+	TLS *TLSListenerConfig `json:"tls,omitempty"`
+	// Websocket is the websocket listener, it is not started if it is not set
+	Websocket *ListenerConfig `json:"websocket,omitempty"`
+	// Auth is the authentication and ACL rules of the broker with the mochi auth ledger format, it has the users,
+	// auth and acl rules. If it is not set, only the message bus itself is allowed, unless the AllowAnonymous is true.
+	Auth *auth.Ledger `json:"auth,omitempty"`
+	// AllowAnonymous allows all of the clients without the authentication if the Auth is not set, it should only
+	// be used if the broker is not reachable from the untrusted networks
+	AllowAnonymous bool `json:"allowAnonymous"`
+}
+
+type ListenerConfig struct {
+	// Address is the listen address, e.g. :8883
+	Address string `json:"address"`
+}
+
+type TLSListenerConfig struct {
+	ListenerConfig `json:",inline"`
+	// CertFile and KeyFile are the server certificate and key, they are the tls.crt and tls.key files in the
+	// credential dir if they are not set
+	CertFile string `json:"certFile"`
+	KeyFile  string `json:"keyFile"`
+	// CAFile is used to verify the client certificates, it is the ca.crt file in the credential dir if it is not
+	// set, the client certificates are not required if there is no CA file
+	CAFile string `json:"caFile"`
+}
+
+// embeddedBroker is a mochi broker that runs in the agent
+type embeddedBroker struct {
+	config        *BrokerConfig
+	credentialDir string
+	server        *mochi.Server
+	ready         *readyHook
+	// the message bus client info to connect to the broker
+	brokerInfo client.MQTTBrokerInfo
+}
+
+func newEmbeddedBroker(config *BrokerConfig, credentialDir string) (*embeddedBroker, error) {
+	if len(config.Address) == 0 {
+		config.Address = defaultBrokerAddress
+	}
+
+	host, port, err := net.SplitHostPort(config.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid broker address %s, %v", config.Address, err)
+	}
+	if len(host) == 0 || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+
+	server := mochi.New(nil)
+	l := server.Log.Level(zerolog.ErrorLevel)
+	server.Log = &l
+
+	b := &embeddedBroker{
+		config:        config,
+		credentialDir: credentialDir,
+		server:        server,
+		ready:         &readyHook{ready: make(chan struct{})},
+		brokerInfo: client.MQTTBrokerInfo{
+			Host:      net.JoinHostPort(host, port),
+			KeepAlive: 3600,
+		},
+	}
+
+	if config.Auth == nil && config.AllowAnonymous {
+		klog.Warningf("The build-in MQTT broker allows the anonymous clients")
+		if err := server.AddHook(new(auth.AllowHook), nil); err != nil {
+			return nil, err
+		}
+	} else {
+		if config.Auth == nil {
+			klog.Warningf("The auth of the build-in MQTT broker is not set, only the message bus is allowed")
+			config.Auth = &auth.Ledger{}
+		}
+
+		// the message bus client is allowed with a generated password
+		password, err := randomPassword()
+		if err != nil {
+			return nil, err
+		}
+
+		if config.Auth.Users == nil {
+			config.Auth.Users = auth.Users{}
+		}
+		config.Auth.Users[internalUsername] = auth.UserRule{
+			Username: internalUsername,
+			Password: auth.RString(password),
+			ACL:      auth.Filters{"#": auth.ReadWrite},
+		}
+
+		if err := server.AddHook(new(auth.Hook), &auth.Options{Ledger: config.Auth}); err != nil {
+			return nil, fmt.Errorf("failed to set the auth of the build-in MQTT broker, %v", err)
+		}
+
+		b.brokerInfo.AuthMode = client.AuthModeBasic
+		b.brokerInfo.Username = internalUsername
+		b.brokerInfo.Password = password
+	}
+
+	if err := server.AddHook(b.ready, nil); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// start starts the listeners of the broker and waits for the broker is started
+func (b *embeddedBroker) start(ctx context.Context) error {
+	if err := b.server.AddListener(listeners.NewTCP("tcp", b.config.Address, nil)); err != nil {
+		return fmt.Errorf("failed to listen on %s, %v", b.config.Address, err)
+	}
+
+	if b.config.TLS != nil {
+		tlsConfig, err := b.serverTLSConfig()
+		if err != nil {
+			return err
+		}
+
+		if err := b.server.AddListener(listeners.NewTCP("tls", b.config.TLS.Address,
+			&listeners.Config{TLSConfig: tlsConfig})); err != nil {
+			return fmt.Errorf("failed to listen on %s, %v", b.config.TLS.Address, err)
+		}
+	}
+
+	if b.config.Websocket != nil {
+		if err := b.server.AddListener(listeners.NewWebsocket("ws", b.config.Websocket.Address, nil)); err != nil {
+			return fmt.Errorf("failed to listen on %s, %v", b.config.Websocket.Address, err)
+		}
+	}
+
+	if err := b.server.Serve(); err != nil {
+		return fmt.Errorf("failed to start the build-in MQTT broker, %v", err)
+	}
+
+	select {
+	case <-b.ready.ready:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(brokerStartTimeout):
+		return fmt.Errorf("the build-in MQTT broker is not started in %s", brokerStartTimeout)
+	}
+
+	klog.Infof("The build-in MQTT broker is started on %s", b.config.Address)
+	return nil
+}
+
+func (b *embeddedBroker) stop() {
+	if err := b.server.Close(); err != nil {
+		klog.Errorf("failed to stop the build-in MQTT broker, %v", err)
+	}
+}
+
+// serverTLSConfig returns the tls config of the TLS listener, the client certificates are verified if there is
+// a CA file
+func (b *embeddedBroker) serverTLSConfig() (*tls.Config, error) {
+	certFile := b.config.TLS.CertFile
+	keyFile := b.config.TLS.KeyFile
+	caFile := b.config.TLS.CAFile
+	if len(b.credentialDir) != 0 {
+		if len(certFile) == 0 {
+			certFile = path.Join(b.credentialDir, client.CertFile)
+		}
+		if len(keyFile) == 0 {
+			keyFile = path.Join(b.credentialDir, client.KeyFile)
+		}
+		if len(caFile) == 0 {
+			if _, err := os.Stat(path.Join(b.credentialDir, client.CAFile)); err == nil {
+				caFile = path.Join(b.credentialDir, client.CAFile)
+			}
+		}
+	}
+
+	if len(certFile) == 0 || len(keyFile) == 0 {
+		return nil, fmt.Errorf("the certificate and key are required by the TLS listener")
+	}
+
+	tlsConfig, err := util.NewTLSConfig("", certFile, keyFile, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(caFile) != 0 {
+		caData, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA file %s, %v", caFile, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("no certificate is found in the CA file %s", caFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// readyHook is notified once the broker is started
+type readyHook struct {
+	mochi.HookBase
+	ready chan struct{}
+}
+
+func (h *readyHook) ID() string {
+	return "ready"
+}
+
+func (h *readyHook) Provides(b byte) bool {
+	return b == mochi.OnStarted
+}
+
+func (h *readyHook) OnStarted() {
+	close(h.ready)
+}
+
+func randomPassword() (string, error) {
+	data := make([]byte, 16)
+	if _, err := rand.Read(data); err != nil {
+		return "", fmt.Errorf("failed to generate the password, %v", err)
+	}
+
+	return hex.EncodeToString(data), nil
+}
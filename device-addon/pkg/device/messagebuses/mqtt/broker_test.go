@@ -0,0 +1,87 @@
+package mqtt
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+)
+
+// connect connects to the broker with the username and password, the anonymous client is connected if the
+// username is empty
+func connect(address, username, password string) error {
+	conn, err := net.DialTimeout("tcp", address, 5*time.Second)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	c := paho.NewClient(paho.ClientConfig{Conn: conn})
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancel()
+	_, err = c.Connect(ctx, &paho.Connect{
+		ClientID:     "test-client",
+		KeepAlive:    30,
+		CleanStart:   true,
+		Username:     username,
+		UsernameFlag: len(username) != 0,
+		Password:     []byte(password),
+		PasswordFlag: len(password) != 0,
+	})
+	return err
+}
+
+func TestBrokerAuth(t *testing.T) {
+	cases := []struct {
+		name             string
+		allowAnonymous   bool
+		anonymousAllowed bool
+	}{
+		{
+			name:             "anonymous clients are denied by default",
+			anonymousAllowed: false,
+		},
+		{
+			name:             "anonymous clients are allowed explicitly",
+			allowAnonymous:   true,
+			anonymousAllowed: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			address := listener.Addr().String()
+			listener.Close()
+
+			broker, err := newEmbeddedBroker(&BrokerConfig{Address: address, AllowAnonymous: c.allowAnonymous}, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := broker.start(context.TODO()); err != nil {
+				t.Fatal(err)
+			}
+			defer broker.stop()
+
+			err = connect(address, "", "")
+			if c.anonymousAllowed && err != nil {
+				t.Errorf("expected the anonymous client is allowed, but got %v", err)
+			}
+			if !c.anonymousAllowed && err == nil {
+				t.Errorf("expected the anonymous client is denied")
+			}
+
+			// the message bus is always allowed
+			if err := connect(address, broker.brokerInfo.Username, broker.brokerInfo.Password); err != nil {
+				t.Errorf("expected the message bus is allowed, but got %v", err)
+			}
+		})
+	}
+}
@@ -5,14 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/eclipse/paho.golang/paho"
-	mochi "github.com/mochi-co/mqtt/v2"
-	"github.com/mochi-co/mqtt/v2/hooks/auth"
-	"github.com/mochi-co/mqtt/v2/listeners"
-	"github.com/rs/zerolog"
 
+	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/klog/v2"
 
 	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
@@ -22,42 +18,60 @@ import (
 
 const (
 	brokerhost    = "host"
+	broker        = "broker"
 	dataTopic     = "dataTopic"
+	commandTopic  = "commandTopic"
+	alarmTopic    = "alarmTopic"
+	authMode      = "authMode"
+	credentialDir = "credentialDir"
 	payloadFormat = "payloadFormat"
 )
 
-const (
-	jsonObj = "jsonObj"
-	jsonMap = "jsonMap"
-)
-
-type payloadFunc func(util.Result) []byte
+// clientIDPrefix is the prefix of the message bus client id, each message bus has a unique client id, so the broker
+// does not take the session of a message bus over when its replacement connects to the same broker
+const clientIDPrefix = "msgbus-mqtt-pub-client"
 
 type MQTTMsgBus struct {
-	mqttBroker *mochi.Server
-	host       string
-	pubClient  *paho.Client
-	dataTopic  string
-	payload    payloadFunc
+	mqttBroker     *embeddedBroker
+	clientID       string
+	host           string
+	authMode       string
+	credentialDir  string
+	conn           *client.MQTTConnection
+	dataTopic      string
+	commandTopic   string
+	alarmTopic     string
+	payload        util.PayloadFunc
+	commandHandler util.CommandHandler
 }
 
-func NewMQTTMsgBus(config v1alpha1.MessageBusConfig) *MQTTMsgBus {
-	m := &MQTTMsgBus{}
+func NewMQTTMsgBus(config v1alpha1.MessageBusConfig) (*MQTTMsgBus, error) {
+	m := &MQTTMsgBus{clientID: fmt.Sprintf("%s-%s", clientIDPrefix, rand.String(8))}
+
+	if dir, ok := config.Properties.Data[credentialDir]; ok {
+		m.credentialDir = fmt.Sprintf("%s", dir)
+	}
 
 	host, ok := config.Properties.Data[brokerhost]
 	if !ok {
 		klog.Infof("Using build-in MQTT broker as the default broker")
-		server := mochi.New(nil)
-		l := server.Log.Level(zerolog.ErrorLevel)
-		server.Log = &l
-
-		// Allow all connections.
-		_ = server.AddHook(new(auth.AllowHook), nil)
+		brokerConfig := &BrokerConfig{}
+		if properties, ok := config.Properties.Data[broker].(map[string]interface{}); ok {
+			if err := util.ToConfigObj(properties, brokerConfig); err != nil {
+				return nil, fmt.Errorf("failed to parse the build-in MQTT broker config, %v", err)
+			}
+		}
 
-		m.mqttBroker = server
-		m.host = "127.0.0.1:1883"
+		mqttBroker, err := newEmbeddedBroker(brokerConfig, m.credentialDir)
+		if err != nil {
+			return nil, err
+		}
+		m.mqttBroker = mqttBroker
 	} else {
 		m.host = fmt.Sprintf("%s", host)
+		if mode, ok := config.Properties.Data[authMode]; ok {
+			m.authMode = fmt.Sprintf("%s", mode)
+		}
 	}
 
 	ptopic, ok := config.Properties.Data[dataTopic]
@@ -67,58 +81,59 @@ func NewMQTTMsgBus(config v1alpha1.MessageBusConfig) *MQTTMsgBus {
 	}
 	m.dataTopic = strings.Replace(fmt.Sprintf("%s", ptopic), "+", "%s", -1)
 
+	ctopic, ok := config.Properties.Data[commandTopic]
+	if !ok {
+		klog.Infof("Using devices/+/command/+ as the default command topic")
+		ctopic = "devices/+/command/+"
+	}
+	m.commandTopic = fmt.Sprintf("%s", ctopic)
+
+	atopic, ok := config.Properties.Data[alarmTopic]
+	if !ok {
+		klog.Infof("Using devices/+/alarm/+ as the default alarm topic")
+		atopic = "devices/+/alarm/+"
+	}
+	m.alarmTopic = strings.Replace(fmt.Sprintf("%s", atopic), "+", "%s", -1)
+
 	format, ok := config.Properties.Data[payloadFormat]
 	if !ok {
-		klog.Infof("Using %s as the default payload format", jsonMap)
-		format = jsonMap
+		klog.Infof("Using %s as the default payload format", util.PayloadFormatJSONMap)
+		format = util.PayloadFormatJSONMap
 	}
 
-	format = fmt.Sprintf("%s", format)
-	switch format {
-	case jsonObj:
-		m.payload = toJsonObj
-	case jsonMap:
-		m.payload = toJsonMap
+	payload, err := util.GetPayloadFunc(fmt.Sprintf("%s", format))
+	if err != nil {
+		klog.Warningf("Using %s as the payload format, %v", util.PayloadFormatJSONMap, err)
+		payload = util.ToJSONMapPayload
 	}
+	m.payload = payload
 
-	return m
+	return m, nil
 }
 
 func (m *MQTTMsgBus) Start(ctx context.Context) error {
-	if m.mqttBroker != nil {
-		go func() {
-			tcp := listeners.NewTCP("mqttmsgbus", ":1883", nil)
-			if err := m.mqttBroker.AddListener(tcp); err != nil {
-				klog.Fatal(err)
-			}
-
-			if err := m.mqttBroker.Serve(); err != nil {
-				klog.Fatal(err)
-			}
+	brokerInfo := &client.MQTTBrokerInfo{
+		Host:          m.host,
+		KeepAlive:     3600,
+		AuthMode:      m.authMode,
+		CredentialDir: m.credentialDir,
+	}
 
-			klog.Infof("MQTT message bus is started on the localhost")
-		}()
+	if m.mqttBroker != nil {
+		if err := m.mqttBroker.start(ctx); err != nil {
+			return err
+		}
 
-		// TODO need a notify mechanism
-		time.Sleep(5 * time.Second)
+		brokerInfo = &m.mqttBroker.brokerInfo
 	}
+	brokerInfo.ClientId = m.clientID
 
-	client, err := client.ConnectToMQTTBroker(
-		ctx,
-		&client.MQTTBrokerInfo{
-			Host:      m.host,
-			ClientId:  "msgbus-mqtt-pub-client",
-			KeepAlive: 3600,
-		},
-		nil,
-	)
-	if err != nil {
+	m.conn = client.NewMQTTConnection(brokerInfo, paho.NewSingleHandlerRouter(m.handleCommand))
+	if err := m.conn.Start(ctx); err != nil {
 		return err
 	}
 
-	m.pubClient = client
-
-	klog.Infof("Connect to localhost MQTT message bus")
+	klog.Infof("Connect to MQTT message bus %s", brokerInfo.Host)
 	return nil
 }
 
@@ -127,36 +142,126 @@ func (m *MQTTMsgBus) ReceiveData(deviceName string, result util.Result) error {
 	data := m.payload(result)
 
 	klog.Infof("Send data to MQTT message bus, [%s] [%s] %s", topic, deviceName, string(data))
-	_, err := m.pubClient.Publish(context.TODO(), &paho.Publish{
+	_, err := m.conn.Publish(context.TODO(), &paho.Publish{
 		Topic:   topic,
 		QoS:     0,
 		Payload: data,
 	})
 	if err != nil {
-		// TODO handle this error
-		klog.Errorf("failed to send data, %v", err)
-		return nil
+		return fmt.Errorf("failed to send data to %s, %v", topic, err)
 	}
 
+	m.publishAlarms(deviceName, result)
 	return nil
 }
 
-func (m *MQTTMsgBus) SendData() error {
-	// TODO subscribe to message bus to get the command to send the command to driver
+func (m *MQTTMsgBus) SendData(handler util.CommandHandler) error {
+	m.commandHandler = handler
+
+	if err := m.conn.Subscribe(context.TODO(), m.commandTopic, paho.SubscribeOptions{QoS: 0}); err != nil {
+		return fmt.Errorf("failed to subscribe to %s, %v", m.commandTopic, err)
+	}
+
+	klog.Infof("Subscribing to the command topic %s", m.commandTopic)
 	return nil
 }
 
 func (m *MQTTMsgBus) Stop(ctx context.Context) {
-	m.pubClient.Disconnect(&paho.Disconnect{ReasonCode: 0})
-	m.mqttBroker.Close()
+	if m.conn != nil {
+		m.conn.Disconnect()
+	}
+
+	if m.mqttBroker != nil {
+		m.mqttBroker.stop()
+	}
+}
+
+// publishAlarms publishes the alarms of a result to the alarm topic
+func (m *MQTTMsgBus) publishAlarms(deviceName string, result util.Result) {
+	if len(result.Alarms) == 0 {
+		return
+	}
+
+	topic := fmt.Sprintf(m.alarmTopic, deviceName, result.Name)
+	for _, alarm := range result.Alarms {
+		data, err := json.Marshal(map[string]any{
+			"type":            alarm.Type,
+			"message":         alarm.Message,
+			"value":           util.ToJSONValue(result),
+			"createTimestamp": result.CreateTimestamp,
+		})
+		if err != nil {
+			klog.Errorf("failed to marshal the alarm of device %s, %v", deviceName, err)
+			continue
+		}
+
+		klog.Warningf("Send alarm to MQTT message bus, [%s] [%s] %s", topic, deviceName, string(data))
+		if _, err := m.conn.Publish(context.TODO(), &paho.Publish{
+			Topic:   topic,
+			QoS:     0,
+			Payload: data,
+		}); err != nil {
+			klog.Errorf("failed to send alarm, %v", err)
+		}
+	}
 }
 
-func toJsonObj(result util.Result) []byte {
-	payload, _ := json.Marshal(result)
-	return payload
+// handleCommand handles the command that is published to the command topic, the first wildcard of the
+// command topic is the device name and the second is the command name, the payload is a json map, its
+// keys are the device resource names and values are the values that will be written to the device.
+func (m *MQTTMsgBus) handleCommand(p *paho.Publish) {
+	if m.commandHandler == nil {
+		return
+	}
+
+	deviceName, commandName, err := parseCommandTopic(m.commandTopic, p.Topic)
+	if err != nil {
+		klog.Warningf("Ignore the message from topic %s, %v", p.Topic, err)
+		return
+	}
+
+	attrs := util.Attributes{}
+	if len(p.Payload) != 0 {
+		if err := json.Unmarshal(p.Payload, &attrs); err != nil {
+			klog.Errorf("failed to unmarshal the command %s of device %s, %v", commandName, deviceName, err)
+			return
+		}
+	}
+
+	klog.Infof("Receive command [%s] [%s] %s", deviceName, commandName, string(p.Payload))
+	go func() {
+		if err := m.commandHandler(util.Command{
+			DeviceName:    deviceName,
+			DeviceCommand: commandName,
+			Attributes:    attrs,
+		}); err != nil {
+			klog.Errorf("failed to run the command %s of device %s, %v", commandName, deviceName, err)
+		}
+	}()
 }
 
-func toJsonMap(result util.Result) []byte {
-	payload, _ := json.Marshal(map[string]any{result.Name: result.Value})
-	return payload
+func parseCommandTopic(commandTopic, topic string) (string, string, error) {
+	levels := strings.Split(topic, "/")
+	filterLevels := strings.Split(commandTopic, "/")
+	if len(levels) != len(filterLevels) {
+		return "", "", fmt.Errorf("the topic does not match %s", commandTopic)
+	}
+
+	wildcards := []string{}
+	for i, level := range filterLevels {
+		if level == "+" {
+			wildcards = append(wildcards, levels[i])
+			continue
+		}
+
+		if level != levels[i] {
+			return "", "", fmt.Errorf("the topic does not match %s", commandTopic)
+		}
+	}
+
+	if len(wildcards) != 2 {
+		return "", "", fmt.Errorf("the device name and command name are required in the topic %s", commandTopic)
+	}
+
+	return wildcards[0], wildcards[1], nil
 }
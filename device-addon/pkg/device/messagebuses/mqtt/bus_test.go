@@ -0,0 +1,29 @@
+package mqtt
+
+import (
+	"testing"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+)
+
+func TestClientIDIsUnique(t *testing.T) {
+	config := v1alpha1.MessageBusConfig{
+		MessageBusType: "mqtt",
+		Enabled:        true,
+		Properties:     v1alpha1.Values{Data: map[string]interface{}{"host": "127.0.0.1:1883"}},
+	}
+
+	// a message bus and its replacement connect to the same broker with different client ids
+	bus1, err := NewMQTTMsgBus(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus2, err := NewMQTTMsgBus(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bus1.clientID == bus2.clientID {
+		t.Errorf("expected the unique client ids, but got %s", bus1.clientID)
+	}
+}
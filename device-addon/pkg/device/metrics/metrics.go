@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Namespace is the namespace of the device addon metrics
+const Namespace = "device_addon"
+
+var (
+	// QueueDepth is the number of the readings that are buffered in the queue of a message bus
+	QueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "message_bus_queue_depth",
+		Help:      "Number of the readings that are buffered in the queue of a message bus.",
+	}, []string{"bus"})
+
+	// QueueDropped is the number of the readings that are dropped from the queue of a message bus
+	QueueDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "message_bus_queue_dropped_total",
+		Help:      "Number of the readings that are dropped from the queue of a message bus.",
+	}, []string{"bus", "reason"})
+
+	// PublishFailures is the number of the readings that are failed to publish to a message bus
+	PublishFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "message_bus_publish_failures_total",
+		Help:      "Number of the readings that are failed to publish to a message bus.",
+	}, []string{"bus"})
+
+	// ReadingValue is the latest numeric reading of a device resource, it is only exposed if the reading values
+	// are enabled
+	ReadingValue = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "device_reading_value",
+		Help:      "Latest numeric reading of a device resource.",
+	}, []string{"device", "resource"})
+)
+
+// readingValuesEnabled exposes the latest numeric readings as the gauges
+var readingValuesEnabled bool
+
+func init() {
+	prometheus.MustRegister(QueueDepth, QueueDropped, PublishFailures, ReadingValue)
+}
+
+// EnableReadingValues exposes the latest numeric readings of the device resources as the gauges
+func EnableReadingValues() {
+	readingValuesEnabled = true
+}
+
+// RecordReadingValue records the latest reading of a device resource if the reading values are enabled, the
+// readings that are not numeric or bool are ignored
+func RecordReadingValue(deviceName, resourceName string, value interface{}) {
+	if !readingValuesEnabled {
+		return
+	}
+
+	var val float64
+	switch v := value.(type) {
+	case bool:
+		if v {
+			val = 1
+		}
+	case float32:
+		val = float64(v)
+	case float64:
+		val = v
+	case int:
+		val = float64(v)
+	case int8:
+		val = float64(v)
+	case int16:
+		val = float64(v)
+	case int32:
+		val = float64(v)
+	case int64:
+		val = float64(v)
+	case uint:
+		val = float64(v)
+	case uint8:
+		val = float64(v)
+	case uint16:
+		val = float64(v)
+	case uint32:
+		val = float64(v)
+	case uint64:
+		val = float64(v)
+	default:
+		return
+	}
+
+	ReadingValue.WithLabelValues(deviceName, resourceName).Set(val)
+}
+
+// DeleteDevice removes the metrics of the device when it is removed
+func DeleteDevice(deviceName string) {
+	ReadingValue.DeletePartialMatch(prometheus.Labels{"device": deviceName})
+}
@@ -0,0 +1,317 @@
+package poller
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/cast"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/messagebuses"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// PollIntervalProperty is the key of the resource poll interval in the resource optional properties, e.g.
+// optional: {pollInterval: 10s}, if it is not set, the device poll interval is used
+const PollIntervalProperty = "pollInterval"
+
+const (
+	defaultJitter     = 0.1
+	defaultMaxBackoff = 5 * time.Minute
+)
+
+// ReadFunc reads the resources of a device and returns the readings keyed by the resource names. The resources
+// that cannot be read are omitted from the readings and their errors are returned together. If no reading is
+// returned with an error, the device is considered unreachable and the next poll is delayed with a backoff.
+type ReadFunc func(ctx context.Context, device v1alpha1.DeviceConfig,
+	resources []v1alpha1.DeviceResource) (map[string]interface{}, error)
+
+// Config is the poller configuration
+type Config struct {
+	// Jitter is the factor of the poll interval that is randomly added to each poll, so that the devices with
+	// the same interval are not polled at the same time, e.g. 0.1 adds up to 10% of the interval, it is 0.1 by
+	// default and a negative value disables the jitter
+	Jitter float64
+	// MaxBackoff is the maximum delay of the next poll if the device is unreachable, the delay starts from the
+	// poll interval and doubles on each failure
+	MaxBackoff time.Duration
+}
+
+// Poller polls the readable resources of the devices periodically with a ReadFunc and publishes the readings to
+// the message buses. Each resource is polled with its own interval, the resources of a device that are due at
+// the same time are coalesced into one read.
+type Poller struct {
+	sync.Mutex
+	config   Config
+	read     ReadFunc
+	msgBuses []messagebuses.MessageBus
+	states   *util.DeviceStates
+	devices  map[string]*polledDevice
+}
+
+type polledDevice struct {
+	cancelFunc context.CancelFunc
+	done       chan struct{}
+}
+
+// schedule is a group of the device resources that have the same poll interval
+type schedule struct {
+	interval  time.Duration
+	resources []v1alpha1.DeviceResource
+	next      time.Time
+}
+
+func NewPoller(config Config, read ReadFunc, msgBuses []messagebuses.MessageBus, states *util.DeviceStates) *Poller {
+	if config.Jitter == 0 {
+		config.Jitter = defaultJitter
+	}
+
+	if config.Jitter < 0 {
+		config.Jitter = 0
+	}
+
+	if config.MaxBackoff == 0 {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+
+	return &Poller{
+		config:   config,
+		read:     read,
+		msgBuses: msgBuses,
+		states:   states,
+		devices:  make(map[string]*polledDevice),
+	}
+}
+
+// AddDevice starts polling the device with the interval, if the device is being polled, it is restarted
+func (p *Poller) AddDevice(device v1alpha1.DeviceConfig, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid poll interval %s of device %s", interval, device.Name)
+	}
+
+	schedules, err := toSchedules(device, interval)
+	if err != nil {
+		return err
+	}
+
+	var polled *polledDevice
+	var ctx context.Context
+	if len(schedules) != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(context.Background())
+		polled = &polledDevice{
+			cancelFunc: cancel,
+			done:       make(chan struct{}),
+		}
+	}
+
+	// the last device is replaced in one step, so the device is not polled twice by the concurrent adds
+	p.Lock()
+	last := p.devices[device.Name]
+	delete(p.devices, device.Name)
+	if polled != nil {
+		p.devices[device.Name] = polled
+	}
+	p.Unlock()
+
+	if last != nil {
+		last.cancelFunc()
+		<-last.done
+	}
+
+	if polled == nil {
+		klog.Infof("The device %s has no readable resource to poll", device.Name)
+		return nil
+	}
+
+	go func() {
+		defer close(polled.done)
+
+		klog.Infof("Start polling device %s every %s", device.Name, interval)
+		p.poll(ctx, device, schedules)
+		klog.Infof("The device %s is done", device.Name)
+	}()
+
+	return nil
+}
+
+// RemoveDevice stops polling the device, once it returns, the device will not be read anymore
+func (p *Poller) RemoveDevice(deviceName string) {
+	p.Lock()
+	polled, ok := p.devices[deviceName]
+	delete(p.devices, deviceName)
+	p.Unlock()
+
+	if !ok {
+		return
+	}
+
+	polled.cancelFunc()
+	<-polled.done
+}
+
+// Stop stops polling all of the devices
+func (p *Poller) Stop() {
+	p.Lock()
+	names := make([]string, 0, len(p.devices))
+	for name := range p.devices {
+		names = append(names, name)
+	}
+	p.Unlock()
+
+	for _, name := range names {
+		p.RemoveDevice(name)
+	}
+}
+
+func (p *Poller) poll(ctx context.Context, device v1alpha1.DeviceConfig, schedules []*schedule) {
+	// spread the first polls of the devices
+	start := time.Now()
+	for _, s := range schedules {
+		s.next = start.Add(p.jitter(s.interval))
+	}
+
+	backoff := time.Duration(0)
+	for {
+		next := schedules[0].next
+		for _, s := range schedules[1:] {
+			if s.next.Before(next) {
+				next = s.next
+			}
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// the resources that are due in the jitter window are read together with the due resources
+		now := time.Now()
+		due := []*schedule{}
+		resources := []v1alpha1.DeviceResource{}
+		for _, s := range schedules {
+			if s.next.After(now.Add(time.Duration(float64(s.interval) * p.config.Jitter))) {
+				continue
+			}
+			due = append(due, s)
+			resources = append(resources, s.resources...)
+		}
+
+		readings, err := p.read(ctx, device, resources)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// the device is unreachable, all of its resources are delayed with the backoff, the backoff starts from
+		// the shortest poll interval of the device
+		if err != nil && len(readings) == 0 {
+			backoff = p.nextBackoff(backoff, schedules[0].interval)
+			klog.Errorf("failed to read the device %s, retry after %s, %v", device.Name, backoff, err)
+			p.states.SetConnected(device.Name, false)
+			p.states.RecordError(device.Name, err)
+			retry := time.Now().Add(backoff)
+			for _, s := range schedules {
+				if s.next.Before(retry) {
+					s.next = retry
+				}
+			}
+			continue
+		}
+
+		backoff = 0
+		p.states.SetConnected(device.Name, true)
+		if err != nil {
+			klog.Errorf("failed to read the device %s, %v", device.Name, err)
+			p.states.RecordError(device.Name, err)
+		}
+
+		p.publish(device.Name, resources, readings)
+
+		for _, s := range due {
+			s.next = time.Now().Add(s.interval + p.jitter(s.interval))
+		}
+	}
+}
+
+func (p *Poller) publish(deviceName string, resources []v1alpha1.DeviceResource, readings map[string]interface{}) {
+	for _, res := range resources {
+		reading, ok := readings[res.Name]
+		if !ok {
+			continue
+		}
+
+		result, err := util.NewResult(res, reading)
+		if err != nil {
+			klog.Errorf("The device %s attribute %s  is unsupported, %v", deviceName, res.Name, err)
+			p.states.RecordError(deviceName, err)
+			continue
+		}
+
+		p.states.RecordReading(deviceName)
+
+		for _, msgBus := range p.msgBuses {
+			if err := msgBus.ReceiveData(deviceName, *result); err != nil {
+				klog.Errorf("failed to publish the data of device %s, %v", deviceName, err)
+			}
+		}
+	}
+}
+
+func (p *Poller) jitter(interval time.Duration) time.Duration {
+	return time.Duration(rand.Float64() * p.config.Jitter * float64(interval))
+}
+
+func (p *Poller) nextBackoff(last, interval time.Duration) time.Duration {
+	backoff := last * 2
+	if backoff == 0 {
+		backoff = interval
+	}
+
+	if backoff > p.config.MaxBackoff {
+		backoff = p.config.MaxBackoff
+	}
+
+	return backoff
+}
+
+// toSchedules groups the readable resources of the device by their poll intervals
+func toSchedules(device v1alpha1.DeviceConfig, interval time.Duration) ([]*schedule, error) {
+	groups := map[time.Duration]*schedule{}
+	for _, res := range device.Profile.DeviceResources {
+		if !util.IsReadable(res.Properties.ReadWrite) {
+			continue
+		}
+
+		resInterval := interval
+		if val, ok := res.Properties.Optional.Data[PollIntervalProperty]; ok {
+			parsed, err := time.ParseDuration(cast.ToString(val))
+			if err != nil || parsed <= 0 {
+				return nil, fmt.Errorf("invalid poll interval %v of resource %s of device %s", val, res.Name, device.Name)
+			}
+			resInterval = parsed
+		}
+
+		group, ok := groups[resInterval]
+		if !ok {
+			group = &schedule{interval: resInterval}
+			groups[resInterval] = group
+		}
+		group.resources = append(group.resources, res)
+	}
+
+	schedules := make([]*schedule, 0, len(groups))
+	for _, group := range groups {
+		schedules = append(schedules, group)
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].interval < schedules[j].interval })
+	return schedules, nil
+}
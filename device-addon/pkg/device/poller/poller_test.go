@@ -0,0 +1,186 @@
+package poller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/messagebuses"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+	testingutil "open-cluster-management-io/addon-contrib/device-addon/pkg/device/util/testing"
+)
+
+// fakeReader records the resources that are read
+type fakeReader struct {
+	sync.Mutex
+	err   error
+	reads [][]string
+}
+
+func (r *fakeReader) read(ctx context.Context, device v1alpha1.DeviceConfig,
+	resources []v1alpha1.DeviceResource) (map[string]interface{}, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	names := []string{}
+	readings := map[string]interface{}{}
+	for _, res := range resources {
+		names = append(names, res.Name)
+		readings[res.Name] = int64(len(r.reads))
+	}
+	r.reads = append(r.reads, names)
+
+	if r.err != nil {
+		return nil, r.err
+	}
+	return readings, nil
+}
+
+func (r *fakeReader) count() int {
+	r.Lock()
+	defer r.Unlock()
+	return len(r.reads)
+}
+
+func (r *fakeReader) readResources() map[string]bool {
+	r.Lock()
+	defer r.Unlock()
+
+	resources := map[string]bool{}
+	for _, names := range r.reads {
+		for _, name := range names {
+			resources[name] = true
+		}
+	}
+	return resources
+}
+
+// newTestDevice returns a device whose humidity is polled in its own interval
+func newTestDevice(name string) v1alpha1.DeviceConfig {
+	humidity := testingutil.NewResource("humidity", "RW", util.ValueTypeInt64, nil)
+	humidity.Properties.Optional = v1alpha1.Values{Data: map[string]interface{}{PollIntervalProperty: "30ms"}}
+
+	return testingutil.NewDevice(name, "", nil,
+		testingutil.NewResource("temperature", "r", util.ValueTypeInt64, nil),
+		humidity,
+		testingutil.NewResource("reset", "w", util.ValueTypeBool, nil),
+	)
+}
+
+func TestToSchedules(t *testing.T) {
+	schedules, err := toSchedules(newTestDevice("device1"), 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(schedules) != 2 {
+		t.Fatalf("expected 2 schedules, but got %d", len(schedules))
+	}
+
+	// the write-only resources are not polled and the schedules are sorted by their intervals
+	if schedules[0].interval != 10*time.Millisecond || len(schedules[0].resources) != 1 ||
+		schedules[0].resources[0].Name != "temperature" {
+		t.Errorf("unexpected schedule %v", schedules[0])
+	}
+	if schedules[1].interval != 30*time.Millisecond || len(schedules[1].resources) != 1 ||
+		schedules[1].resources[0].Name != "humidity" {
+		t.Errorf("unexpected schedule %v", schedules[1])
+	}
+
+	device := newTestDevice("device1")
+	device.Profile.DeviceResources[0].Properties.Optional = v1alpha1.Values{
+		Data: map[string]interface{}{PollIntervalProperty: "-1s"}}
+	if _, err := toSchedules(device, 10*time.Millisecond); err == nil {
+		t.Errorf("expected the invalid poll interval is rejected")
+	}
+}
+
+func TestPoll(t *testing.T) {
+	reader := &fakeReader{}
+	msgBus := testingutil.NewFakeMsgBus()
+	states := util.NewDeviceStates()
+	poller := NewPoller(Config{}, reader.read, []messagebuses.MessageBus{msgBus}, states)
+	defer poller.Stop()
+
+	if err := poller.AddDevice(newTestDevice("device1"), 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	received := map[string]bool{}
+	timeout := time.After(5 * time.Second)
+	for len(received) < 2 {
+		select {
+		case result := <-msgBus.Results:
+			received[result.Name] = true
+		case <-timeout:
+			t.Fatalf("expected the readings of temperature and humidity, but got %v", received)
+		}
+	}
+
+	if reader.readResources()["reset"] {
+		t.Errorf("expected the write-only resource is not read")
+	}
+
+	state := states.Get("device1")
+	if state == nil || !state.Connected {
+		t.Errorf("expected the device is connected, but got %v", state)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	reader := &fakeReader{err: fmt.Errorf("the device is unreachable")}
+	states := util.NewDeviceStates()
+	poller := NewPoller(Config{Jitter: -1, MaxBackoff: time.Minute}, reader.read, nil, states)
+	defer poller.Stop()
+
+	if err := poller.AddDevice(newTestDevice("device1"), 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	// the reads are delayed 10ms, 20ms, 40ms, 80ms and 160ms, the resources with the other interval are delayed
+	// together, so there are about 6 reads in 350ms, there are more than 40 reads without the backoff
+	time.Sleep(350 * time.Millisecond)
+	if count := reader.count(); count > 8 {
+		t.Errorf("expected the reads of the unreachable device are delayed, but got %d reads", count)
+	}
+
+	state := states.Get("device1")
+	if state == nil || state.Connected || state.ErrorCount == 0 {
+		t.Errorf("expected the device is disconnected with errors, but got %v", state)
+	}
+}
+
+func TestAddAndRemoveDevice(t *testing.T) {
+	reader := &fakeReader{}
+	poller := NewPoller(Config{}, reader.read, nil, util.NewDeviceStates())
+	defer poller.Stop()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := poller.AddDevice(newTestDevice("device1"), 10*time.Millisecond); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	poller.Lock()
+	count := len(poller.devices)
+	poller.Unlock()
+	if count != 1 {
+		t.Errorf("expected 1 polled device, but got %d", count)
+	}
+
+	poller.RemoveDevice("device1")
+	reads := reader.count()
+	time.Sleep(50 * time.Millisecond)
+	if reader.count() != reads {
+		t.Errorf("expected the removed device is not read anymore")
+	}
+}
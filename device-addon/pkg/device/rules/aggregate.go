@@ -0,0 +1,144 @@
+package rules
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+const (
+	AggregateAvg = "avg"
+	AggregateMin = "min"
+	AggregateMax = "max"
+)
+
+type window struct {
+	timer *time.Timer
+	count int
+	sum   float64
+	// min and max are the results of the minimum and maximum readings
+	min util.Result
+	max util.Result
+	// last is the result of the last reading
+	last util.Result
+}
+
+// aggregateStage publishes the aggregate of the numeric readings once a window is closed, the window of a device
+// resource starts with its first reading. The non-numeric readings and the readings with alarms are published
+// without aggregation.
+type aggregateStage struct {
+	sync.Mutex
+	window   time.Duration
+	function string
+	windows  map[stateKey]*window
+	emit     PublishFunc
+}
+
+func newAggregateStage(config *v1alpha1.AggregateRule, emit PublishFunc) (*aggregateStage, error) {
+	duration, err := parseDuration("window", config.Window, true)
+	if err != nil {
+		return nil, err
+	}
+
+	function := config.Function
+	switch function {
+	case "":
+		function = AggregateAvg
+	case AggregateAvg, AggregateMin, AggregateMax:
+	default:
+		return nil, fmt.Errorf("unsupported aggregate function %s", function)
+	}
+
+	return &aggregateStage{
+		window:   duration,
+		function: function,
+		windows:  make(map[stateKey]*window),
+		emit:     emit,
+	}, nil
+}
+
+func (s *aggregateStage) apply(deviceName string, result util.Result) (util.Result, bool) {
+	val, ok := toFloat(result)
+	if !ok || len(result.Alarms) != 0 {
+		return result, true
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	key := stateKey{device: deviceName, resource: result.Name}
+	w, ok := s.windows[key]
+	if !ok {
+		w = &window{min: result, max: result}
+		w.timer = time.AfterFunc(s.window, func() { s.close(key, w) })
+		s.windows[key] = w
+	}
+
+	w.count++
+	w.sum += val
+	if minVal, _ := toFloat(w.min); val < minVal {
+		w.min = result
+	}
+	if maxVal, _ := toFloat(w.max); val > maxVal {
+		w.max = result
+	}
+	w.last = result
+
+	return result, false
+}
+
+// close publishes the aggregate of a window
+func (s *aggregateStage) close(key stateKey, w *window) {
+	s.Lock()
+	if s.windows[key] != w {
+		// the window is removed
+		s.Unlock()
+		return
+	}
+	delete(s.windows, key)
+	s.Unlock()
+
+	var result util.Result
+	switch s.function {
+	case AggregateMin:
+		result = w.min
+	case AggregateMax:
+		result = w.max
+	default:
+		// the average keeps the fields of the last result, and it is rounded for the integer value types
+		result = w.last
+		result.Value = fromFloat(result.Type, w.sum/float64(w.count))
+	}
+	result.CreateTimestamp = time.Now().UnixNano()
+
+	if err := s.emit(key.device, result); err != nil {
+		klog.Errorf("failed to publish the %s of %s of device %s, %v", s.function, key.resource, key.device, err)
+	}
+}
+
+func (s *aggregateStage) removeDevice(deviceName string) {
+	s.Lock()
+	defer s.Unlock()
+
+	for key, w := range s.windows {
+		if key.device == deviceName {
+			w.timer.Stop()
+			delete(s.windows, key)
+		}
+	}
+}
+
+func (s *aggregateStage) stop() {
+	s.Lock()
+	defer s.Unlock()
+
+	for key, w := range s.windows {
+		w.timer.Stop()
+		delete(s.windows, key)
+	}
+}
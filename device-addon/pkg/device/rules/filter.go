@@ -0,0 +1,132 @@
+package rules
+
+import (
+	"math"
+	"reflect"
+	"sync"
+	"time"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// deadbandStage publishes a reading only if it changes beyond the deadband from the last published reading, the
+// non-numeric readings are published once they are changed. The readings with alarms are always published.
+type deadbandStage struct {
+	sync.Mutex
+	absolute    float64
+	percent     float64
+	maxInterval time.Duration
+	last        map[stateKey]util.Result
+}
+
+func newDeadbandStage(config *v1alpha1.DeadbandRule) (*deadbandStage, error) {
+	maxInterval, err := parseDuration("max interval", config.MaxInterval, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &deadbandStage{
+		absolute:    math.Abs(config.Absolute),
+		percent:     math.Abs(config.Percent),
+		maxInterval: maxInterval,
+		last:        make(map[stateKey]util.Result),
+	}, nil
+}
+
+func (s *deadbandStage) apply(deviceName string, result util.Result) (util.Result, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	key := stateKey{device: deviceName, resource: result.Name}
+	last, ok := s.last[key]
+	if ok && len(result.Alarms) == 0 && !s.exceeded(last, result) {
+		return result, false
+	}
+
+	s.last[key] = result
+	return result, true
+}
+
+func (s *deadbandStage) exceeded(last, current util.Result) bool {
+	if s.maxInterval > 0 && time.Duration(current.CreateTimestamp-last.CreateTimestamp) >= s.maxInterval {
+		return true
+	}
+
+	lastVal, lastOk := toFloat(last)
+	val, ok := toFloat(current)
+	if !lastOk || !ok {
+		return !reflect.DeepEqual(last.Value, current.Value)
+	}
+
+	delta := math.Abs(val - lastVal)
+	if s.absolute == 0 && s.percent == 0 {
+		return delta != 0
+	}
+
+	if s.absolute > 0 && delta > s.absolute {
+		return true
+	}
+
+	return s.percent > 0 && delta > math.Abs(lastVal)*s.percent/100
+}
+
+func (s *deadbandStage) removeDevice(deviceName string) {
+	s.Lock()
+	defer s.Unlock()
+
+	for key := range s.last {
+		if key.device == deviceName {
+			delete(s.last, key)
+		}
+	}
+}
+
+func (s *deadbandStage) stop() {}
+
+// downsampleStage publishes one reading at most in an interval, the readings with alarms are always published
+type downsampleStage struct {
+	sync.Mutex
+	interval time.Duration
+	// last is the timestamp of the last published reading
+	last map[stateKey]int64
+}
+
+func newDownsampleStage(config *v1alpha1.DownsampleRule) (*downsampleStage, error) {
+	interval, err := parseDuration("interval", config.Interval, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &downsampleStage{
+		interval: interval,
+		last:     make(map[stateKey]int64),
+	}, nil
+}
+
+func (s *downsampleStage) apply(deviceName string, result util.Result) (util.Result, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	key := stateKey{device: deviceName, resource: result.Name}
+	last, ok := s.last[key]
+	if ok && len(result.Alarms) == 0 && time.Duration(result.CreateTimestamp-last) < s.interval {
+		return result, false
+	}
+
+	s.last[key] = result.CreateTimestamp
+	return result, true
+}
+
+func (s *downsampleStage) removeDevice(deviceName string) {
+	s.Lock()
+	defer s.Unlock()
+
+	for key := range s.last {
+		if key.device == deviceName {
+			delete(s.last, key)
+		}
+	}
+}
+
+func (s *downsampleStage) stop() {}
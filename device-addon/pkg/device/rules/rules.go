@@ -0,0 +1,243 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"time"
+
+	"github.com/spf13/cast"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// PublishFunc publishes a processed result of a device
+type PublishFunc func(deviceName string, result util.Result) error
+
+// stage is the processing of one rule
+type stage interface {
+	// apply returns the result that is processed by the next rules, the result is suppressed if false is returned
+	apply(deviceName string, result util.Result) (util.Result, bool)
+	// removeDevice clears the states of a device
+	removeDevice(deviceName string)
+	// stop releases the resources of the stage
+	stop()
+}
+
+type rule struct {
+	name      string
+	devices   []string
+	resources []string
+	stage     stage
+}
+
+func (r *rule) matches(deviceName, resourceName string) bool {
+	return matchNames(r.devices, deviceName) && matchNames(r.resources, resourceName)
+}
+
+// Processor applies the processing rules to the readings of the devices in order, the results that pass all of
+// the matched rules are published with the PublishFunc. A rule may suppress a result, e.g. deadband, or publish
+// its results later, e.g. aggregate, the later results are processed by the rules after it.
+type Processor struct {
+	rules   []*rule
+	publish PublishFunc
+}
+
+func NewProcessor(configs []v1alpha1.ProcessingRule, publish PublishFunc) (*Processor, error) {
+	p := &Processor{publish: publish}
+
+	for i, config := range configs {
+		for _, pattern := range append(append([]string{}, config.Devices...), config.Resources...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern %s of rule %s, %v", pattern, config.Name, err)
+			}
+		}
+
+		next := i + 1
+		s, err := newStage(config, func(deviceName string, result util.Result) error {
+			return p.process(next, deviceName, result)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule %s, %v", config.Name, err)
+		}
+
+		p.rules = append(p.rules, &rule{
+			name:      config.Name,
+			devices:   config.Devices,
+			resources: config.Resources,
+			stage:     s,
+		})
+	}
+
+	return p, nil
+}
+
+// Validate validates the config of a rule
+func Validate(config v1alpha1.ProcessingRule) error {
+	p, err := NewProcessor([]v1alpha1.ProcessingRule{config}, func(deviceName string, result util.Result) error {
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	p.Stop()
+	return nil
+}
+
+// Process applies the rules to a result of a device, the error of publishing the result is returned if the result
+// is published by the rules directly
+func (p *Processor) Process(deviceName string, result util.Result) error {
+	return p.process(0, deviceName, result)
+}
+
+// RemoveDevice clears the states of a device in the rules
+func (p *Processor) RemoveDevice(deviceName string) {
+	for _, r := range p.rules {
+		r.stage.removeDevice(deviceName)
+	}
+}
+
+// Stop stops the rules, the readings in the windows of the aggregate rules are dropped
+func (p *Processor) Stop() {
+	for _, r := range p.rules {
+		r.stage.stop()
+	}
+}
+
+func (p *Processor) process(from int, deviceName string, result util.Result) error {
+	for i := from; i < len(p.rules); i++ {
+		if !p.rules[i].matches(deviceName, result.Name) {
+			continue
+		}
+
+		processed, ok := p.rules[i].stage.apply(deviceName, result)
+		if !ok {
+			return nil
+		}
+		result = processed
+	}
+
+	return p.publish(deviceName, result)
+}
+
+func newStage(config v1alpha1.ProcessingRule, emit PublishFunc) (stage, error) {
+	var stages []stage
+	if config.Deadband != nil {
+		s, err := newDeadbandStage(config.Deadband)
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, s)
+	}
+
+	if config.Downsample != nil {
+		s, err := newDownsampleStage(config.Downsample)
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, s)
+	}
+
+	if config.Aggregate != nil {
+		s, err := newAggregateStage(config.Aggregate, emit)
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, s)
+	}
+
+	if config.Threshold != nil {
+		s, err := newThresholdStage(config.Threshold)
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, s)
+	}
+
+	if len(stages) != 1 {
+		return nil, fmt.Errorf("one and only one of deadband, downsample, aggregate and threshold should be set")
+	}
+
+	return stages[0], nil
+}
+
+// stateKey is the key of the rule states of a device resource
+type stateKey struct {
+	device   string
+	resource string
+}
+
+func matchNames(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toFloat returns the value of a numeric result
+func toFloat(result util.Result) (float64, bool) {
+	if !util.IsNumericValueType(result.Type) {
+		return 0, false
+	}
+
+	val, err := cast.ToFloat64E(result.Value)
+	if err != nil {
+		return 0, false
+	}
+
+	return val, true
+}
+
+// fromFloat converts a float to the value of a numeric value type, the float is rounded for the integer value types
+func fromFloat(valueType string, val float64) interface{} {
+	switch valueType {
+	case util.ValueTypeUint8:
+		return cast.ToUint8(math.Round(val))
+	case util.ValueTypeUint16:
+		return cast.ToUint16(math.Round(val))
+	case util.ValueTypeUint32:
+		return cast.ToUint32(math.Round(val))
+	case util.ValueTypeUint64:
+		return cast.ToUint64(math.Round(val))
+	case util.ValueTypeInt8:
+		return cast.ToInt8(math.Round(val))
+	case util.ValueTypeInt16:
+		return cast.ToInt16(math.Round(val))
+	case util.ValueTypeInt32:
+		return cast.ToInt32(math.Round(val))
+	case util.ValueTypeInt64:
+		return cast.ToInt64(math.Round(val))
+	case util.ValueTypeFloat32:
+		return float32(val)
+	}
+	return val
+}
+
+func parseDuration(name, value string, required bool) (time.Duration, error) {
+	if len(value) == 0 {
+		if required {
+			return 0, fmt.Errorf("the %s is required", name)
+		}
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %s, %v", name, value, err)
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("the %s %s should be positive", name, value)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,95 @@
+package rules
+
+import (
+	"fmt"
+	"sync"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+const (
+	AlarmTypeHighThreshold = "HighThreshold"
+	AlarmTypeLowThreshold  = "LowThreshold"
+)
+
+// thresholdStage adds an alarm to a numeric reading once the readings of the device resource cross the high or
+// low threshold, the alarm is not raised again until the readings are back between the thresholds
+type thresholdStage struct {
+	sync.Mutex
+	high *float64
+	low  *float64
+	// states are the alarm types of the device resources that are beyond the thresholds
+	states map[stateKey]string
+}
+
+func newThresholdStage(config *v1alpha1.ThresholdRule) (*thresholdStage, error) {
+	if config.High == nil && config.Low == nil {
+		return nil, fmt.Errorf("the high or low threshold is required")
+	}
+
+	if config.High != nil && config.Low != nil && *config.Low > *config.High {
+		return nil, fmt.Errorf("the low threshold %v is greater than the high threshold %v", *config.Low, *config.High)
+	}
+
+	return &thresholdStage{
+		high:   config.High,
+		low:    config.Low,
+		states: make(map[stateKey]string),
+	}, nil
+}
+
+func (s *thresholdStage) apply(deviceName string, result util.Result) (util.Result, bool) {
+	val, ok := toFloat(result)
+	if !ok {
+		return result, true
+	}
+
+	var alarm *util.Alarm
+	state := ""
+	switch {
+	case s.high != nil && val > *s.high:
+		state = AlarmTypeHighThreshold
+		alarm = &util.Alarm{
+			Type:    AlarmTypeHighThreshold,
+			Message: fmt.Sprintf("the value %v is greater than the high threshold %v", val, *s.high),
+		}
+	case s.low != nil && val < *s.low:
+		state = AlarmTypeLowThreshold
+		alarm = &util.Alarm{
+			Type:    AlarmTypeLowThreshold,
+			Message: fmt.Sprintf("the value %v is less than the low threshold %v", val, *s.low),
+		}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	key := stateKey{device: deviceName, resource: result.Name}
+	if s.states[key] == state {
+		return result, true
+	}
+
+	if len(state) == 0 {
+		delete(s.states, key)
+		return result, true
+	}
+
+	s.states[key] = state
+	// copy the alarms, the result may be shared with the other stages
+	result.Alarms = append(append([]util.Alarm{}, result.Alarms...), *alarm)
+	return result, true
+}
+
+func (s *thresholdStage) removeDevice(deviceName string) {
+	s.Lock()
+	defer s.Unlock()
+
+	for key := range s.states {
+		if key.device == deviceName {
+			delete(s.states, key)
+		}
+	}
+}
+
+func (s *thresholdStage) stop() {}
@@ -0,0 +1,155 @@
+package util
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/spf13/cast"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+)
+
+// DefaultBinaryMediaType is the media type of a binary value if its device resource does not specify one
+const DefaultBinaryMediaType = "application/octet-stream"
+
+var arrayElementTypes = map[string]string{
+	ValueTypeBoolArray:    ValueTypeBool,
+	ValueTypeStringArray:  ValueTypeString,
+	ValueTypeUint8Array:   ValueTypeUint8,
+	ValueTypeUint16Array:  ValueTypeUint16,
+	ValueTypeUint32Array:  ValueTypeUint32,
+	ValueTypeUint64Array:  ValueTypeUint64,
+	ValueTypeInt8Array:    ValueTypeInt8,
+	ValueTypeInt16Array:   ValueTypeInt16,
+	ValueTypeInt32Array:   ValueTypeInt32,
+	ValueTypeInt64Array:   ValueTypeInt64,
+	ValueTypeFloat32Array: ValueTypeFloat32,
+	ValueTypeFloat64Array: ValueTypeFloat64,
+}
+
+// ElementValueType returns the value type of the elements of an array value type, false is returned if the
+// value type is not an array
+func ElementValueType(valueType string) (string, bool) {
+	elementType, ok := arrayElementTypes[valueType]
+	return elementType, ok
+}
+
+// ToJSONValue returns the value of a result that can be serialized to json sensibly. The Uint8Array is
+// serialized as a number array rather than a base64 string and the Binary is serialized as a base64 string.
+func ToJSONValue(result Result) interface{} {
+	switch val := result.Value.(type) {
+	case []uint8:
+		if result.Type == ValueTypeUint8Array {
+			numbers := make([]uint16, len(val))
+			for i, v := range val {
+				numbers[i] = uint16(v)
+			}
+			return numbers
+		}
+
+		return base64.StdEncoding.EncodeToString(val)
+	}
+
+	return result.Value
+}
+
+// toSlice converts an array reading to a slice, the reading can be a slice, an array or a json array string
+func toSlice(reading interface{}) ([]interface{}, error) {
+	if str, ok := reading.(string); ok {
+		elements := []interface{}{}
+		if err := json.Unmarshal([]byte(str), &elements); err != nil {
+			return nil, fmt.Errorf("the %q is not an array, %v", str, err)
+		}
+		return elements, nil
+	}
+
+	if elements, ok := reading.([]interface{}); ok {
+		return elements, nil
+	}
+
+	v := reflect.ValueOf(reading)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("the %T is not an array", reading)
+	}
+
+	elements := make([]interface{}, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		elements[i] = v.Index(i).Interface()
+	}
+
+	return elements, nil
+}
+
+// toBinary converts a binary reading to bytes, a string reading is decoded with base64
+func toBinary(reading interface{}) ([]byte, error) {
+	switch val := reading.(type) {
+	case []byte:
+		return val, nil
+	case string:
+		return base64.StdEncoding.DecodeString(val)
+	}
+
+	elements, err := toSlice(reading)
+	if err != nil {
+		return nil, err
+	}
+
+	return castElements(elements, cast.ToUint8E)
+}
+
+func toArrayValue(resource v1alpha1.DeviceResource, elementType string, reading interface{}) (interface{}, error) {
+	elements, err := toSlice(reading)
+	if err != nil {
+		return nil, fmt.Errorf(castError, resource.Name, err)
+	}
+
+	var val interface{}
+	switch elementType {
+	case ValueTypeBool:
+		val, err = castElements(elements, cast.ToBoolE)
+	case ValueTypeString:
+		val, err = castElements(elements, cast.ToStringE)
+	case ValueTypeUint8:
+		val, err = castElements(elements, cast.ToUint8E)
+	case ValueTypeUint16:
+		val, err = castElements(elements, cast.ToUint16E)
+	case ValueTypeUint32:
+		val, err = castElements(elements, cast.ToUint32E)
+	case ValueTypeUint64:
+		val, err = castElements(elements, cast.ToUint64E)
+	case ValueTypeInt8:
+		val, err = castElements(elements, cast.ToInt8E)
+	case ValueTypeInt16:
+		val, err = castElements(elements, cast.ToInt16E)
+	case ValueTypeInt32:
+		val, err = castElements(elements, cast.ToInt32E)
+	case ValueTypeInt64:
+		val, err = castElements(elements, cast.ToInt64E)
+	case ValueTypeFloat32:
+		val, err = castElements(elements, cast.ToFloat32E)
+	case ValueTypeFloat64:
+		val, err = castElements(elements, cast.ToFloat64E)
+	default:
+		return nil, fmt.Errorf("return result fail, none supported array element type: %v", elementType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(castError, resource.Name, err)
+	}
+
+	return val, nil
+}
+
+func castElements[T any](elements []interface{}, castFunc func(interface{}) (T, error)) ([]T, error) {
+	values := make([]T, len(elements))
+	for i, element := range elements {
+		val, err := castFunc(element)
+		if err != nil {
+			return nil, fmt.Errorf("the element %d is invalid, %v", i, err)
+		}
+		values[i] = val
+	}
+
+	return values, nil
+}
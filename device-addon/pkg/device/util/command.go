@@ -0,0 +1,123 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+)
+
+// WriteRequest represents a raw value that will be written to a device resource
+type WriteRequest struct {
+	Resource v1alpha1.DeviceResource
+	Value    interface{}
+}
+
+// ToWriteRequests resolves the command with the device profile to the write requests, the command name can be
+// a device command name or a device resource name, the values of the resources are taken from the command
+// attributes, if a value is not given, the default value of the device command resource or device resource
+// is used. Only the writable device commands and device resources are allowed. The values are in the same space
+// as the readings, they are rejected if they are out of the resource minimum and maximum, and they are reverted
+// with the resource transformations before they are written.
+func ToWriteRequests(device v1alpha1.DeviceConfig, command Command) ([]WriteRequest, error) {
+	if deviceCommand := findDeviceCommand(command.DeviceCommand, device.Profile.DeviceCommands); deviceCommand != nil {
+		if !IsWritable(deviceCommand.ReadWrite) {
+			return nil, fmt.Errorf("the command %s of device %s is not writable", deviceCommand.Name, device.Name)
+		}
+
+		requests := []WriteRequest{}
+		for _, commandResource := range deviceCommand.Resources {
+			res := FindDeviceResource(commandResource.DeviceResource, device.Profile.DeviceResources)
+			if res == nil {
+				return nil, fmt.Errorf("the resource %s of command %s is not found in device %s",
+					commandResource.DeviceResource, deviceCommand.Name, device.Name)
+			}
+
+			req, err := toWriteRequest(device.Name, *res, command.Attributes, commandResource.DefaultValue)
+			if err != nil {
+				return nil, err
+			}
+
+			requests = append(requests, *req)
+		}
+
+		return requests, nil
+	}
+
+	res := FindDeviceResource(command.DeviceCommand, device.Profile.DeviceResources)
+	if res == nil {
+		return nil, fmt.Errorf("the command %s is not found in device %s", command.DeviceCommand, device.Name)
+	}
+
+	req, err := toWriteRequest(device.Name, *res, command.Attributes, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return []WriteRequest{*req}, nil
+}
+
+// IsWritable returns true if the permission allows to write, "W", "RW" and "WR" are writable, the permission is
+// DefaultReadWrite if it is not set
+func IsWritable(readWrite v1alpha1.ReadWrite) bool {
+	return strings.Contains(toPermission(readWrite), "W")
+}
+
+// IsReadable returns true if the permission allows to read, "R", "RW" and "WR" are readable, the permission is
+// DefaultReadWrite if it is not set
+func IsReadable(readWrite v1alpha1.ReadWrite) bool {
+	return strings.Contains(toPermission(readWrite), "R")
+}
+
+func toPermission(readWrite v1alpha1.ReadWrite) string {
+	if len(readWrite) == 0 {
+		return DefaultReadWrite
+	}
+	return strings.ToUpper(string(readWrite))
+}
+
+func toWriteRequest(deviceName string, res v1alpha1.DeviceResource, attrs Attributes, defaultValue string) (*WriteRequest, error) {
+	if !IsWritable(res.Properties.ReadWrite) {
+		return nil, fmt.Errorf("the resource %s of device %s is not writable", res.Name, deviceName)
+	}
+
+	var reading interface{}
+	switch {
+	case attrs[res.Name] != nil:
+		reading = attrs[res.Name]
+	case len(defaultValue) != 0:
+		reading = defaultValue
+	case len(res.Properties.DefaultValue) != 0:
+		reading = res.Properties.DefaultValue
+	default:
+		return nil, fmt.Errorf("no value is given for the resource %s of device %s", res.Name, deviceName)
+	}
+
+	// the value is in the same space as the readings, so it is validated with the resource minimum and maximum,
+	// then it is reverted to the raw value of the device
+	if err := validateReading(res, reading); err != nil {
+		return nil, err
+	}
+
+	raw, err := inverseTransform(res, reading)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := toValue(res, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WriteRequest{Resource: res, Value: val}, nil
+}
+
+func findDeviceCommand(name string, commands []v1alpha1.DeviceCommand) *v1alpha1.DeviceCommand {
+	for _, cmd := range commands {
+		if cmd.Name == name {
+			return &cmd
+		}
+	}
+
+	return nil
+}
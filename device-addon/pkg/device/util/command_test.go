@@ -0,0 +1,155 @@
+package util
+
+import (
+	"math"
+	"testing"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+)
+
+func newFloat64(val float64) *float64 {
+	return &val
+}
+
+func newInt64(val int64) *int64 {
+	return &val
+}
+
+func newUint64(val uint64) *uint64 {
+	return &val
+}
+
+func TestToWriteRequests(t *testing.T) {
+	cases := []struct {
+		name        string
+		properties  v1alpha1.ResourceProperties
+		value       interface{}
+		expected    interface{}
+		expectedErr bool
+	}{
+		{
+			name:       "no transformation",
+			properties: v1alpha1.ResourceProperties{ReadWrite: "RW", ValueType: ValueTypeInt16},
+			value:      float64(10),
+			expected:   int16(10),
+		},
+		{
+			name: "scale and offset",
+			properties: v1alpha1.ResourceProperties{
+				ReadWrite: "RW", ValueType: ValueTypeFloat32, Scale: newFloat64(0.1), Offset: newFloat64(-40)},
+			value:    float64(-18.5),
+			expected: float32(215),
+		},
+		{
+			name: "integer is rounded",
+			properties: v1alpha1.ResourceProperties{
+				ReadWrite: "W", ValueType: ValueTypeUint16, Scale: newFloat64(0.1)},
+			value:    float64(21.5),
+			expected: uint16(215),
+		},
+		{
+			name: "base",
+			properties: v1alpha1.ResourceProperties{
+				ReadWrite: "RW", ValueType: ValueTypeFloat64, Base: newFloat64(10)},
+			value:    float64(1000),
+			expected: float64(3),
+		},
+		{
+			name: "shift and mask",
+			properties: v1alpha1.ResourceProperties{
+				ReadWrite: "RW", ValueType: ValueTypeUint16, Mask: newUint64(0x0F), Shift: newInt64(4)},
+			value:    float64(0xA0),
+			expected: uint16(0x0A),
+		},
+		{
+			name: "less than minimum",
+			properties: v1alpha1.ResourceProperties{
+				ReadWrite: "RW", ValueType: ValueTypeFloat32, Minimum: newFloat64(5)},
+			value:       float64(4.9),
+			expectedErr: true,
+		},
+		{
+			name: "greater than maximum",
+			properties: v1alpha1.ResourceProperties{
+				ReadWrite: "RW", ValueType: ValueTypeFloat32, Scale: newFloat64(0.1), Maximum: newFloat64(30)},
+			value:       "30.5",
+			expectedErr: true,
+		},
+		{
+			name: "raw value is out of the value type",
+			properties: v1alpha1.ResourceProperties{
+				ReadWrite: "RW", ValueType: ValueTypeUint8, Scale: newFloat64(0.1)},
+			value:       float64(30),
+			expectedErr: true,
+		},
+		{
+			name:        "read only",
+			properties:  v1alpha1.ResourceProperties{ReadWrite: "R", ValueType: ValueTypeInt16},
+			value:       float64(10),
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			device := v1alpha1.DeviceConfig{
+				Name: "test",
+				Profile: v1alpha1.DeviceProfileSpec{
+					DeviceResources: []v1alpha1.DeviceResource{{Name: "res", Properties: c.properties}},
+				},
+			}
+
+			requests, err := ToWriteRequests(device, Command{
+				DeviceName:    "test",
+				DeviceCommand: "res",
+				Attributes:    Attributes{"res": c.value},
+			})
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected an error, but got %v", requests)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if expected, ok := c.expected.(float64); ok {
+				if actual, ok := requests[0].Value.(float64); !ok || math.Abs(actual-expected) > 1e-9 {
+					t.Errorf("expected %v, but got %v(%T)", expected, requests[0].Value, requests[0].Value)
+				}
+				return
+			}
+
+			if requests[0].Value != c.expected {
+				t.Errorf("expected %v(%T), but got %v(%T)", c.expected, c.expected, requests[0].Value, requests[0].Value)
+			}
+		})
+	}
+}
+
+func TestWriteAndRead(t *testing.T) {
+	res := v1alpha1.DeviceResource{
+		Name: "res",
+		Properties: v1alpha1.ResourceProperties{
+			ReadWrite: "RW", ValueType: ValueTypeFloat64, Scale: newFloat64(0.01), Offset: newFloat64(2)},
+	}
+
+	requests, err := ToWriteRequests(v1alpha1.DeviceConfig{
+		Name:    "test",
+		Profile: v1alpha1.DeviceProfileSpec{DeviceResources: []v1alpha1.DeviceResource{res}},
+	}, Command{DeviceName: "test", DeviceCommand: "res", Attributes: Attributes{"res": 23.45}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the raw value that is written is read back as the written value
+	result, err := NewResult(res, requests[0].Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if math.Abs(result.Value.(float64)-23.45) > 1e-9 {
+		t.Errorf("expected 23.45, but got %v", result.Value)
+	}
+}
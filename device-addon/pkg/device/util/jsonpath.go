@@ -0,0 +1,53 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LookupJSONPath returns the value that is selected by the path, the path is a dot separated field names with
+// the optional array indexes, e.g. $.sensors[0].temperature, nil is returned if the value does not exist
+func LookupJSONPath(data interface{}, path string) (interface{}, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if len(path) == 0 {
+		return data, nil
+	}
+
+	current := data
+	for _, field := range strings.Split(path, ".") {
+		name := field
+		indexes := []int{}
+		if i := strings.Index(field, "["); i >= 0 {
+			name = field[:i]
+			for _, part := range strings.Split(field[i:], "[")[1:] {
+				if !strings.HasSuffix(part, "]") {
+					return nil, fmt.Errorf("invalid json path %s", path)
+				}
+				index, err := strconv.Atoi(strings.TrimSuffix(part, "]"))
+				if err != nil {
+					return nil, fmt.Errorf("invalid json path %s, %v", path, err)
+				}
+				indexes = append(indexes, index)
+			}
+		}
+
+		if len(name) != 0 {
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, nil
+			}
+			current = obj[name]
+		}
+
+		for _, index := range indexes {
+			array, ok := current.([]interface{})
+			if !ok || index < 0 || index >= len(array) {
+				return nil, nil
+			}
+			current = array[index]
+		}
+	}
+
+	return current, nil
+}
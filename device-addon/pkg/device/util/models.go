@@ -29,19 +29,62 @@ const (
 	ValueTypeObject       = "Object"
 )
 
+// The permissions of the device resources and commands
+const (
+	ReadWriteR  = "R"
+	ReadWriteW  = "W"
+	ReadWriteRW = "RW"
+	ReadWriteWR = "WR"
+
+	// DefaultReadWrite is the permission of the device resources and commands whose permission is not set
+	DefaultReadWrite = ReadWriteRW
+)
+
+// ValueTypes are the supported value types of the device resources
+var ValueTypes = []string{
+	ValueTypeBool, ValueTypeString,
+	ValueTypeUint8, ValueTypeUint16, ValueTypeUint32, ValueTypeUint64,
+	ValueTypeInt8, ValueTypeInt16, ValueTypeInt32, ValueTypeInt64,
+	ValueTypeFloat32, ValueTypeFloat64,
+	ValueTypeBinary,
+	ValueTypeBoolArray, ValueTypeStringArray,
+	ValueTypeUint8Array, ValueTypeUint16Array, ValueTypeUint32Array, ValueTypeUint64Array,
+	ValueTypeInt8Array, ValueTypeInt16Array, ValueTypeInt32Array, ValueTypeInt64Array,
+	ValueTypeFloat32Array, ValueTypeFloat64Array,
+	ValueTypeObject,
+}
+
+// ReadWrites are the supported permissions of the device resources and commands
+var ReadWrites = []string{ReadWriteR, ReadWriteW, ReadWriteRW, ReadWriteWR}
+
 type ConfigProperties map[string]any
 
 type Attributes map[string]any
 
 type Command struct {
-	// refer to DeviceProfile.DeviceCommand.Name
-	DeviceCommand string     `yaml:"DeviceCommand"`
-	Attributes    Attributes `yaml:"attributes"`
+	// the name of the device that the command is sent to
+	DeviceName string `yaml:"deviceName" json:"deviceName"`
+	// refer to DeviceProfile.DeviceCommand.Name or DeviceProfile.DeviceResource.Name
+	DeviceCommand string `yaml:"DeviceCommand" json:"deviceCommand"`
+	// the values of the device resources, the key is the device resource name
+	Attributes Attributes `yaml:"attributes" json:"attributes"`
 }
 
+// CommandHandler handles the command that is received from the message bus
+type CommandHandler func(command Command) error
+
 type Result struct {
 	Name            string      `json:"name"`
 	Value           interface{} `json:"value"`
 	Type            string      `json:"type"`
 	CreateTimestamp int64       `json:"createTimestamp"`
+	// MediaType is the media type of a binary value, e.g. image/jpeg
+	MediaType string  `json:"mediaType,omitempty"`
+	Alarms    []Alarm `json:"alarms,omitempty"`
+}
+
+// Alarm represents an abnormal event of a device resource reading
+type Alarm struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
 }
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	PayloadFormatJSONObj = "jsonObj"
+	PayloadFormatJSONMap = "jsonMap"
+)
+
+// PayloadFunc converts a result to the payload that is published to a message bus
+type PayloadFunc func(Result) []byte
+
+// GetPayloadFunc returns the payload func of a payload format, the jsonMap is used if the format is empty
+func GetPayloadFunc(format string) (PayloadFunc, error) {
+	switch format {
+	case PayloadFormatJSONObj:
+		return ToJSONObjPayload, nil
+	case PayloadFormatJSONMap, "":
+		return ToJSONMapPayload, nil
+	}
+
+	return nil, fmt.Errorf("unsupported payload format %s", format)
+}
+
+// ToJSONObjPayload converts a result to a json object with all of the result fields
+func ToJSONObjPayload(result Result) []byte {
+	result.Value = ToJSONValue(result)
+	payload, _ := json.Marshal(result)
+	return payload
+}
+
+// ToJSONMapPayload converts a result to a json map, the key is the result name and the value is the result value
+func ToJSONMapPayload(result Result) []byte {
+	payload, _ := json.Marshal(map[string]any{result.Name: ToJSONValue(result)})
+	return payload
+}
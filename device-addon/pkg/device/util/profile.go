@@ -0,0 +1,176 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+)
+
+// MergeProfile merges the overrides into the base profile, the device resources and commands of the overrides
+// replace the ones of the base profile with the same names, and the others are appended in order.
+func MergeProfile(base, overrides v1alpha1.DeviceProfileSpec) v1alpha1.DeviceProfileSpec {
+	merged := v1alpha1.DeviceProfileSpec{}
+
+	resources := map[string]v1alpha1.DeviceResource{}
+	for _, res := range overrides.DeviceResources {
+		resources[res.Name] = res
+	}
+	for _, res := range base.DeviceResources {
+		if override, ok := resources[res.Name]; ok {
+			merged.DeviceResources = append(merged.DeviceResources, override)
+			delete(resources, res.Name)
+			continue
+		}
+		merged.DeviceResources = append(merged.DeviceResources, res)
+	}
+	for _, res := range overrides.DeviceResources {
+		if _, ok := resources[res.Name]; ok {
+			merged.DeviceResources = append(merged.DeviceResources, res)
+		}
+	}
+
+	commands := map[string]v1alpha1.DeviceCommand{}
+	for _, cmd := range overrides.DeviceCommands {
+		commands[cmd.Name] = cmd
+	}
+	for _, cmd := range base.DeviceCommands {
+		if override, ok := commands[cmd.Name]; ok {
+			merged.DeviceCommands = append(merged.DeviceCommands, override)
+			delete(commands, cmd.Name)
+			continue
+		}
+		merged.DeviceCommands = append(merged.DeviceCommands, cmd)
+	}
+	for _, cmd := range overrides.DeviceCommands {
+		if _, ok := commands[cmd.Name]; ok {
+			merged.DeviceCommands = append(merged.DeviceCommands, cmd)
+		}
+	}
+
+	return merged
+}
+
+// ValidateProfile validates the device resources and commands of a device profile with the supported value
+// types and permissions, the base is the profile that the profile is merged into, the resources of the commands
+// can be the resources of the base
+func ValidateProfile(profile, base v1alpha1.DeviceProfileSpec, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	merged := MergeProfile(base, profile)
+
+	resources := sets.New[string]()
+	for i, res := range profile.DeviceResources {
+		resPath := fldPath.Child("deviceResources").Index(i)
+		switch {
+		case len(res.Name) == 0:
+			errs = append(errs, field.Required(resPath.Child("name"), ""))
+		case resources.Has(res.Name):
+			errs = append(errs, field.Duplicate(resPath.Child("name"), res.Name))
+		}
+		resources.Insert(res.Name)
+
+		errs = append(errs, validateResourceProperties(res, resPath.Child("properties"))...)
+	}
+
+	commands := sets.New[string]()
+	for i, cmd := range profile.DeviceCommands {
+		cmdPath := fldPath.Child("deviceCommands").Index(i)
+		switch {
+		case len(cmd.Name) == 0:
+			errs = append(errs, field.Required(cmdPath.Child("name"), ""))
+		case commands.Has(cmd.Name):
+			errs = append(errs, field.Duplicate(cmdPath.Child("name"), cmd.Name))
+		}
+		commands.Insert(cmd.Name)
+
+		errs = append(errs, validateReadWrite(cmd.ReadWrite, cmdPath.Child("readWrite"))...)
+
+		for j, cmdRes := range cmd.Resources {
+			cmdResPath := cmdPath.Child("resources").Index(j)
+			res := FindDeviceResource(cmdRes.DeviceResource, merged.DeviceResources)
+			if res == nil {
+				errs = append(errs, field.NotFound(cmdResPath.Child("deviceResource"), cmdRes.DeviceResource))
+				continue
+			}
+
+			if len(cmdRes.DefaultValue) == 0 {
+				continue
+			}
+
+			if _, err := toValue(*res, cmdRes.DefaultValue); err != nil {
+				errs = append(errs, field.Invalid(cmdResPath.Child("defaultValue"), cmdRes.DefaultValue, err.Error()))
+			}
+		}
+	}
+
+	return errs
+}
+
+// ValidateDesired validates the desired values of a device, the desired values should be the writable resources
+// of the device profile and they can be converted to the value types of the resources
+func ValidateDesired(desired map[string]interface{}, profile v1alpha1.DeviceProfileSpec, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	for _, name := range sets.List(sets.KeySet(desired)) {
+		res := FindDeviceResource(name, profile.DeviceResources)
+		if res == nil {
+			errs = append(errs, field.NotFound(fldPath.Key(name), name))
+			continue
+		}
+
+		if !IsWritable(res.Properties.ReadWrite) {
+			errs = append(errs, field.Invalid(fldPath.Key(name), desired[name],
+				fmt.Sprintf("the resource %s is not writable", name)))
+			continue
+		}
+
+		if _, err := toValue(*res, desired[name]); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Key(name), desired[name], err.Error()))
+		}
+	}
+
+	return errs
+}
+
+func validateResourceProperties(res v1alpha1.DeviceResource, fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	props := res.Properties
+
+	errs = append(errs, validateReadWrite(props.ReadWrite, fldPath.Child("readWrite"))...)
+
+	if !sets.New(ValueTypes...).Has(props.ValueType) {
+		errs = append(errs, field.NotSupported(fldPath.Child("valueType"), props.ValueType, ValueTypes))
+		// the other properties cannot be validated without the value type
+		return errs
+	}
+
+	if props.Minimum != nil && props.Maximum != nil && *props.Minimum > *props.Maximum {
+		errs = append(errs, field.Invalid(fldPath.Child("minimum"), *props.Minimum,
+			fmt.Sprintf("the minimum is greater than the maximum %v", *props.Maximum)))
+	}
+
+	if len(props.DefaultValue) != 0 {
+		if _, err := toValue(res, props.DefaultValue); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("defaultValue"), props.DefaultValue, err.Error()))
+		}
+	}
+
+	return errs
+}
+
+// validateReadWrite validates the permission, the permission is DefaultReadWrite if it is not set
+func validateReadWrite(readWrite v1alpha1.ReadWrite, fldPath *field.Path) field.ErrorList {
+	if len(readWrite) == 0 {
+		return nil
+	}
+
+	// the permissions are case insensitive, see IsWritable and IsReadable
+	if !sets.New(ReadWrites...).Has(strings.ToUpper(string(readWrite))) {
+		return field.ErrorList{field.NotSupported(fldPath, readWrite, ReadWrites)}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package util
+
+import (
+	"sync"
+	"time"
+)
+
+// DeviceState represents the live state of a device that is reported by its driver
+type DeviceState struct {
+	// Connected is true if the driver is connected to the device
+	Connected bool
+	// Subscribed is true if the driver is subscribed to the device data
+	Subscribed bool
+	// LastSeen is the time of the last reading from the device
+	LastSeen time.Time
+	// ReadingCount is the number of the readings that are received from the device
+	ReadingCount int64
+	// RejectedCount is the number of the readings that are rejected by the validation of their device resources
+	RejectedCount int64
+	// ErrorCount is the number of the errors that occurred when the driver handles the device
+	ErrorCount int64
+	// LastError is the last error that occurred when the driver handles the device
+	LastError string
+}
+
+// DeviceStates records the states of devices, it is used by the drivers to report the devices states
+type DeviceStates struct {
+	sync.RWMutex
+	states map[string]*DeviceState
+}
+
+func NewDeviceStates() *DeviceStates {
+	return &DeviceStates{
+		states: make(map[string]*DeviceState),
+	}
+}
+
+func (s *DeviceStates) SetConnected(deviceName string, connected bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	state := s.getOrCreate(deviceName)
+	state.Connected = connected
+	if !connected {
+		state.Subscribed = false
+	}
+}
+
+func (s *DeviceStates) SetSubscribed(deviceName string, subscribed bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.getOrCreate(deviceName).Subscribed = subscribed
+}
+
+// RecordReading records a reading is received from the device
+func (s *DeviceStates) RecordReading(deviceName string) {
+	s.Lock()
+	defer s.Unlock()
+
+	state := s.getOrCreate(deviceName)
+	state.LastSeen = time.Now()
+	state.ReadingCount++
+}
+
+// RecordError records an error that occurred when the driver handles the device, if the error is a
+// ValidationError, the reading is also recorded as rejected
+func (s *DeviceStates) RecordError(deviceName string, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	state := s.getOrCreate(deviceName)
+	state.ErrorCount++
+	if IsValidationError(err) {
+		state.RejectedCount++
+	}
+	if err != nil {
+		state.LastError = err.Error()
+	}
+}
+
+// Get returns a copy of the device state, nil is returned if the device state is not recorded
+func (s *DeviceStates) Get(deviceName string) *DeviceState {
+	s.RLock()
+	defer s.RUnlock()
+
+	state, ok := s.states[deviceName]
+	if !ok {
+		return nil
+	}
+
+	copied := *state
+	return &copied
+}
+
+func (s *DeviceStates) Remove(deviceName string) {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.states, deviceName)
+}
+
+func (s *DeviceStates) getOrCreate(deviceName string) *DeviceState {
+	state, ok := s.states[deviceName]
+	if !ok {
+		state = &DeviceState{}
+		s.states[deviceName] = state
+	}
+
+	return state
+}
@@ -0,0 +1,102 @@
+// Package testing provides the fake message bus and the device helpers that are shared by the tests of the drivers,
+// the poller and the equipment.
+package testing
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/device/util"
+)
+
+// FakeMsgBus records the readings that are published to it in the Results, the readings are dropped once the
+// Results is full, and they are failed to publish if the error of the message bus is set
+type FakeMsgBus struct {
+	sync.Mutex
+	Results chan util.Result
+	err     error
+}
+
+func NewFakeMsgBus() *FakeMsgBus {
+	return &FakeMsgBus{Results: make(chan util.Result, 100)}
+}
+
+func (b *FakeMsgBus) Start(ctx context.Context) error { return nil }
+
+func (b *FakeMsgBus) Stop(ctx context.Context) {}
+
+func (b *FakeMsgBus) ReceiveData(deviceName string, result util.Result) error {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.err != nil {
+		return b.err
+	}
+
+	select {
+	case b.Results <- result:
+	default:
+	}
+	return nil
+}
+
+func (b *FakeMsgBus) SendData(handler util.CommandHandler) error { return nil }
+
+// SetErr sets the error that is returned when a reading is published
+func (b *FakeMsgBus) SetErr(err error) {
+	b.Lock()
+	defer b.Unlock()
+	b.err = err
+}
+
+// Received returns the readings that are published and not received yet
+func (b *FakeMsgBus) Received() []util.Result {
+	var results []util.Result
+	for {
+		select {
+		case result := <-b.Results:
+			results = append(results, result)
+		default:
+			return results
+		}
+	}
+}
+
+// WaitForReadings waits for the readings of the resources, the values of the last readings are returned
+func WaitForReadings(t *testing.T, msgBus *FakeMsgBus, names ...string) map[string]interface{} {
+	readings := map[string]interface{}{}
+	timeout := time.After(10 * time.Second)
+	for len(readings) < len(names) {
+		select {
+		case result := <-msgBus.Results:
+			readings[result.Name] = result.Value
+		case <-timeout:
+			t.Fatalf("expected the readings of %v, but got %v", names, readings)
+		}
+	}
+	return readings
+}
+
+// NewDevice returns a device of the driver with the protocol properties and the resources
+func NewDevice(name, driverType string, protocolProperties map[string]interface{},
+	resources ...v1alpha1.DeviceResource) v1alpha1.DeviceConfig {
+	return v1alpha1.DeviceConfig{
+		Name:               name,
+		DriverType:         driverType,
+		ProtocolProperties: v1alpha1.Values{Data: protocolProperties},
+		Profile:            v1alpha1.DeviceProfileSpec{DeviceResources: resources},
+	}
+}
+
+// NewResource returns a device resource with the permission, the value type and the attributes
+func NewResource(name string, readWrite v1alpha1.ReadWrite, valueType string,
+	attributes map[string]interface{}) v1alpha1.DeviceResource {
+	return v1alpha1.DeviceResource{
+		Name:       name,
+		Properties: v1alpha1.ResourceProperties{ReadWrite: readWrite, ValueType: valueType},
+		Attributes: v1alpha1.Values{Data: attributes},
+	}
+}
@@ -0,0 +1,261 @@
+package util
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/spf13/cast"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+)
+
+const AlarmTypeAssertionFailed = "AssertionFailed"
+
+// ValidationError represents a reading is rejected by the validation of its device resource
+type ValidationError struct {
+	Resource string
+	Reason   string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("the reading of %s is invalid, %s", e.Resource, e.Reason)
+}
+
+// IsValidationError returns true if the error is a ValidationError
+func IsValidationError(err error) bool {
+	_, ok := err.(*ValidationError)
+	return ok
+}
+
+// transformReading applies the resource properties transformations to a numeric reading with the order:
+// mask, shift, base, scale and offset. The mask and shift are only applied to the integer readings, a
+// positive shift shifts the bits to left and a negative shift shifts the bits to right.
+func transformReading(resource v1alpha1.DeviceResource, reading interface{}) (interface{}, error) {
+	props := resource.Properties
+	if !IsNumericValueType(props.ValueType) || !hasTransformation(props) {
+		return reading, nil
+	}
+
+	value := reading
+	if props.Mask != nil || props.Shift != nil {
+		if !isIntegerReading(reading) {
+			return nil, fmt.Errorf("the mask and shift of %s only support integer readings, but got %T", resource.Name, reading)
+		}
+
+		raw, err := cast.ToUint64E(reading)
+		if err != nil {
+			// the negative integer is masked and shifted with its two's complement
+			signed, err := cast.ToInt64E(reading)
+			if err != nil {
+				return nil, fmt.Errorf(castError, resource.Name, err)
+			}
+			raw = uint64(signed)
+		}
+
+		if props.Mask != nil {
+			raw = raw & *props.Mask
+		}
+
+		if props.Shift != nil {
+			if *props.Shift > 0 {
+				raw = raw << uint64(*props.Shift)
+			} else {
+				raw = raw >> uint64(-*props.Shift)
+			}
+		}
+
+		value = raw
+	}
+
+	if props.Base == nil && props.Scale == nil && props.Offset == nil {
+		return value, nil
+	}
+
+	val, err := cast.ToFloat64E(value)
+	if err != nil {
+		return nil, fmt.Errorf(castError, resource.Name, err)
+	}
+
+	if props.Base != nil {
+		val = math.Pow(*props.Base, val)
+	}
+
+	if props.Scale != nil {
+		val = val * *props.Scale
+	}
+
+	if props.Offset != nil {
+		val = val + *props.Offset
+	}
+
+	return val, nil
+}
+
+// inverseTransform reverts the resource properties transformations of a numeric value to the raw value that is
+// written to the device, the transformations are reverted in the reverse order: offset, scale, base, shift and
+// mask. The raw value is rounded if the resource is an integer or it has a mask or shift.
+func inverseTransform(resource v1alpha1.DeviceResource, value interface{}) (interface{}, error) {
+	props := resource.Properties
+	if !IsNumericValueType(props.ValueType) || !hasTransformation(props) {
+		return value, nil
+	}
+
+	val, err := cast.ToFloat64E(value)
+	if err != nil {
+		return nil, fmt.Errorf(castError, resource.Name, err)
+	}
+
+	if props.Offset != nil {
+		val = val - *props.Offset
+	}
+
+	if props.Scale != nil {
+		if *props.Scale == 0 {
+			return nil, fmt.Errorf("the value of %s cannot be written with the scale 0", resource.Name)
+		}
+		val = val / *props.Scale
+	}
+
+	if props.Base != nil {
+		if *props.Base <= 0 || *props.Base == 1 || val <= 0 {
+			return nil, fmt.Errorf("the value %v of %s cannot be reverted with the base %v", val, resource.Name, *props.Base)
+		}
+		val = math.Log(val) / math.Log(*props.Base)
+	}
+
+	if props.Mask == nil && props.Shift == nil {
+		if isIntegerValueType(props.ValueType) {
+			return math.Round(val), nil
+		}
+		return val, nil
+	}
+
+	signed := int64(math.Round(val))
+	raw := uint64(signed)
+	if props.Shift != nil {
+		if *props.Shift > 0 {
+			raw = raw >> uint64(*props.Shift)
+		} else {
+			raw = raw << uint64(-*props.Shift)
+		}
+	}
+
+	if props.Mask != nil {
+		raw = raw & *props.Mask
+	}
+
+	if signed < 0 {
+		return int64(raw), nil
+	}
+	return raw, nil
+}
+
+// validateReading checks a numeric reading with the minimum and maximum of its resource
+func validateReading(resource v1alpha1.DeviceResource, reading interface{}) error {
+	props := resource.Properties
+	if props.Minimum == nil && props.Maximum == nil {
+		return nil
+	}
+
+	if elementType, ok := ElementValueType(props.ValueType); ok && IsNumericValueType(elementType) {
+		// the minimum and maximum are applied to each element of a numeric array
+		elements, err := toSlice(reading)
+		if err != nil {
+			return fmt.Errorf(castError, resource.Name, err)
+		}
+
+		for _, element := range elements {
+			if err := validateNumber(resource, element); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if !IsNumericValueType(props.ValueType) {
+		return nil
+	}
+
+	return validateNumber(resource, reading)
+}
+
+func validateNumber(resource v1alpha1.DeviceResource, reading interface{}) error {
+	props := resource.Properties
+	val, err := cast.ToFloat64E(reading)
+	if err != nil {
+		return fmt.Errorf(castError, resource.Name, err)
+	}
+
+	if props.Minimum != nil && val < *props.Minimum {
+		return &ValidationError{
+			Resource: resource.Name,
+			Reason:   fmt.Sprintf("%v is less than the minimum %v", val, *props.Minimum),
+		}
+	}
+
+	if props.Maximum != nil && val > *props.Maximum {
+		return &ValidationError{
+			Resource: resource.Name,
+			Reason:   fmt.Sprintf("%v is greater than the maximum %v", val, *props.Maximum),
+		}
+	}
+
+	return nil
+}
+
+// assertValue returns an alarm if the value does not match the assertion of its resource, the assertion is
+// only applied to the scalar values
+func assertValue(resource v1alpha1.DeviceResource, value interface{}) *Alarm {
+	assertion := resource.Properties.Assertion
+	if len(assertion) == 0 {
+		return nil
+	}
+
+	if _, ok := ElementValueType(resource.Properties.ValueType); ok || resource.Properties.ValueType == ValueTypeBinary {
+		return nil
+	}
+
+	actual := cast.ToString(value)
+	if actual == assertion {
+		return nil
+	}
+
+	return &Alarm{
+		Type:    AlarmTypeAssertionFailed,
+		Message: fmt.Sprintf("the value of %s is %s, but the assertion is %s", resource.Name, actual, assertion),
+	}
+}
+
+func hasTransformation(props v1alpha1.ResourceProperties) bool {
+	return props.Mask != nil || props.Shift != nil || props.Base != nil || props.Scale != nil || props.Offset != nil
+}
+
+func IsNumericValueType(valueType string) bool {
+	switch valueType {
+	case ValueTypeUint8, ValueTypeUint16, ValueTypeUint32, ValueTypeUint64,
+		ValueTypeInt8, ValueTypeInt16, ValueTypeInt32, ValueTypeInt64,
+		ValueTypeFloat32, ValueTypeFloat64:
+		return true
+	}
+
+	return false
+}
+
+func isIntegerValueType(valueType string) bool {
+	return IsNumericValueType(valueType) && valueType != ValueTypeFloat32 && valueType != ValueTypeFloat64
+}
+
+func isIntegerReading(reading interface{}) bool {
+	switch v := reading.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return true
+	case float32:
+		return float64(v) == math.Trunc(float64(v))
+	case float64:
+		// the numbers that are decoded from json are float64
+		return v == math.Trunc(v)
+	}
+
+	return false
+}
@@ -36,7 +36,47 @@ func ToConfigObj(configProperties map[string]interface{}, configObj any) error {
 	return json.Unmarshal(data, configObj)
 }
 
+// NewResult converts a reading to a result with its device resource properties, the reading is transformed
+// with the resource transformations firstly, then it is validated with the resource minimum and maximum, a
+// ValidationError is returned if the reading is out of the range. If the value does not match the resource
+// assertion, an alarm is added to the result.
 func NewResult(resource v1alpha1.DeviceResource, reading interface{}) (*Result, error) {
+	transformed, err := transformReading(resource, reading)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateReading(resource, transformed); err != nil {
+		return nil, err
+	}
+
+	val, err := toValue(resource, transformed)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Name:            resource.Name,
+		Type:            resource.Properties.ValueType,
+		Value:           val,
+		CreateTimestamp: time.Now().UnixNano(),
+	}
+
+	if result.Type == ValueTypeBinary {
+		result.MediaType = resource.Properties.MediaType
+		if len(result.MediaType) == 0 {
+			result.MediaType = DefaultBinaryMediaType
+		}
+	}
+
+	if alarm := assertValue(resource, val); alarm != nil {
+		result.Alarms = append(result.Alarms, *alarm)
+	}
+
+	return result, nil
+}
+
+func toValue(resource v1alpha1.DeviceResource, reading interface{}) (interface{}, error) {
 	var err error
 	valueType := resource.Properties.ValueType
 	if !checkValueInRange(valueType, reading) {
@@ -107,17 +147,23 @@ func NewResult(resource v1alpha1.DeviceResource, reading interface{}) (*Result,
 		}
 	case ValueTypeObject:
 		val = reading
+	case ValueTypeBinary:
+		val, err = toBinary(reading)
+		if err != nil {
+			return nil, fmt.Errorf(castError, resource.Name, err)
+		}
+	case ValueTypeBoolArray, ValueTypeStringArray,
+		ValueTypeUint8Array, ValueTypeUint16Array, ValueTypeUint32Array, ValueTypeUint64Array,
+		ValueTypeInt8Array, ValueTypeInt16Array, ValueTypeInt32Array, ValueTypeInt64Array,
+		ValueTypeFloat32Array, ValueTypeFloat64Array:
+		elementType, _ := ElementValueType(valueType)
+		return toArrayValue(resource, elementType, reading)
 	default:
 		return nil, fmt.Errorf("return result fail, none supported value type: %v", valueType)
 
 	}
 
-	return &Result{
-		Name:            resource.Name,
-		Type:            valueType,
-		Value:           val,
-		CreateTimestamp: time.Now().UnixNano(),
-	}, nil
+	return val, nil
 }
 
 func FindDeviceResource(name string, resources []v1alpha1.DeviceResource) *v1alpha1.DeviceResource {
@@ -0,0 +1,209 @@
+package util
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"open-cluster-management-io/addon-contrib/device-addon/pkg/apis/v1alpha1"
+)
+
+func TestNewResult(t *testing.T) {
+	cases := []struct {
+		name                  string
+		properties            v1alpha1.ResourceProperties
+		reading               interface{}
+		expected              interface{}
+		expectedMediaType     string
+		expectedAlarm         bool
+		expectedErr           bool
+		expectedValidationErr bool
+	}{
+		{
+			name:       "no transformation",
+			properties: v1alpha1.ResourceProperties{ValueType: ValueTypeInt16},
+			reading:    float64(-5),
+			expected:   int16(-5),
+		},
+		{
+			name: "scale and offset",
+			properties: v1alpha1.ResourceProperties{
+				ValueType: ValueTypeFloat64, Scale: newFloat64(0.1), Offset: newFloat64(-40)},
+			reading:  uint16(215),
+			expected: float64(-18.5),
+		},
+		{
+			name: "scaled value is cast to the value type",
+			properties: v1alpha1.ResourceProperties{
+				ValueType: ValueTypeFloat32, Scale: newFloat64(0.5)},
+			reading:  int32(43),
+			expected: float32(21.5),
+		},
+		{
+			name: "base",
+			properties: v1alpha1.ResourceProperties{
+				ValueType: ValueTypeFloat64, Base: newFloat64(10)},
+			reading:  float64(3),
+			expected: float64(1000),
+		},
+		{
+			name: "mask and shift to left",
+			properties: v1alpha1.ResourceProperties{
+				ValueType: ValueTypeUint16, Mask: newUint64(0x0F), Shift: newInt64(4)},
+			reading:  uint16(0xA5),
+			expected: uint16(0x50),
+		},
+		{
+			name: "shift to right",
+			properties: v1alpha1.ResourceProperties{
+				ValueType: ValueTypeUint16, Shift: newInt64(-4)},
+			reading:  float64(0xA0),
+			expected: uint16(0x0A),
+		},
+		{
+			name: "mask of a fractional reading",
+			properties: v1alpha1.ResourceProperties{
+				ValueType: ValueTypeUint16, Mask: newUint64(0x0F)},
+			reading:     float64(1.5),
+			expectedErr: true,
+		},
+		{
+			name: "less than minimum",
+			properties: v1alpha1.ResourceProperties{
+				ValueType: ValueTypeFloat64, Minimum: newFloat64(5)},
+			reading:               float64(4.9),
+			expectedErr:           true,
+			expectedValidationErr: true,
+		},
+		{
+			name: "greater than maximum after scale",
+			properties: v1alpha1.ResourceProperties{
+				ValueType: ValueTypeFloat64, Scale: newFloat64(0.1), Maximum: newFloat64(30)},
+			reading:               uint16(400),
+			expectedErr:           true,
+			expectedValidationErr: true,
+		},
+		{
+			name: "between minimum and maximum",
+			properties: v1alpha1.ResourceProperties{
+				ValueType: ValueTypeInt32, Minimum: newFloat64(-10), Maximum: newFloat64(10)},
+			reading:  "10",
+			expected: int32(10),
+		},
+		{
+			name:        "out of the value type",
+			properties:  v1alpha1.ResourceProperties{ValueType: ValueTypeUint8},
+			reading:     float64(300),
+			expectedErr: true,
+		},
+		{
+			name:       "numeric array",
+			properties: v1alpha1.ResourceProperties{ValueType: ValueTypeInt16Array},
+			reading:    []interface{}{float64(1), float64(-2)},
+			expected:   []int16{1, -2},
+		},
+		{
+			name:       "json array",
+			properties: v1alpha1.ResourceProperties{ValueType: ValueTypeBoolArray},
+			reading:    "[true, false]",
+			expected:   []bool{true, false},
+		},
+		{
+			name:       "uint8 array",
+			properties: v1alpha1.ResourceProperties{ValueType: ValueTypeUint8Array},
+			reading:    []uint16{1, 255},
+			expected:   []uint8{1, 255},
+		},
+		{
+			name: "array element greater than maximum",
+			properties: v1alpha1.ResourceProperties{
+				ValueType: ValueTypeFloat64Array, Maximum: newFloat64(5)},
+			reading:               []float64{1, 10},
+			expectedErr:           true,
+			expectedValidationErr: true,
+		},
+		{
+			name:        "invalid array element",
+			properties:  v1alpha1.ResourceProperties{ValueType: ValueTypeInt16Array},
+			reading:     []interface{}{float64(1), "a"},
+			expectedErr: true,
+		},
+		{
+			name:        "not an array",
+			properties:  v1alpha1.ResourceProperties{ValueType: ValueTypeInt16Array},
+			reading:     "1",
+			expectedErr: true,
+		},
+		{
+			name:              "base64 binary",
+			properties:        v1alpha1.ResourceProperties{ValueType: ValueTypeBinary},
+			reading:           "aGVsbG8=",
+			expected:          []byte("hello"),
+			expectedMediaType: DefaultBinaryMediaType,
+		},
+		{
+			name:              "binary with media type",
+			properties:        v1alpha1.ResourceProperties{ValueType: ValueTypeBinary, MediaType: "image/png"},
+			reading:           []byte{0x89, 0x50},
+			expected:          []byte{0x89, 0x50},
+			expectedMediaType: "image/png",
+		},
+		{
+			name:        "invalid base64 binary",
+			properties:  v1alpha1.ResourceProperties{ValueType: ValueTypeBinary},
+			reading:     "not base64!",
+			expectedErr: true,
+		},
+		{
+			name:       "assertion is met",
+			properties: v1alpha1.ResourceProperties{ValueType: ValueTypeString, Assertion: "ok"},
+			reading:    "ok",
+			expected:   "ok",
+		},
+		{
+			name:          "assertion is failed",
+			properties:    v1alpha1.ResourceProperties{ValueType: ValueTypeString, Assertion: "ok"},
+			reading:       "fault",
+			expected:      "fault",
+			expectedAlarm: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result, err := NewResult(v1alpha1.DeviceResource{Name: "res", Properties: c.properties}, c.reading)
+			if c.expectedErr {
+				if err == nil {
+					t.Fatalf("expected an error, but got %v", result)
+				}
+				if c.expectedValidationErr != IsValidationError(err) {
+					t.Errorf("expected the validation error %v, but got %v", c.expectedValidationErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Name != "res" || result.Type != c.properties.ValueType || result.CreateTimestamp == 0 {
+				t.Errorf("unexpected result %v", result)
+			}
+
+			if expected, ok := c.expected.(float64); ok {
+				if actual, ok := result.Value.(float64); !ok || math.Abs(actual-expected) > 1e-9 {
+					t.Errorf("expected %v, but got %v(%T)", expected, result.Value, result.Value)
+				}
+			} else if !reflect.DeepEqual(result.Value, c.expected) {
+				t.Errorf("expected %v(%T), but got %v(%T)", c.expected, c.expected, result.Value, result.Value)
+			}
+
+			if result.MediaType != c.expectedMediaType {
+				t.Errorf("expected the media type %q, but got %q", c.expectedMediaType, result.MediaType)
+			}
+
+			if c.expectedAlarm != (len(result.Alarms) == 1 && result.Alarms[0].Type == AlarmTypeAssertionFailed) {
+				t.Errorf("expected the alarm %v, but got %v", c.expectedAlarm, result.Alarms)
+			}
+		})
+	}
+}
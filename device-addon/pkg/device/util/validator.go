@@ -9,10 +9,15 @@ import (
 func checkValueInRange(valueType string, reading interface{}) bool {
 	isValid := false
 
-	if valueType == ValueTypeString || valueType == ValueTypeBool || valueType == ValueTypeObject {
+	if valueType == ValueTypeString || valueType == ValueTypeBool || valueType == ValueTypeObject ||
+		valueType == ValueTypeBinary {
 		return true
 	}
 
+	if elementType, ok := ElementValueType(valueType); ok {
+		return checkArrayValueInRange(elementType, reading)
+	}
+
 	if valueType == ValueTypeInt8 || valueType == ValueTypeInt16 ||
 		valueType == ValueTypeInt32 || valueType == ValueTypeInt64 {
 		val := cast.ToInt64(reading)
@@ -33,6 +38,22 @@ func checkValueInRange(valueType string, reading interface{}) bool {
 	return isValid
 }
 
+// checkArrayValueInRange checks every element of an array reading is in the range of the element type
+func checkArrayValueInRange(elementType string, reading interface{}) bool {
+	elements, err := toSlice(reading)
+	if err != nil {
+		return false
+	}
+
+	for _, element := range elements {
+		if !checkValueInRange(elementType, element) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func checkUintValueRange(valueType string, val uint64) bool {
 	var isValid = false
 	switch valueType {